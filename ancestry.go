@@ -0,0 +1,52 @@
+package dgraph
+
+func (d *directedGraph[NodeType]) Ancestors(nodeID string) (map[string]Node[NodeType], error) {
+	return d.reachableNodes(nodeID, d.connectionsToNode)
+}
+
+func (d *directedGraph[NodeType]) Descendants(nodeID string) (map[string]Node[NodeType], error) {
+	return d.reachableNodes(nodeID, d.connectionsFromNode)
+}
+
+// reachableNodes returns all nodes reachable from the specified node by following the connections in the map,
+// which is either connectionsToNode or connectionsFromNode. The start node is only included if it is part of a
+// cycle.
+func (d *directedGraph[NodeType]) reachableNodes(
+	nodeID string,
+	connections map[string]map[string]struct{},
+) (map[string]Node[NodeType], error) {
+	nodeID = d.config.normalizeID(nodeID)
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if _, ok := d.nodes[nodeID]; !ok {
+		return nil, d.nodeNotFound(nodeID)
+	}
+	reachable := d.reachableNodeIDs(nodeID, connections)
+	result := make(map[string]Node[NodeType], len(reachable))
+	for reachableNodeID := range reachable {
+		result[reachableNodeID] = d.nodes[reachableNodeID]
+	}
+	return result, nil
+}
+
+// reachableNodeIDs returns the IDs of the nodes reachable from the existing node over the connections in the map.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) reachableNodeIDs(
+	nodeID string,
+	connections map[string]map[string]struct{},
+) map[string]struct{} {
+	result := map[string]struct{}{}
+	queue := []string{nodeID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for nextNodeID := range connections[current] {
+			if _, visited := result[nextNodeID]; visited {
+				continue
+			}
+			result[nextNodeID] = struct{}{}
+			queue = append(queue, nextNodeID)
+		}
+	}
+	return result
+}
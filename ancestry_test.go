@@ -0,0 +1,43 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_AncestorsDescendants(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	// a -> b -> d, a -> c -> d, e is unrelated.
+	for _, connection := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(connection[1]))
+		assert.NoError(t, n.ConnectDependency(connection[0], dgraph.AndDependency))
+	}
+	keys := func(nodes map[string]dgraph.Node[string], err error) []string {
+		assert.NoError(t, err)
+		var result []string
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			if _, ok := nodes[id]; ok {
+				result = append(result, id)
+			}
+		}
+		return result
+	}
+	assert.Equals(t, keys(d.Ancestors("d")), []string{"a", "b", "c"})
+	assert.Equals(t, keys(d.Descendants("a")), []string{"b", "c", "d"})
+	assert.Equals(t, keys(d.Descendants("b")), []string{"d"})
+	assert.Equals(t, len(keys(d.Ancestors("e"))), 0)
+
+	// A node in a cycle is its own ancestor.
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ConnectDependency("d", dgraph.AndDependency))
+	assert.Equals(t, keys(d.Ancestors("b")), []string{"a", "b", "c", "d"})
+
+	_, err := d.Descendants("missing")
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+}
@@ -0,0 +1,71 @@
+package dgraph
+
+import (
+	"maps"
+	"slices"
+)
+
+func (n *node[NodeType]) Annotate(annotations ...string) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	n.annotations = append(n.annotations, annotations...)
+	n.dg.advanceGeneration()
+	return nil
+}
+
+func (n *node[NodeType]) Annotations() []string {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return slices.Clone(n.annotations)
+}
+
+func (n *node[NodeType]) SetDescription(description string) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	n.description = description
+	n.dg.advanceGeneration()
+	return nil
+}
+
+func (n *node[NodeType]) Description() string {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.description
+}
+
+func (n *node[NodeType]) SetAttr(key string, value string) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	if n.attributes == nil {
+		n.attributes = map[string]string{}
+	}
+	n.attributes[key] = value
+	n.dg.advanceGeneration()
+	return nil
+}
+
+func (n *node[NodeType]) Attr(key string) (string, bool) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	value, ok := n.attributes[key]
+	return value, ok
+}
+
+func (n *node[NodeType]) Attrs() map[string]string {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	result := maps.Clone(n.attributes)
+	if result == nil {
+		result = map[string]string{}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package dgraph
+
+import "slices"
+
+func (d *directedGraph[NodeType]) AddNodes(items map[string]NodeType) error {
+	ids := make(map[string]string, len(items))
+	for id := range items {
+		ids[id] = d.config.normalizeID(id)
+	}
+	d.lock.Lock()
+	defer d.unlock()
+	// Validate all nodes first, so that the graph is left unchanged if any of them can't be added.
+	order := sortedKeys(ids)
+	seen := make(map[string]struct{}, len(items))
+	for _, id := range order {
+		normalizedID := ids[id]
+		if d.config.idPattern != nil && !d.config.idPattern.MatchString(normalizedID) {
+			return &ErrInvalidNodeID{normalizedID, d.config.idPattern.String()}
+		}
+		if _, ok := d.nodes[normalizedID]; ok {
+			return ErrNodeAlreadyExists{normalizedID}
+		}
+		if _, ok := seen[normalizedID]; ok {
+			return ErrNodeAlreadyExists{normalizedID}
+		}
+		seen[normalizedID] = struct{}{}
+	}
+	for _, id := range order {
+		if _, err := d.addNode(ids[id], items[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *directedGraph[NodeType]) ConnectDependencies(toID string, dependencies map[string]DependencyType) error {
+	toID = d.config.normalizeID(toID)
+	fromIDs := make(map[string]string, len(dependencies))
+	for fromID := range dependencies {
+		fromIDs[fromID] = d.config.normalizeID(fromID)
+	}
+	d.lock.Lock()
+	defer d.unlock()
+	// Validate all connections first, so that the graph is left unchanged if any of them is invalid.
+	order := sortedKeys(fromIDs)
+	seen := make(map[string]struct{}, len(dependencies))
+	for _, fromID := range order {
+		normalizedID := fromIDs[fromID]
+		if err := d.validateConnection(normalizedID, toID, dependencies[fromID]); err != nil {
+			return err
+		}
+		if _, ok := seen[normalizedID]; ok {
+			return &ErrConnectionAlreadyExists{normalizedID, toID}
+		}
+		seen[normalizedID] = struct{}{}
+	}
+	for _, fromID := range order {
+		if err := d.connect(fromIDs[fromID], toID, dependencies[fromID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_AddNodes(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoError(t, d.AddNodes(map[string]string{"a": "a", "b": "b", "c": "c"}))
+	assert.Equals(t, len(d.ListNodes()), 3)
+
+	// The graph is unchanged if any node already exists.
+	err := d.AddNodes(map[string]string{"d": "d", "b": "b"})
+	assert.InstanceOf[dgraph.ErrNodeAlreadyExists](t, err)
+	assert.Equals(t, len(d.ListNodes()), 3)
+}
+
+func TestDirectedGraph_ConnectDependencies(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoError(t, d.AddNodes(map[string]string{"a": "a", "b": "b", "c": "c"}))
+	assert.NoError(t, d.ConnectDependencies("c", map[string]dgraph.DependencyType{
+		"a": dgraph.AndDependency,
+		"b": dgraph.OrDependency,
+	}))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.AndDependency,
+		"b": dgraph.OrDependency,
+	})
+
+	// No connection is made if any of them is invalid.
+	err := d.ConnectDependencies("a", map[string]dgraph.DependencyType{
+		"b":       dgraph.AndDependency,
+		"missing": dgraph.AndDependency,
+	})
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.Equals(t, a.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+}
+
+func TestDirectedGraph_ConnectDependencies_ReadyNodeRejected(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithReadyNodeConnectionPolicy(dgraph.ReadyNodeConnectionReject))
+	assert.NoError(t, d.AddNodes(map[string]string{"target": "target"}))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, d.AddNodes(map[string]string{"a": "a", "b": "b"}))
+
+	// The optional dependency would be accepted on its own, but no connection is made since b is rejected.
+	err := d.ConnectDependencies("target", map[string]dgraph.DependencyType{
+		"a": dgraph.OptionalDependency,
+		"b": dgraph.AndDependency,
+	})
+	assert.InstanceOf[*dgraph.ErrNodeAlreadyReady](t, err)
+	target := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("target"))
+	assert.Equals(t, target.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+}
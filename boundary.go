@@ -0,0 +1,103 @@
+package dgraph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BoundaryContract declares how a child graph embedded into a parent graph, for example with Merge and
+// WithMergeIDPrefix, must be connected. The node IDs of the child are given without the prefix.
+type BoundaryContract struct {
+	// Inputs are the nodes of the child that must have an inbound connection from a node of the parent.
+	Inputs []string
+	// Outputs maps nodes of the child to the nodes of the parent that must depend on them.
+	Outputs map[string]string
+}
+
+// BoundaryViolationKind is the kind of a BoundaryViolation.
+type BoundaryViolationKind string
+
+const (
+	// BoundaryMissingNode means that a node declared by the contract does not exist.
+	BoundaryMissingNode BoundaryViolationKind = "missing-node"
+	// BoundaryUnconnectedInput means that an input of the child has no inbound connection from the parent.
+	BoundaryUnconnectedInput BoundaryViolationKind = "unconnected-input"
+	// BoundaryUnmappedOutput means that an output of the child is not connected to its parent node.
+	BoundaryUnmappedOutput BoundaryViolationKind = "unmapped-output"
+)
+
+// BoundaryViolation is a single broken boundary contract found by ValidateBoundary.
+type BoundaryViolation struct {
+	Kind BoundaryViolationKind
+	// NodeID is the ID of the node of the child, including the prefix, or of the missing parent node.
+	NodeID string
+	// ParentNodeID is the parent node an output should be connected to. It is empty for inputs.
+	ParentNodeID string
+}
+
+func (v BoundaryViolation) String() string {
+	switch v.Kind {
+	case BoundaryMissingNode:
+		return fmt.Sprintf("node %q does not exist", v.NodeID)
+	case BoundaryUnconnectedInput:
+		return fmt.Sprintf("input %q has no inbound connection from the parent graph", v.NodeID)
+	default:
+		return fmt.Sprintf("output %q is not connected to node %q", v.NodeID, v.ParentNodeID)
+	}
+}
+
+func (d *directedGraph[NodeType]) ValidateBoundary(prefix string, contract BoundaryContract) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	var violations []BoundaryViolation
+	inputs := slices.Clone(contract.Inputs)
+	slices.Sort(inputs)
+	for _, input := range inputs {
+		nodeID := d.config.normalizeID(prefix + input)
+		if _, ok := d.nodes[nodeID]; !ok {
+			violations = append(violations, BoundaryViolation{Kind: BoundaryMissingNode, NodeID: nodeID})
+			continue
+		}
+		connected := false
+		for fromNodeID := range d.connectionsToNode[nodeID] {
+			if !strings.HasPrefix(fromNodeID, prefix) {
+				connected = true
+				break
+			}
+		}
+		if !connected {
+			violations = append(violations, BoundaryViolation{Kind: BoundaryUnconnectedInput, NodeID: nodeID})
+		}
+	}
+	outputs := make([]string, 0, len(contract.Outputs))
+	for output := range contract.Outputs {
+		outputs = append(outputs, output)
+	}
+	slices.Sort(outputs)
+	for _, output := range outputs {
+		nodeID := d.config.normalizeID(prefix + output)
+		parentNodeID := d.config.normalizeID(contract.Outputs[output])
+		missing := false
+		for _, id := range []string{nodeID, parentNodeID} {
+			if _, ok := d.nodes[id]; !ok {
+				violations = append(violations, BoundaryViolation{Kind: BoundaryMissingNode, NodeID: id})
+				missing = true
+			}
+		}
+		if missing {
+			continue
+		}
+		if _, ok := d.connectionsFromNode[nodeID][parentNodeID]; !ok {
+			violations = append(violations, BoundaryViolation{
+				Kind:         BoundaryUnmappedOutput,
+				NodeID:       nodeID,
+				ParentNodeID: parentNodeID,
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return &ErrBoundaryViolations{violations}
+	}
+	return nil
+}
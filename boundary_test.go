@@ -0,0 +1,48 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_ValidateBoundary(t *testing.T) {
+	d := dgraph.New[string]()
+	start := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("start", "start"))
+	finish := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("finish", "finish"))
+	assert.NoError(t, d.Merge(fragment(t, "child"), dgraph.WithMergeIDPrefix("child.")))
+	contract := dgraph.BoundaryContract{
+		Inputs:  []string{"input"},
+		Outputs: map[string]string{"outputs": "finish"},
+	}
+
+	err := d.ValidateBoundary("child.", contract)
+	var violations *dgraph.ErrBoundaryViolations
+	assert.Equals(t, errors.As(err, &violations), true)
+	assert.Equals(t, violations.Violations, []dgraph.BoundaryViolation{
+		{Kind: dgraph.BoundaryUnconnectedInput, NodeID: "child.input"},
+		{Kind: dgraph.BoundaryUnmappedOutput, NodeID: "child.outputs", ParentNodeID: "finish"},
+	})
+	assert.Contains(t, err.Error(), "boundary validation failed with 2 violations")
+
+	assert.NoError(t, start.Connect("child.input"))
+	assert.NoError(t, finish.ConnectDependency("child.outputs", dgraph.AndDependency))
+	assert.NoError(t, d.ValidateBoundary("child.", contract))
+}
+
+func TestDirectedGraph_ValidateBoundaryMissingNodes(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoError(t, d.Merge(fragment(t, "child"), dgraph.WithMergeIDPrefix("child.")))
+	err := d.ValidateBoundary("child.", dgraph.BoundaryContract{
+		Inputs:  []string{"missing"},
+		Outputs: map[string]string{"outputs": "finish"},
+	})
+	var violations *dgraph.ErrBoundaryViolations
+	assert.Equals(t, errors.As(err, &violations), true)
+	assert.Equals(t, violations.Violations, []dgraph.BoundaryViolation{
+		{Kind: dgraph.BoundaryMissingNode, NodeID: "child.missing"},
+		{Kind: dgraph.BoundaryMissingNode, NodeID: "finish"},
+	})
+}
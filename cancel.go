@@ -0,0 +1,61 @@
+package dgraph
+
+func (d *directedGraph[NodeType]) Cancel(nodeID string) error {
+	nodeID = d.config.normalizeID(nodeID)
+	d.lock.Lock()
+	defer d.unlock()
+	if _, ok := d.nodes[nodeID]; !ok {
+		return d.nodeNotFound(nodeID)
+	}
+	nodeIDs := d.reachableNodeIDs(nodeID, d.connectionsFromNode)
+	nodeIDs[nodeID] = struct{}{}
+	d.cancel(sortedKeys(nodeIDs))
+	return nil
+}
+
+func (d *directedGraph[NodeType]) CancelAll() {
+	d.lock.Lock()
+	defer d.unlock()
+	d.cancel(sortedKeys(d.nodes))
+}
+
+// cancel sets the status of the waiting nodes among the specified ones to Cancelled and delivers them through the
+// ready set. Cancelled nodes release their resources, since they need no processing beyond the cleanup, and no
+// longer wait for their dependencies. Their declared types are kept for ResetExecution.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) cancel(nodeIDs []string) {
+	var released, outputCancelled bool
+	for _, nodeID := range nodeIDs {
+		n := d.nodes[nodeID]
+		if n.status != Waiting {
+			continue
+		}
+		n.status = Cancelled
+		n.resolvedAt = d.config.clock()
+		n.ready = true
+		n.readyReason = ReadyCancelled
+		for dependencyNodeID := range n.outstandingDependencies {
+			if n.obviatedTypes == nil {
+				n.obviatedTypes = map[string]DependencyType{}
+			}
+			n.obviatedTypes[dependencyNodeID] = n.declaredDependencyType(dependencyNodeID)
+		}
+		clear(n.outstandingDependencies)
+		d.resolutionOrder = append(d.resolutionOrder, nodeID)
+		if d.releaseResources(n) {
+			released = true
+		}
+		d.emitNodeResolved(n, Cancelled)
+		// Nodes that are already in the ready set or held back from it are delivered with their new status.
+		d.pushReady(n)
+		outputCancelled = outputCancelled || n.output
+		d.advanceGeneration()
+	}
+	if released {
+		d.releaseHeldReady()
+	}
+	d.notifyInFlightChanged()
+	if outputCancelled {
+		d.notifyOutputResolved()
+	}
+}
@@ -0,0 +1,68 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Cancel(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, nodeID := range []string{"a", "b", "c", "d"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.CompletionAndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "d"})
+
+	assert.NoError(t, d.Cancel("a"))
+	// The popped node is delivered again, so that the executor can clean up.
+	assert.Equals(t, d.PopReadyNodesWithReasons(), map[string]dgraph.ReadyNodeInfo{
+		"a": {Status: dgraph.Cancelled, Reason: dgraph.ReadyCancelled},
+		"b": {Status: dgraph.Cancelled, Reason: dgraph.ReadyCancelled},
+		"c": {Status: dgraph.Cancelled, Reason: dgraph.ReadyCancelled},
+	})
+	assert.InstanceOf[dgraph.ErrNodeResolutionAlreadySet](t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.ResolutionOrder(), []string{"a", "b", "c"})
+
+	dNode := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("d"))
+	assert.NoError(t, dNode.ResolveNode(dgraph.Resolved))
+	d.CancelAll()
+	assert.Equals(t, dNode.ResolutionStatus(), dgraph.Resolved)
+	assert.Equals(t, d.StatusCounts(""), dgraph.StatusCounts{dgraph.Cancelled: 3, dgraph.Resolved: 1})
+
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, d.Cancel("e"))
+}
+
+func TestDirectedGraph_Cancel_DependencyResolved(t *testing.T) {
+	for _, status := range []dgraph.ResolutionStatus{dgraph.Resolved, dgraph.Unresolvable} {
+		d := dgraph.New[string]()
+		a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+		b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+		assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+		var resolved []string
+		d.OnNodeResolved(func(node dgraph.Node[string], status dgraph.ResolutionStatus) {
+			resolved = append(resolved, node.ID()+":"+string(status))
+		})
+		assert.NoError(t, d.PushStartingNodes())
+		assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+		assert.NoError(t, d.Cancel("b"))
+		assert.Equals(t, d.PopReadyNodesOrdered(), []string{"b"})
+
+		// The cancelled node no longer waits for its dependency, so it is not delivered again.
+		assert.NoError(t, a.ResolveNode(status))
+		assert.Equals(t, d.HasReadyNodes(), false)
+		assert.Equals(t, b.ReadyReason(), dgraph.ReadyCancelled)
+		assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+		assert.Equals(t, resolved, []string{"b:cancelled", "a:" + string(status)})
+
+		// ResetExecution restores the dependency.
+		d.ResetExecution()
+		assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+	}
+}
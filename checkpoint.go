@@ -0,0 +1,214 @@
+package dgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// savedState is the serialized form of the execution state used by SaveState and RestoreState.
+type savedState struct {
+	Started bool                 `json:"started,omitempty"`
+	Nodes   map[string]savedNode `json:"nodes"`
+	// ReadyNodes lists the nodes that are ready, but have not been popped yet, in the order they became ready.
+	ReadyNodes      []string `json:"ready_nodes,omitempty"`
+	ResolutionOrder []string `json:"resolution_order,omitempty"`
+	Generation      uint64   `json:"generation,omitempty"`
+}
+
+type savedNode struct {
+	Status      ResolutionStatus `json:"status"`
+	Ready       bool             `json:"ready,omitempty"`
+	ReadyReason ReadyReason      `json:"ready_reason,omitempty"`
+	// InFlight is true if the node was popped, but not resolved yet.
+	InFlight                bool                      `json:"in_flight,omitempty"`
+	Iterations              int                       `json:"iterations,omitempty"`
+	SatisfyingOrDependency  string                    `json:"satisfying_or_dependency,omitempty"`
+	OutstandingDependencies map[string]DependencyType `json:"outstanding_dependencies,omitempty"`
+	ResolvedDependencies    map[string]DependencyType `json:"resolved_dependencies,omitempty"`
+	FailedDependencies      map[string]DependencyType `json:"failed_dependencies,omitempty"`
+	ObviatedTypes           map[string]DependencyType `json:"obviated_types,omitempty"`
+}
+
+func (d *directedGraph[NodeType]) SaveState() ([]byte, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	result := savedState{
+		Started:         d.started,
+		Nodes:           make(map[string]savedNode, len(d.nodes)),
+		ResolutionOrder: d.resolutionOrder,
+		Generation:      d.generation,
+	}
+	for nodeID, n := range d.nodes {
+		result.Nodes[nodeID] = savedNode{
+			Status:                  n.status,
+			Ready:                   n.ready,
+			ReadyReason:             n.readyReason,
+			InFlight:                d.isInFlight(n),
+			Iterations:              n.iterations,
+			SatisfyingOrDependency:  n.satisfyingOrDependency,
+			OutstandingDependencies: n.outstandingDependencies,
+			ResolvedDependencies:    n.resolvedDependencies,
+			FailedDependencies:      n.failedDependencies,
+			ObviatedTypes:           n.obviatedTypes,
+		}
+	}
+	result.ReadyNodes = d.readyForProcessing.List()
+	for _, n := range d.heldReady {
+		result.ReadyNodes = append(result.ReadyNodes, n.id)
+	}
+	return json.Marshal(result)
+}
+
+func (d *directedGraph[NodeType]) RestoreState(data []byte) error {
+	var input savedState
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("failed to unmarshal state (%w)", err)
+	}
+	d.lock.Lock()
+	defer d.unlock()
+	saved, err := d.validateSavedState(input)
+	if err != nil {
+		return err
+	}
+
+	d.readyForProcessing.Clear()
+	d.clearHeldReady()
+	clear(d.changedNodes)
+	clear(d.resourcesInUse)
+	clear(d.pendingData)
+	clear(d.dependencyStats)
+	now := d.config.clock()
+	d.started = input.Started
+	d.startedAt = time.Time{}
+	if d.started {
+		d.startedAt = now
+	}
+	d.resolutionOrder = d.resolutionOrder[:0]
+	for _, nodeID := range input.ResolutionOrder {
+		d.resolutionOrder = append(d.resolutionOrder, d.config.normalizeID(nodeID))
+	}
+	for nodeID, n := range d.nodes {
+		s := saved[nodeID]
+		n.status = s.Status
+		n.ready = s.Ready
+		n.readyReason = s.ReadyReason
+		n.iterations = s.Iterations
+		n.satisfyingOrDependency = d.config.normalizeID(s.SatisfyingOrDependency)
+		n.outstandingDependencies = d.normalizeDependencies(s.OutstandingDependencies)
+		n.resolvedDependencies = d.normalizeDependencies(s.ResolvedDependencies)
+		n.failedDependencies = d.normalizeDependencies(s.FailedDependencies)
+		n.obviatedTypes = nil
+		if len(s.ObviatedTypes) > 0 {
+			n.obviatedTypes = d.normalizeDependencies(s.ObviatedTypes)
+		}
+		n.resolutionHistory = nil
+		n.satisfactionTrace = nil
+		n.unresolvableCause = nil
+		n.result = nil
+		n.holdsResources = false
+		n.readyAt = time.Time{}
+		n.poppedAt = time.Time{}
+		n.resolvedAt = time.Time{}
+		if n.ready {
+			n.readyAt = now
+		}
+		if n.status != Waiting {
+			n.resolvedAt = now
+		}
+		if s.InFlight {
+			// Nodes that were being processed keep holding their resources until they are resolved.
+			n.poppedAt = now
+			d.acquireResources(n)
+		}
+	}
+	for _, nodeID := range input.ReadyNodes {
+		n := d.nodes[d.config.normalizeID(nodeID)]
+		// The nodes were already reported as ready before the state was saved, so no listeners are called.
+		if d.readyLimitReached() || !d.acquireResources(n) {
+			d.holdReady(n)
+			continue
+		}
+		d.readyForProcessing.Add(n.id)
+	}
+	// The generation never goes backwards, so that snapshots taken before the restore are detected as stale.
+	d.generation = max(d.generation, input.Generation)
+	d.advanceGeneration()
+	d.notifyReadyChanged()
+	d.notifyInFlightChanged()
+	return nil
+}
+
+// validateSavedState checks that the saved state matches the nodes and connections of the graph and returns the
+// saved nodes by their normalized IDs.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) validateSavedState(input savedState) (map[string]savedNode, error) {
+	saved := make(map[string]savedNode, len(input.Nodes))
+	for nodeID, s := range input.Nodes {
+		nodeID = d.config.normalizeID(nodeID)
+		if _, ok := d.nodes[nodeID]; !ok {
+			return nil, &ErrTopologyMismatch{fmt.Sprintf("node %q of the saved state is missing from the graph", nodeID)}
+		}
+		switch s.Status {
+		case Waiting, Resolved, Unresolvable, Cancelled:
+		default:
+			return nil, &ErrInvalidResolutionStatus{nodeID, s.Status}
+		}
+		saved[nodeID] = s
+	}
+	nodeIDs := make([]string, 0, len(d.nodes))
+	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		s, ok := saved[nodeID]
+		if !ok {
+			return nil, &ErrTopologyMismatch{fmt.Sprintf("node %q is missing from the saved state", nodeID)}
+		}
+		dependencies := map[string]struct{}{}
+		for _, group := range []map[string]DependencyType{
+			s.OutstandingDependencies, s.ResolvedDependencies, s.FailedDependencies,
+		} {
+			for fromID := range group {
+				dependencies[d.config.normalizeID(fromID)] = struct{}{}
+			}
+		}
+		connections := d.connectionsToNode[nodeID]
+		for fromID := range dependencies {
+			if _, ok := connections[fromID]; !ok {
+				return nil, &ErrTopologyMismatch{fmt.Sprintf(
+					"the saved state has a connection from node %q to node %q, which is missing from the graph",
+					fromID, nodeID,
+				)}
+			}
+		}
+		for fromID := range connections {
+			if _, ok := dependencies[fromID]; !ok {
+				return nil, &ErrTopologyMismatch{fmt.Sprintf(
+					"the connection from node %q to node %q is missing from the saved state", fromID, nodeID,
+				)}
+			}
+		}
+	}
+	for _, nodeID := range input.ReadyNodes {
+		if _, ok := d.nodes[d.config.normalizeID(nodeID)]; !ok {
+			return nil, d.nodeNotFound(nodeID)
+		}
+	}
+	return saved, nil
+}
+
+// normalizeDependencies returns a copy of the dependencies with normalized node IDs.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) normalizeDependencies(
+	dependencies map[string]DependencyType,
+) map[string]DependencyType {
+	result := make(map[string]DependencyType, len(dependencies))
+	for nodeID, dependencyType := range dependencies {
+		result[d.config.normalizeID(nodeID)] = dependencyType
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// checkpointGraph builds a graph in which c depends on a and b, and d depends on c.
+func checkpointGraph(t *testing.T) dgraph.DirectedGraph[string] {
+	d := dgraph.New[string]()
+	for _, nodeID := range []string{"a", "b", "c", "d"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.AndDependency))
+	last := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("d"))
+	assert.NoError(t, last.ConnectDependency("c", dgraph.AndDependency))
+	return d
+}
+
+func TestDirectedGraph_SaveState(t *testing.T) {
+	original := checkpointGraph(t)
+	assert.NoError(t, original.PushStartingNodes())
+	assert.Equals(t, original.PopReadyNodesOrdered(), []string{"a", "b"})
+	a := assert.NoErrorR[dgraph.Node[string]](t)(original.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	state := assert.NoErrorR[[]byte](t)(original.SaveState())
+
+	// The engine restarts, rebuilds the graph from the workflow definition, and resumes.
+	d := checkpointGraph(t)
+	assert.NoError(t, d.RestoreState(state))
+	assert.Equals(t, d.ResolutionOrder(), []string{"a"})
+	a = assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.Equals(t, a.ResolutionStatus(), dgraph.Resolved)
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{"b": dgraph.AndDependency})
+	assert.Equals(t, c.ResolvedDependencies(), map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+	// b was being processed, so it is not reported as ready again.
+	assert.Equals(t, d.HasReadyNodes(), false)
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"c"})
+	assert.NoError(t, c.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"d"})
+}
+
+func TestDirectedGraph_RestoreStateReadyNodes(t *testing.T) {
+	original := checkpointGraph(t)
+	assert.NoError(t, original.PushStartingNodes())
+	state := assert.NoErrorR[[]byte](t)(original.SaveState())
+
+	d := checkpointGraph(t)
+	assert.NoError(t, d.RestoreState(state))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+	assert.Equals(t, d.HasReadyNodes(), false)
+}
+
+func TestDirectedGraph_RestoreStateTopologyMismatch(t *testing.T) {
+	original := checkpointGraph(t)
+	assert.NoError(t, original.PushStartingNodes())
+	state := assert.NoErrorR[[]byte](t)(original.SaveState())
+
+	d := checkpointGraph(t)
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	generation := d.Generation()
+	err := d.RestoreState(state)
+	var mismatch *dgraph.ErrTopologyMismatch
+	assert.Equals(t, errors.As(err, &mismatch), true)
+	assert.Equals(t, d.Generation(), generation)
+	assert.Equals(t, d.HasReadyNodes(), false)
+
+	d = checkpointGraph(t)
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("e", "e"))
+	assert.Equals(t, errors.As(d.RestoreState(state), &mismatch), true)
+}
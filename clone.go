@@ -0,0 +1,138 @@
+package dgraph
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+func (d *directedGraph[NodeType]) CloneChecked() (DirectedGraph[NodeType], error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	newDG := d.clone()
+	if err := d.verifyClone(newDG); err != nil {
+		return nil, err
+	}
+	return newDG, nil
+}
+
+// verifyClone checks that the clone shares no mutable references with the original graph and that both have the
+// same topology fingerprint.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) verifyClone(newDG *directedGraph[NodeType]) error {
+	if newDG.lock == d.lock {
+		return &ErrCloneVerificationFailed{"the clone shares the lock of the original"}
+	}
+	shared := func(name string, original, cloned any) error {
+		switch reflect.ValueOf(original).Kind() {
+		case reflect.Map, reflect.Pointer, reflect.Slice:
+		default:
+			// Values of other kinds, such as custom ready sets, can't be shared by reference.
+			return nil
+		}
+		originalPointer := reflect.ValueOf(original).Pointer()
+		if originalPointer != 0 && originalPointer == reflect.ValueOf(cloned).Pointer() {
+			return &ErrCloneVerificationFailed{fmt.Sprintf("the clone shares the %s of the original", name)}
+		}
+		return nil
+	}
+	checks := []error{
+		shared("node map", d.nodes, newDG.nodes),
+		shared("ready nodes", d.readyForProcessing, newDG.readyForProcessing),
+		shared("outbound connections", d.connectionsFromNode, newDG.connectionsFromNode),
+		shared("inbound connections", d.connectionsToNode, newDG.connectionsToNode),
+		shared("changed nodes", d.changedNodes, newDG.changedNodes),
+		shared("groups", d.groups, newDG.groups),
+		shared("connection order", d.connectionSequence, newDG.connectionSequence),
+		shared("connection metadata", d.connectionMetadata, newDG.connectionMetadata),
+		shared("connection weights", d.connectionWeights, newDG.connectionWeights),
+		shared("resources in use", d.resourcesInUse, newDG.resourcesInUse),
+		shared("pending data", d.pendingData, newDG.pendingData),
+		shared("dependency statistics", d.dependencyStats, newDG.dependencyStats),
+	}
+	for nodeID, connections := range d.connectionsFromNode {
+		newConnections := newDG.connectionsFromNode[nodeID]
+		checks = append(checks, shared("outbound connections of node "+nodeID, connections, newConnections))
+	}
+	for nodeID, connections := range d.connectionsToNode {
+		newConnections := newDG.connectionsToNode[nodeID]
+		checks = append(checks, shared("inbound connections of node "+nodeID, connections, newConnections))
+	}
+	for connection, metadata := range d.connectionMetadata {
+		name := fmt.Sprintf("metadata of connection %s->%s", connection[0], connection[1])
+		checks = append(checks, shared(name, metadata, newDG.connectionMetadata[connection]))
+	}
+	for nodeID, n := range d.nodes {
+		newNode, ok := newDG.nodes[nodeID]
+		if !ok {
+			return &ErrCloneVerificationFailed{fmt.Sprintf("node %q is missing from the clone", nodeID)}
+		}
+		if newNode == n {
+			return &ErrCloneVerificationFailed{fmt.Sprintf("the clone shares node %q with the original", nodeID)}
+		}
+		if newNode.dg != newDG {
+			return &ErrCloneVerificationFailed{fmt.Sprintf("node %q of the clone references another graph", nodeID)}
+		}
+		checks = append(
+			checks,
+			shared("outstanding dependencies of "+nodeID, n.outstandingDependencies, newNode.outstandingDependencies),
+			shared("resolved dependencies of "+nodeID, n.resolvedDependencies, newNode.resolvedDependencies),
+			shared("failed dependencies of "+nodeID, n.failedDependencies, newNode.failedDependencies),
+			shared("required resources of "+nodeID, n.resources, newNode.resources),
+			shared("annotations of "+nodeID, n.annotations, newNode.annotations),
+			shared("attributes of "+nodeID, n.attributes, newNode.attributes),
+			shared("obviated dependency types of "+nodeID, n.obviatedTypes, newNode.obviatedTypes),
+		)
+	}
+	for name, g := range d.groups {
+		newGroup, ok := newDG.groups[name]
+		if !ok {
+			return &ErrCloneVerificationFailed{fmt.Sprintf("group %q is missing from the clone", name)}
+		}
+		if newGroup == g || newGroup.dg != newDG {
+			return &ErrCloneVerificationFailed{fmt.Sprintf("group %q of the clone references the original", name)}
+		}
+		checks = append(
+			checks,
+			shared("members of group "+name, g.members, newGroup.members),
+			shared("dependents of group "+name, g.dependents, newGroup.dependents),
+		)
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	if d.fingerprint() != newDG.fingerprint() {
+		return &ErrCloneVerificationFailed{"the topology of the clone differs from the original"}
+	}
+	return nil
+}
+
+// fingerprint returns a hash of the nodes, connections, dependency types, and resolution statuses of the graph,
+// which is independent of map iteration order.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) fingerprint() [sha256.Size]byte {
+	nodeIDs := make([]string, 0, len(d.nodes))
+	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+	hash := sha256.New()
+	for _, nodeID := range nodeIDs {
+		n := d.nodes[nodeID]
+		_, _ = fmt.Fprintf(hash, "node %q %s\n", nodeID, n.status)
+		var fromNodeIDs []string
+		for fromNodeID := range d.connectionsToNode[nodeID] {
+			fromNodeIDs = append(fromNodeIDs, fromNodeID)
+		}
+		slices.Sort(fromNodeIDs)
+		for _, fromNodeID := range fromNodeIDs {
+			_, _ = fmt.Fprintf(hash, "connection %q %q %s\n", fromNodeID, nodeID, n.dependencyType(fromNodeID))
+		}
+	}
+	var result [sha256.Size]byte
+	hash.Sum(result[:0])
+	return result
+}
@@ -0,0 +1,145 @@
+package dgraph
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Mermaid styles used by RunComparison.MermaidNodeStyle.
+const (
+	ComparisonStatusChangedStyle = "fill:#f96"
+	ComparisonSlowerStyle        = "stroke:#d00,stroke-width:3px"
+	ComparisonFasterStyle        = "stroke:#0a0,stroke-width:3px"
+)
+
+// NodeComparison compares the outcome of a single node in two runs.
+type NodeComparison struct {
+	NodeID      string
+	BaseStatus  ResolutionStatus
+	OtherStatus ResolutionStatus
+	// BaseTimings and OtherTimings are the timings of the node in the two runs.
+	BaseTimings  NodeTimings
+	OtherTimings NodeTimings
+	// DurationDelta is the queue time of the node in the other run minus the queue time in the base run. It is
+	// only set if HasDurationDelta is true, which requires the node to have been processed in both runs.
+	DurationDelta    time.Duration
+	HasDurationDelta bool
+}
+
+// StatusChanged returns true if the node was resolved differently in the two runs.
+func (c NodeComparison) StatusChanged() bool {
+	return c.BaseStatus != c.OtherStatus
+}
+
+// RunComparison is the result of comparing two runs of the same workflow with Compare.
+type RunComparison[NodeType any] struct {
+	// Nodes contains the comparison of every node, sorted by node ID.
+	Nodes []NodeComparison
+	byID  map[string]int
+}
+
+// Node returns the comparison of the node with the specified ID, and false if the node does not exist.
+func (c *RunComparison[NodeType]) Node(nodeID string) (NodeComparison, bool) {
+	i, ok := c.byID[nodeID]
+	if !ok {
+		return NodeComparison{}, false
+	}
+	return c.Nodes[i], true
+}
+
+// StatusChanges returns the comparisons of the nodes whose resolution status differs between the two runs.
+func (c *RunComparison[NodeType]) StatusChanges() []NodeComparison {
+	var result []NodeComparison
+	for _, nodeComparison := range c.Nodes {
+		if nodeComparison.StatusChanged() {
+			result = append(result, nodeComparison)
+		}
+	}
+	return result
+}
+
+// MermaidNodeStyle highlights the differences between the two runs when used as MermaidOptions.NodeStyle of
+// either graph. Nodes whose status changed are filled, and otherwise nodes that took longer or shorter in the
+// other run get a red or green border.
+func (c *RunComparison[NodeType]) MermaidNodeStyle(node Node[NodeType]) string {
+	nodeComparison, ok := c.Node(node.ID())
+	switch {
+	case !ok:
+		return ""
+	case nodeComparison.StatusChanged():
+		return ComparisonStatusChangedStyle
+	case !nodeComparison.HasDurationDelta || nodeComparison.DurationDelta == 0:
+		return ""
+	case nodeComparison.DurationDelta > 0:
+		return ComparisonSlowerStyle
+	default:
+		return ComparisonFasterStyle
+	}
+}
+
+// Compare compares two runs of the same workflow, given as two graphs with identical nodes and connections, and
+// returns the per-node status and timing differences of the other run relative to the base run. If the
+// topologies differ, an ErrTopologyMismatch is returned. Each graph should not be modified during the
+// comparison, as the nodes and connections are read with separate calls.
+func Compare[NodeType any](base, other DirectedGraph[NodeType]) (*RunComparison[NodeType], error) {
+	baseNodes := base.ListNodes()
+	otherNodes := other.ListNodes()
+	if err := compareTopology(baseNodes, otherNodes, base.ListConnections(), other.ListConnections()); err != nil {
+		return nil, err
+	}
+	nodeIDs := make([]string, 0, len(baseNodes))
+	for nodeID := range baseNodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+	result := &RunComparison[NodeType]{
+		Nodes: make([]NodeComparison, len(nodeIDs)),
+		byID:  make(map[string]int, len(nodeIDs)),
+	}
+	for i, nodeID := range nodeIDs {
+		baseNode, otherNode := baseNodes[nodeID], otherNodes[nodeID]
+		nodeComparison := NodeComparison{
+			NodeID:       nodeID,
+			BaseStatus:   baseNode.ResolutionStatus(),
+			OtherStatus:  otherNode.ResolutionStatus(),
+			BaseTimings:  baseNode.Timings(),
+			OtherTimings: otherNode.Timings(),
+		}
+		baseDuration, baseOK := nodeComparison.BaseTimings.QueueTime()
+		otherDuration, otherOK := nodeComparison.OtherTimings.QueueTime()
+		if baseOK && otherOK {
+			nodeComparison.DurationDelta = otherDuration - baseDuration
+			nodeComparison.HasDurationDelta = true
+		}
+		result.Nodes[i] = nodeComparison
+		result.byID[nodeID] = i
+	}
+	return result, nil
+}
+
+// compareTopology returns an ErrTopologyMismatch describing the first difference between the two graphs. The
+// dependency types are ignored, since they change while the graphs are resolved.
+func compareTopology[NodeType any](
+	baseNodes, otherNodes map[string]Node[NodeType],
+	baseConnections, otherConnections []Connection,
+) error {
+	diff := diffTopology(baseNodes, otherNodes, baseConnections, otherConnections)
+	switch {
+	case len(diff.RemovedNodes) > 0:
+		return &ErrTopologyMismatch{fmt.Sprintf("node %q is missing from the other graph", diff.RemovedNodes[0])}
+	case len(diff.AddedNodes) > 0:
+		return &ErrTopologyMismatch{fmt.Sprintf("node %q is missing from the base graph", diff.AddedNodes[0])}
+	case len(diff.RemovedConnections) > 0:
+		return &ErrTopologyMismatch{fmt.Sprintf(
+			"connection from node %q to node %q is missing from the other graph",
+			diff.RemovedConnections[0].SourceNodeID, diff.RemovedConnections[0].DestinationNodeID,
+		)}
+	case len(diff.AddedConnections) > 0:
+		return &ErrTopologyMismatch{fmt.Sprintf(
+			"connection from node %q to node %q is missing from the base graph",
+			diff.AddedConnections[0].SourceNodeID, diff.AddedConnections[0].DestinationNodeID,
+		)}
+	}
+	return nil
+}
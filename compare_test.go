@@ -0,0 +1,92 @@
+package dgraph_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// runWorkflow builds a graph in which b depends on a and c is independent, then resolves every node with the
+// specified status after it has been processed for the specified duration.
+func runWorkflow(
+	t *testing.T,
+	statuses map[string]dgraph.ResolutionStatus,
+	durations map[string]time.Duration,
+) dgraph.DirectedGraph[string] {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := dgraph.New[string](dgraph.WithClock(func() time.Time { return now }))
+	for _, id := range []string{"a", "b", "c"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	for _, id := range []string{"a", "c", "b"} {
+		d.PopReadyNodes()
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(id))
+		if n.ResolutionStatus() != dgraph.Waiting {
+			continue
+		}
+		now = now.Add(durations[id])
+		assert.NoError(t, n.ResolveNode(statuses[id]))
+	}
+	return d
+}
+
+func TestCompare(t *testing.T) {
+	base := runWorkflow(
+		t,
+		map[string]dgraph.ResolutionStatus{"a": dgraph.Resolved, "b": dgraph.Resolved, "c": dgraph.Resolved},
+		map[string]time.Duration{"a": time.Second, "b": time.Second, "c": time.Second},
+	)
+	other := runWorkflow(
+		t,
+		map[string]dgraph.ResolutionStatus{"a": dgraph.Resolved, "b": dgraph.Resolved, "c": dgraph.Unresolvable},
+		map[string]time.Duration{"a": 3 * time.Second, "b": time.Second / 2, "c": time.Second},
+	)
+	comparison := assert.NoErrorR[*dgraph.RunComparison[string]](t)(dgraph.Compare(base, other))
+	assert.Equals(t, len(comparison.Nodes), 3)
+
+	a, ok := comparison.Node("a")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, a.StatusChanged(), false)
+	assert.Equals(t, a.HasDurationDelta, true)
+	assert.Equals(t, a.DurationDelta, 2*time.Second)
+
+	changes := comparison.StatusChanges()
+	assert.Equals(t, len(changes), 1)
+	assert.Equals(t, changes[0].NodeID, "c")
+	assert.Equals(t, changes[0].OtherStatus, dgraph.Unresolvable)
+
+	mermaid := other.MermaidWithOptions(dgraph.MermaidOptions[string]{NodeStyle: comparison.MermaidNodeStyle})
+	assert.Equals(t, strings.HasSuffix(mermaid, `%% Styles
+style a stroke:#d00,stroke-width:3px
+style b stroke:#0a0,stroke-width:3px
+style c fill:#f96
+%% Mermaid end
+`), true)
+}
+
+func TestCompare_TopologyMismatch(t *testing.T) {
+	base := dgraph.New[string]()
+	other := dgraph.New[string]()
+	for _, d := range []dgraph.DirectedGraph[string]{base, other} {
+		for _, id := range []string{"a", "b"} {
+			_, err := d.AddNode(id, id)
+			assert.NoError(t, err)
+		}
+	}
+	b := assert.NoErrorR[dgraph.Node[string]](t)(base.GetNodeByID("b"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	_, err := dgraph.Compare(base, other)
+	assert.InstanceOf[*dgraph.ErrTopologyMismatch](t, err)
+	assert.Equals(
+		t,
+		err.Error(),
+		`the graphs have different topologies: connection from node "a" to node "b" is missing from the other graph`,
+	)
+}
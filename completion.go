@@ -0,0 +1,61 @@
+package dgraph
+
+import "strings"
+
+// StatusCounts is the number of nodes in each resolution status within a part of the graph.
+type StatusCounts map[ResolutionStatus]int
+
+// IsComplete returns true if no node is Waiting.
+func (c StatusCounts) IsComplete() bool {
+	return c[Waiting] == 0
+}
+
+func (d *directedGraph[NodeType]) StatusCounts(prefix string) StatusCounts {
+	d.lock.Lock()
+	defer d.unlock()
+	d.checkDeadline()
+	result := StatusCounts{}
+	for nodeID, n := range d.nodes {
+		if strings.HasPrefix(nodeID, prefix) {
+			result[n.status]++
+		}
+	}
+	return result
+}
+
+// NodeState is the execution state of a node at the time DirectedGraph.NodeStates was called.
+type NodeState struct {
+	Status ResolutionStatus
+	// Ready is the same as Node.IsReady.
+	Ready bool
+}
+
+func (d *directedGraph[NodeType]) NodeStates() map[string]NodeState {
+	d.lock.Lock()
+	defer d.unlock()
+	d.checkDeadline()
+	result := make(map[string]NodeState, len(d.nodes))
+	for nodeID, n := range d.nodes {
+		result[nodeID] = NodeState{n.status, n.ready}
+	}
+	return result
+}
+
+func (d *directedGraph[NodeType]) IsComplete(prefix string) bool {
+	return d.StatusCounts(prefix).IsComplete()
+}
+
+func (g *group[NodeType]) StatusCounts() StatusCounts {
+	g.dg.lock.Lock()
+	defer g.dg.unlock()
+	g.dg.checkDeadline()
+	result := StatusCounts{}
+	for nodeID := range g.members {
+		result[g.dg.nodes[nodeID].status]++
+	}
+	return result
+}
+
+func (g *group[NodeType]) IsComplete() bool {
+	return g.StatusCounts().IsComplete()
+}
@@ -0,0 +1,55 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_IsComplete(t *testing.T) {
+	d := dgraph.New[string]()
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"steps.a.run", "steps.a.done", "steps.b.run", "finish"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	group := assert.NoErrorR[dgraph.Group](t)(d.AddGroup("b"))
+	assert.NoError(t, group.AddMember("steps.b.run"))
+
+	assert.NoError(t, nodes["steps.a.run"].ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.IsComplete("steps.a."), false)
+	assert.NoError(t, nodes["steps.a.done"].ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, d.IsComplete("steps.a."), true)
+	assert.Equals(t, d.StatusCounts("steps."), dgraph.StatusCounts{
+		dgraph.Resolved:     1,
+		dgraph.Unresolvable: 1,
+		dgraph.Waiting:      1,
+	})
+	assert.Equals(t, d.IsComplete(""), false)
+
+	assert.Equals(t, group.IsComplete(), false)
+	assert.NoError(t, nodes["steps.b.run"].ResolveNode(dgraph.Resolved))
+	assert.Equals(t, group.StatusCounts(), dgraph.StatusCounts{dgraph.Resolved: 1})
+	assert.Equals(t, group.IsComplete(), true)
+}
+
+func TestDirectedGraph_NodeStates(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"steps.a.run", "steps.a.done", "finish"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	done := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("steps.a.done"))
+	assert.NoError(t, done.ConnectDependency("steps.a.run", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	finish := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("finish"))
+	assert.NoError(t, finish.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.NodeStates(), map[string]dgraph.NodeState{
+		"steps.a.run":  {Status: dgraph.Waiting, Ready: true},
+		"steps.a.done": {Status: dgraph.Waiting},
+		"finish":       {Status: dgraph.Resolved, Ready: true},
+	})
+	assert.Equals(t, d.Namespace("steps.a.").NodeStates(), map[string]dgraph.NodeState{
+		"run":  {Status: dgraph.Waiting, Ready: true},
+		"done": {Status: dgraph.Waiting},
+	})
+}
@@ -0,0 +1,202 @@
+package dgraph
+
+import (
+	"maps"
+	"slices"
+)
+
+// Sequence creates a new graph containing the nodes and connections of both graphs, where every leaf of the first
+// graph (a node without outbound connections) becomes a dependency of every root of the second graph (a node
+// without inbound connections), using the specified dependency type. The items are copied; resolution states are
+// not. If both graphs contain a node with the same ID, an ErrNodeAlreadyExists is returned.
+func Sequence[NodeType any](
+	first, second DirectedGraph[NodeType],
+	dependencyType DependencyType,
+) (DirectedGraph[NodeType], error) {
+	result, leaves, roots, err := combine(first, second)
+	if err != nil {
+		return nil, err
+	}
+	for _, rootID := range roots {
+		root, err := result.GetNodeByID(rootID)
+		if err != nil {
+			return nil, err
+		}
+		for _, leafID := range leaves {
+			if err := root.ConnectDependency(leafID, dependencyType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// SequenceWithBarrier works like Sequence, but connects the graphs through a single barrier node with the
+// specified ID and item, instead of connecting every leaf to every root. The leaves of the first graph are
+// dependencies of the barrier with the specified dependency type, and the barrier is an AndDependency of every root
+// of the second graph.
+func SequenceWithBarrier[NodeType any](
+	first, second DirectedGraph[NodeType],
+	dependencyType DependencyType,
+	barrierID string,
+	barrierItem NodeType,
+) (DirectedGraph[NodeType], error) {
+	result, leaves, roots, err := combine(first, second)
+	if err != nil {
+		return nil, err
+	}
+	barrier, err := result.AddNode(barrierID, barrierItem)
+	if err != nil {
+		return nil, err
+	}
+	for _, leafID := range leaves {
+		if err := barrier.ConnectDependency(leafID, dependencyType); err != nil {
+			return nil, err
+		}
+	}
+	for _, rootID := range roots {
+		if err := barrier.Connect(rootID); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// combine copies both graphs into a new graph, and returns the sorted IDs of the leaves of the first graph and the
+// roots of the second graph.
+func combine[NodeType any](
+	first, second DirectedGraph[NodeType],
+) (result DirectedGraph[NodeType], leaves []string, roots []string, err error) {
+	if firstDG, ok := first.(*directedGraph[NodeType]); ok {
+		result = newDirectedGraph[NodeType](firstDG.config)
+	} else {
+		result = New[NodeType]()
+	}
+	if err := copyTopology(result, first); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := copyTopology(result, second); err != nil {
+		return nil, nil, nil, err
+	}
+	for nodeID, n := range first.ListNodes() {
+		outbound, err := n.ListOutboundConnections()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(outbound) == 0 {
+			leaves = append(leaves, nodeID)
+		}
+	}
+	for nodeID := range second.ListNodesWithoutInboundConnections() {
+		roots = append(roots, nodeID)
+	}
+	slices.Sort(leaves)
+	slices.Sort(roots)
+	return result, leaves, roots, nil
+}
+
+// copyTopology adds the nodes and connections of the source graph to the target graph, keeping the declared
+// dependency types and the conditions of the connections, so that obviated dependencies are restored, and the
+// dependency expressions of the nodes.
+func copyTopology[NodeType any](target, source DirectedGraph[NodeType]) error {
+	t := topologyOf(source)
+	for _, nodeID := range t.nodeIDs {
+		if _, err := target.AddNode(nodeID, t.items[nodeID]); err != nil {
+			return err
+		}
+	}
+	for _, connection := range t.connections {
+		if connection.DependencyType == ExpressionDependency {
+			// Connected with the expression below.
+			continue
+		}
+		targetNode, err := target.GetNodeByID(connection.DestinationNodeID)
+		if err != nil {
+			return err
+		}
+		if condition, ok := t.conditions[[2]string{connection.SourceNodeID, connection.DestinationNodeID}]; ok {
+			err = targetNode.ConnectDependencyIf(connection.SourceNodeID, condition)
+		} else {
+			err = targetNode.ConnectDependency(connection.SourceNodeID, connection.DependencyType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, nodeID := range t.nodeIDs {
+		expression, ok := t.expressions[nodeID]
+		if !ok {
+			continue
+		}
+		targetNode, err := target.GetNodeByID(nodeID)
+		if err != nil {
+			return err
+		}
+		if err := targetNode.ConnectExpression(expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topology is a snapshot of the structure of a graph, which Merge and Sequence copy into another graph.
+type topology[NodeType any] struct {
+	// nodeIDs contains the IDs of the nodes, sorted.
+	nodeIDs []string
+	items   map[string]NodeType
+	// connections contains the connections in connection order, with the dependency types they were declared
+	// with, even if they have been obviated since.
+	connections []Connection
+	// conditions contains the conditions of the conditional dependencies, keyed by source and destination ID.
+	conditions map[[2]string]DependencyCondition[NodeType]
+	// expressions contains the dependency expressions of the nodes that have one.
+	expressions map[string]Expression
+}
+
+// topologyOf takes a snapshot of the structure of the graph. The declared dependency types and the conditions of
+// conditional dependencies are only available if the graph was created by this package; otherwise the current
+// dependency types are used. The graph must not be locked by the caller.
+func topologyOf[NodeType any](g DirectedGraph[NodeType]) topology[NodeType] {
+	if v, ok := g.(*namespace[NodeType]); ok {
+		g = v.detached(false)
+	}
+	if d, ok := g.(*directedGraph[NodeType]); ok {
+		d.lock.RLock()
+		defer d.lock.RUnlock()
+		t := topology[NodeType]{
+			items:       make(map[string]NodeType, len(d.nodes)),
+			connections: d.listConnections(),
+			conditions:  maps.Clone(d.connectionConditions),
+			expressions: map[string]Expression{},
+		}
+		for i, connection := range t.connections {
+			t.connections[i].DependencyType = d.nodes[connection.DestinationNodeID].declaredDependencyType(
+				connection.SourceNodeID,
+			)
+		}
+		for nodeID, n := range d.nodes {
+			t.nodeIDs = append(t.nodeIDs, nodeID)
+			t.items[nodeID] = n.item
+			if n.expression != nil {
+				t.expressions[nodeID] = n.expression.clone()
+			}
+		}
+		slices.Sort(t.nodeIDs)
+		return t
+	}
+	nodes := g.ListNodes()
+	t := topology[NodeType]{
+		items:       make(map[string]NodeType, len(nodes)),
+		connections: g.ListConnections(),
+		expressions: map[string]Expression{},
+	}
+	for nodeID, n := range nodes {
+		t.nodeIDs = append(t.nodeIDs, nodeID)
+		t.items[nodeID] = n.Item()
+		if expression, ok := n.DependencyExpression(); ok {
+			t.expressions[nodeID] = expression
+		}
+	}
+	slices.Sort(t.nodeIDs)
+	return t
+}
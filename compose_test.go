@@ -0,0 +1,81 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func newChain(t *testing.T, ids ...string) dgraph.DirectedGraph[string] {
+	d := dgraph.New[string]()
+	for i, id := range ids {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+		if i > 0 {
+			assert.NoError(t, n.ConnectDependency(ids[i-1], dgraph.AndDependency))
+		}
+	}
+	return d
+}
+
+func TestSequence(t *testing.T) {
+	first := newChain(t, "a1", "a2")
+	_, err := first.AddNode("a3", "a3")
+	assert.NoError(t, err)
+	second := newChain(t, "b1", "b2")
+
+	combined, err := dgraph.Sequence(first, second, dgraph.CompletionAndDependency)
+	assert.NoError(t, err)
+	assert.Equals(t, len(combined.ListNodes()), 5)
+	b1 := assert.NoErrorR[dgraph.Node[string]](t)(combined.GetNodeByID("b1"))
+	assert.Equals(t, b1.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a2": dgraph.CompletionAndDependency,
+		"a3": dgraph.CompletionAndDependency,
+	})
+	b2 := assert.NoErrorR[dgraph.Node[string]](t)(combined.GetNodeByID("b2"))
+	assert.Equals(t, b2.OutstandingDependencies(), map[string]dgraph.DependencyType{"b1": dgraph.AndDependency})
+
+	_, err = dgraph.Sequence(first, first, dgraph.AndDependency)
+	assert.InstanceOf[dgraph.ErrNodeAlreadyExists](t, err)
+}
+
+func TestSequenceWithBarrier(t *testing.T) {
+	first := newChain(t, "a1", "a2")
+	second := newChain(t, "b1")
+	_, err := second.AddNode("b2", "b2")
+	assert.NoError(t, err)
+
+	combined, err := dgraph.SequenceWithBarrier(first, second, dgraph.AndDependency, "barrier", "barrier")
+	assert.NoError(t, err)
+	barrier := assert.NoErrorR[dgraph.Node[string]](t)(combined.GetNodeByID("barrier"))
+	assert.Equals(t, barrier.OutstandingDependencies(), map[string]dgraph.DependencyType{"a2": dgraph.AndDependency})
+	outbound, err := barrier.ListOutboundConnections()
+	assert.NoError(t, err)
+	assert.Equals(t, len(outbound), 2)
+	assert.MapContainsKey(t, "b1", outbound)
+	assert.MapContainsKey(t, "b2", outbound)
+}
+
+func TestSequence_StartedGraph(t *testing.T) {
+	first := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(first.AddNode(id, id))
+	}
+	c := assert.NoErrorR[dgraph.Node[string]](t)(first.GetNodeByID("c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, first.PushStartingNodes())
+	a := assert.NoErrorR[dgraph.Node[string]](t)(first.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{"b": dgraph.ObviatedDependency})
+
+	// The copy has the declared dependency types, not the obviated ones.
+	combined := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(
+		dgraph.Sequence(first, newChain(t, "d"), dgraph.AndDependency),
+	)
+	combinedC := assert.NoErrorR[dgraph.Node[string]](t)(combined.GetNodeByID("c"))
+	assert.Equals(t, combinedC.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.OrDependency,
+		"b": dgraph.OrDependency,
+	})
+}
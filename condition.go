@@ -0,0 +1,121 @@
+package dgraph
+
+// ConditionOperator is the kind of a ReadinessCondition.
+type ConditionOperator string
+
+const (
+	// ConditionAll is satisfied if all of its operands are satisfied, or if it has none.
+	ConditionAll ConditionOperator = "all"
+	// ConditionAny is satisfied if any of its operands is satisfied.
+	ConditionAny ConditionOperator = "any"
+	// ConditionDependency is a single dependency. An AndDependency or OrDependency is satisfied if the dependency
+	// is Resolved, a CompletionAndDependency if it is Resolved or Unresolvable, and an OnUnresolvableDependency if
+	// it is Unresolvable. Conditional dependencies are satisfied if their condition is met, but Evaluate treats them
+	// as CompletionAndDependency, since it doesn't have the items of the dependencies.
+	ConditionDependency ConditionOperator = "dependency"
+)
+
+// ReadinessCondition is a boolean expression tree over the dependencies of a node, which describes when the node
+// becomes ready. It is returned by Node.ReadinessCondition, so that tools can evaluate and render the condition.
+type ReadinessCondition struct {
+	Operator ConditionOperator
+	// Operands are the subexpressions of ConditionAll and ConditionAny.
+	Operands []ReadinessCondition
+	// DependencyID is the ID of the dependency of a ConditionDependency.
+	DependencyID string
+	// DependencyType is the type the dependency was connected with, even if it has been obviated since.
+	DependencyType DependencyType
+	// Status is the current resolution status of the dependency.
+	Status ResolutionStatus
+	// Satisfied is true if the expression is satisfied in the current state of the graph. A dependency is only
+	// satisfied once its resolution has been applied to the node, so it may be unsatisfied while its Status is
+	// already terminal, for example while its data is pending or before Reconcile.
+	Satisfied bool
+}
+
+// Evaluate evaluates the expression with the specified statuses of the dependencies instead of the current state
+// of the graph. Dependencies missing from the map are treated as Waiting.
+func (c ReadinessCondition) Evaluate(statuses map[string]ResolutionStatus) bool {
+	switch c.Operator {
+	case ConditionDependency:
+		status, ok := statuses[c.DependencyID]
+		if !ok {
+			status = Waiting
+		}
+		return conditionSatisfied(c.DependencyType, status)
+	case ConditionAny:
+		for _, operand := range c.Operands {
+			if operand.Evaluate(statuses) {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, operand := range c.Operands {
+			if !operand.Evaluate(statuses) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (n *node[NodeType]) ReadinessCondition() ReadinessCondition {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	result := ReadinessCondition{Operator: ConditionAll, Satisfied: true}
+	var ors []ReadinessCondition
+	for _, dependencyID := range sortedKeys(n.dg.connectionsToNode[n.id]) {
+		dependencyType := n.declaredDependencyType(dependencyID)
+		if !isHardDependency(dependencyType) || dependencyType == ExpressionDependency && n.expression != nil {
+			continue
+		}
+		operand := ReadinessCondition{
+			Operator:       ConditionDependency,
+			DependencyID:   dependencyID,
+			DependencyType: dependencyType,
+			Status:         n.dg.nodes[dependencyID].status,
+		}
+		// Obviated dependencies have been recorded under their current type, so they are never satisfied.
+		if recordedType, ok := n.resolvedDependencies[dependencyID]; ok {
+			operand.Satisfied = n.dependencySatisfied(dependencyID, recordedType, Resolved)
+		} else if recordedType, ok := n.failedDependencies[dependencyID]; ok {
+			operand.Satisfied = n.dependencySatisfied(dependencyID, recordedType, Unresolvable)
+		}
+		if dependencyType == OrDependency {
+			ors = append(ors, operand)
+			continue
+		}
+		result.Operands = append(result.Operands, operand)
+		result.Satisfied = result.Satisfied && operand.Satisfied
+	}
+	if len(ors) > 0 {
+		anyOf := ReadinessCondition{Operator: ConditionAny, Operands: ors}
+		for _, operand := range ors {
+			anyOf.Satisfied = anyOf.Satisfied || operand.Satisfied
+		}
+		result.Operands = append(result.Operands, anyOf)
+		result.Satisfied = result.Satisfied && anyOf.Satisfied
+	}
+	if n.expression != nil {
+		expression := n.expressionCondition(*n.expression)
+		result.Operands = append(result.Operands, expression)
+		result.Satisfied = result.Satisfied && expression.Satisfied
+	}
+	return result
+}
+
+// conditionSatisfied returns true if a dependency of the specified type with the specified status satisfies its
+// ConditionDependency.
+func conditionSatisfied(dependencyType DependencyType, status ResolutionStatus) bool {
+	switch dependencyType {
+	case AndDependency, OrDependency:
+		return status == Resolved
+	case CompletionAndDependency:
+		return status != Waiting
+	case OnUnresolvableDependency:
+		return status == Unresolvable
+	default:
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestNode_ReadinessCondition(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, nodeID := range []string{"a", "b", "c", "o", "x"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("x"))
+	assert.NoError(t, d.ConnectDependencies("x", map[string]dgraph.DependencyType{
+		"a": dgraph.AndDependency,
+		"b": dgraph.OrDependency,
+		"c": dgraph.OrDependency,
+		"o": dgraph.OptionalDependency,
+	}))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+
+	condition := x.ReadinessCondition()
+	assert.Equals(t, condition, dgraph.ReadinessCondition{
+		Operator: dgraph.ConditionAll,
+		Operands: []dgraph.ReadinessCondition{
+			{
+				Operator:       dgraph.ConditionDependency,
+				DependencyID:   "a",
+				DependencyType: dgraph.AndDependency,
+				Status:         dgraph.Waiting,
+			},
+			{
+				Operator: dgraph.ConditionAny,
+				Operands: []dgraph.ReadinessCondition{
+					{
+						Operator:       dgraph.ConditionDependency,
+						DependencyID:   "b",
+						DependencyType: dgraph.OrDependency,
+						Status:         dgraph.Resolved,
+						Satisfied:      true,
+					},
+					{
+						Operator:       dgraph.ConditionDependency,
+						DependencyID:   "c",
+						DependencyType: dgraph.OrDependency,
+						Status:         dgraph.Waiting,
+					},
+				},
+				Satisfied: true,
+			},
+		},
+	})
+	assert.Equals(t, condition.Evaluate(map[string]dgraph.ResolutionStatus{"a": dgraph.Resolved}), false)
+	assert.Equals(t, condition.Evaluate(map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Resolved,
+		"c": dgraph.Resolved,
+	}), true)
+
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, x.ReadinessCondition().Satisfied, true)
+	assert.Equals(t, x.IsReady(), true)
+}
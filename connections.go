@@ -0,0 +1,136 @@
+package dgraph
+
+import (
+	"maps"
+	"slices"
+)
+
+// ConnectionState is the state of a connection from the point of view of the destination node.
+type ConnectionState string
+
+const (
+	// ConnectionOutstanding means the destination node has not processed a resolution of the source node yet.
+	ConnectionOutstanding ConnectionState = "outstanding"
+	// ConnectionResolved means the destination node has processed the resolution of the source node as Resolved.
+	ConnectionResolved ConnectionState = "resolved"
+	// ConnectionFailed means the destination node has processed the resolution of the source node as
+	// Unresolvable.
+	ConnectionFailed ConnectionState = "failed"
+)
+
+// Connection is a directed connection from a dependency to the node depending on it.
+type Connection struct {
+	SourceNodeID      string
+	DestinationNodeID string
+	// DependencyType is the current dependency type of the connection, as reported by the destination node.
+	DependencyType DependencyType
+	// Resolved is true if the resolution of the source node has already been processed by the destination node,
+	// whether the source node was resolved or unresolvable.
+	Resolved bool
+	// State distinguishes whether a resolved connection was resolved or failed.
+	State ConnectionState
+	// Metadata contains the key/value metadata of the connection, or nil if it has none.
+	Metadata map[string]string
+	// Weight is the weight of the connection used by ShortestPath and LongestPath.
+	Weight float64
+}
+
+// LabelMetadataKey is the connection metadata key or node attribute key holding the label of the connection or
+// node, which exporters render.
+const LabelMetadataKey = "label"
+
+// DescriptionMetadataKey is the connection metadata key holding the documentation string of the connection, which
+// the DOT renderer shows as a tooltip.
+const DescriptionMetadataKey = "description"
+
+func (d *directedGraph[NodeType]) ListConnections() []Connection {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.listConnections()
+}
+
+func (d *directedGraph[NodeType]) Connections() func(yield func(Connection) bool) {
+	return sequence(d.ListConnections())
+}
+
+func (d *directedGraph[NodeType]) Edges() func(yield func(Edge) bool) {
+	return sequence(d.ListEdges())
+}
+
+// sequence returns an iterator over the items of a snapshot.
+func sequence[T any](items []T) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (d *directedGraph[NodeType]) ListEdges() []Edge {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	connections := d.listConnections()
+	result := make([]Edge, len(connections))
+	for i, connection := range connections {
+		result[i] = &edge[NodeType]{d.nodes[connection.SourceNodeID], d.nodes[connection.DestinationNodeID], d}
+	}
+	return result
+}
+
+func (d *directedGraph[NodeType]) AdjacencyMatrix() ([][]bool, []string) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	nodeIDs := make([]string, 0, len(d.nodes))
+	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+	indexes := make(map[string]int, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		indexes[nodeID] = i
+	}
+	matrix := make([][]bool, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		matrix[i] = make([]bool, len(nodeIDs))
+		for destination := range d.connectionsFromNode[nodeID] {
+			matrix[i][indexes[destination]] = true
+		}
+	}
+	return matrix, nodeIDs
+}
+
+// listConnections returns all connections, ordered as by sortConnections.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) listConnections() []Connection {
+	var pairs [][2]string
+	for source, destinations := range d.connectionsFromNode {
+		for destination := range destinations {
+			pairs = append(pairs, [2]string{source, destination})
+		}
+	}
+	d.sortConnections(pairs)
+	result := make([]Connection, len(pairs))
+	for i, pair := range pairs {
+		n := d.nodes[pair[1]]
+		_, resolved := n.resolvedDependencies[pair[0]]
+		_, failed := n.failedDependencies[pair[0]]
+		state := ConnectionOutstanding
+		if resolved {
+			state = ConnectionResolved
+		} else if failed {
+			state = ConnectionFailed
+		}
+		result[i] = Connection{
+			SourceNodeID:      pair[0],
+			DestinationNodeID: pair[1],
+			DependencyType:    n.dependencyType(pair[0]),
+			Resolved:          resolved || failed,
+			State:             state,
+			Metadata:          maps.Clone(d.connectionMetadata[pair]),
+			Weight:            d.connectionWeight(pair[0], pair[1]),
+		}
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_ListConnections(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.OrDependency))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.OptionalDependency))
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("x", "x"))
+	y := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("y", "y"))
+	assert.NoError(t, y.ConnectDependency(x.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, x.ResolveNode(dgraph.Unresolvable))
+
+	expected := []dgraph.Connection{
+		{
+			SourceNodeID:      "a",
+			DestinationNodeID: "b",
+			DependencyType:    dgraph.OptionalDependency,
+			Resolved:          true,
+			State:             dgraph.ConnectionResolved,
+			Weight:            1,
+		},
+		{
+			SourceNodeID:      "a",
+			DestinationNodeID: "c",
+			DependencyType:    dgraph.OrDependency,
+			Resolved:          true,
+			State:             dgraph.ConnectionResolved,
+			Weight:            1,
+		},
+		{
+			SourceNodeID:      "b",
+			DestinationNodeID: "c",
+			DependencyType:    dgraph.ObviatedDependency,
+			State:             dgraph.ConnectionOutstanding,
+			Weight:            1,
+		},
+		{
+			SourceNodeID:      "x",
+			DestinationNodeID: "y",
+			DependencyType:    dgraph.AndDependency,
+			Resolved:          true,
+			State:             dgraph.ConnectionFailed,
+			Weight:            1,
+		},
+	}
+	assert.Equals(t, d.ListConnections(), expected)
+
+	var iterated []dgraph.Connection
+	d.Connections()(func(connection dgraph.Connection) bool {
+		iterated = append(iterated, connection)
+		return len(iterated) < 2
+	})
+	assert.Equals(t, iterated, expected[:2])
+}
+
+func TestDirectedGraph_AdjacencyMatrix(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.OrDependency))
+
+	matrix, nodeIDs := d.AdjacencyMatrix()
+	assert.Equals(t, nodeIDs, []string{"a", "b", "c"})
+	assert.Equals(t, matrix, [][]bool{
+		{false, true, false},
+		{false, false, true},
+		{false, false, false},
+	})
+
+	edges := d.ListEdges()
+	assert.Equals(t, len(edges), 2)
+	assert.Equals(t, edges[1].SourceNodeID(), "b")
+	assert.Equals(t, edges[1].DestinationNodeID(), "c")
+	assert.Equals(t, assert.NoErrorR[dgraph.DependencyType](t)(edges[1].DependencyType()), dgraph.OrDependency)
+}
+
+func TestDirectedGraph_NodesAndEdges(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+
+	items := map[string]string{}
+	d.Nodes()(func(nodeID string, node dgraph.Node[string]) bool {
+		items[nodeID] = node.Item()
+		return true
+	})
+	assert.Equals(t, items, map[string]string{"a": "a", "b": "b"})
+	visited := 0
+	d.Nodes()(func(string, dgraph.Node[string]) bool {
+		visited++
+		return false
+	})
+	assert.Equals(t, visited, 1)
+
+	var edges []string
+	d.Edges()(func(edge dgraph.Edge) bool {
+		edges = append(edges, edge.SourceNodeID()+"->"+edge.DestinationNodeID())
+		return true
+	})
+	assert.Equals(t, edges, []string{"a->b"})
+}
@@ -0,0 +1,265 @@
+package dgraph
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// CycleBreak is a connection suggested for removal by SuggestCycleBreaks.
+type CycleBreak struct {
+	SourceNodeID      string
+	DestinationNodeID string
+	// DependencyType is the current dependency type of the connection.
+	DependencyType DependencyType
+	// Alternative is CompletionAndDependency if the connection can be converted to it instead of being removed,
+	// or empty if the connection already has that type. A converted connection stays part of the cycle, but the
+	// destination no longer fails with the source, so the cycle can be broken at run time by marking the source
+	// Unresolvable.
+	Alternative DependencyType
+}
+
+func (d *directedGraph[NodeType]) FindCycles() [][]string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	var result [][]string
+	for _, component := range stronglyConnectedComponents(d.connectionsFromNode) {
+		// Self-connections are not allowed, so only components with more than one node contain cycles.
+		if len(component) > 1 {
+			result = append(result, component)
+		}
+	}
+	return result
+}
+
+func (d *directedGraph[NodeType]) SuggestCycleBreaks() []CycleBreak {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if !d.HasCycles() {
+		return nil
+	}
+	order := feedbackOrder(d.connectionsFromNode)
+	position := make(map[string]int, len(order))
+	for i, nodeID := range order {
+		position[nodeID] = i
+	}
+	// Keep all forward edges of the ordering, which are acyclic, and collect the backward edges.
+	remaining := make(map[string]map[string]struct{}, len(d.connectionsFromNode))
+	var backwardEdges [][2]string
+	for source, destinations := range d.connectionsFromNode {
+		remaining[source] = map[string]struct{}{}
+		for destination := range destinations {
+			if position[source] < position[destination] {
+				remaining[source][destination] = struct{}{}
+			} else {
+				backwardEdges = append(backwardEdges, [2]string{source, destination})
+			}
+		}
+	}
+	slices.SortFunc(backwardEdges, func(a, b [2]string) int {
+		if c := cmp.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a[1], b[1])
+	})
+	// Add back every backward edge that does not close a cycle, so that no suggestion is redundant.
+	var result []CycleBreak
+	for _, edge := range backwardEdges {
+		if !isReachable(remaining, edge[1], edge[0]) {
+			remaining[edge[0]][edge[1]] = struct{}{}
+			continue
+		}
+		suggestion := CycleBreak{
+			SourceNodeID:      edge[0],
+			DestinationNodeID: edge[1],
+			DependencyType:    d.nodes[edge[1]].dependencyType(edge[0]),
+		}
+		if suggestion.DependencyType != CompletionAndDependency {
+			suggestion.Alternative = CompletionAndDependency
+		}
+		result = append(result, suggestion)
+	}
+	return result
+}
+
+// feedbackOrder orders the nodes using the Eades-Lin-Smyth heuristic, so that few edges point backwards in the
+// order. Ties are broken by node ID to keep the result deterministic.
+func feedbackOrder(connectionsFromNode map[string]map[string]struct{}) []string {
+	outbound := make(map[string]map[string]struct{}, len(connectionsFromNode))
+	inbound := make(map[string]map[string]struct{}, len(connectionsFromNode))
+	for nodeID := range connectionsFromNode {
+		outbound[nodeID] = map[string]struct{}{}
+		inbound[nodeID] = map[string]struct{}{}
+	}
+	for source, destinations := range connectionsFromNode {
+		for destination := range destinations {
+			outbound[source][destination] = struct{}{}
+			inbound[destination][source] = struct{}{}
+		}
+	}
+	remove := func(nodeID string) {
+		for destination := range outbound[nodeID] {
+			delete(inbound[destination], nodeID)
+		}
+		for source := range inbound[nodeID] {
+			delete(outbound[source], nodeID)
+		}
+		delete(outbound, nodeID)
+		delete(inbound, nodeID)
+	}
+	// Sort the IDs once. Removed nodes are skipped while iterating instead of re-sorting the remaining ones.
+	sortedNodes := make([]string, 0, len(outbound))
+	for nodeID := range outbound {
+		sortedNodes = append(sortedNodes, nodeID)
+	}
+	slices.Sort(sortedNodes)
+
+	var head, tail []string
+	for len(outbound) > 0 {
+		changed := true
+		for changed {
+			changed = false
+			for _, nodeID := range sortedNodes {
+				if _, ok := outbound[nodeID]; !ok {
+					continue
+				}
+				if len(outbound[nodeID]) == 0 {
+					tail = append(tail, nodeID)
+					remove(nodeID)
+					changed = true
+				} else if len(inbound[nodeID]) == 0 {
+					head = append(head, nodeID)
+					remove(nodeID)
+					changed = true
+				}
+			}
+		}
+		if len(outbound) == 0 {
+			break
+		}
+		best := ""
+		bestDelta := 0
+		for _, nodeID := range sortedNodes {
+			if _, ok := outbound[nodeID]; !ok {
+				continue
+			}
+			delta := len(outbound[nodeID]) - len(inbound[nodeID])
+			if best == "" || delta > bestDelta {
+				best, bestDelta = nodeID, delta
+			}
+		}
+		head = append(head, best)
+		remove(best)
+	}
+	slices.Reverse(tail)
+	return append(head, tail...)
+}
+
+// isReachable returns true if the destination can be reached from the source.
+func isReachable(connectionsFromNode map[string]map[string]struct{}, source, destination string) bool {
+	visited := map[string]struct{}{source: {}}
+	queue := []string{source}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == destination {
+			return true
+		}
+		for next := range connectionsFromNode[current] {
+			if _, ok := visited[next]; !ok {
+				visited[next] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+func (d *directedGraph[NodeType]) Condense() (DirectedGraph[[]string], map[string]string) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	c := newConfig(nil)
+	c.clock = d.config.clock
+	result := newDirectedGraph[[]string](c)
+	membership := make(map[string]string, len(d.nodes))
+	for _, component := range stronglyConnectedComponents(d.connectionsFromNode) {
+		// The IDs are sorted, so the first one identifies the component.
+		componentID := component[0]
+		for _, nodeID := range component {
+			membership[nodeID] = componentID
+		}
+		if _, err := result.AddNode(componentID, component); err != nil {
+			panic(fmt.Errorf("bug: failed to add component %s (%w)", componentID, err))
+		}
+	}
+	for source, destinations := range d.connectionsFromNode {
+		for destination := range destinations {
+			fromID, toID := membership[source], membership[destination]
+			if fromID == toID {
+				continue
+			}
+			if _, ok := result.connectionsFromNode[fromID][toID]; ok {
+				continue
+			}
+			if err := result.connect(fromID, toID, AndDependency); err != nil {
+				panic(fmt.Errorf("bug: failed to connect components %s and %s (%w)", fromID, toID, err))
+			}
+		}
+	}
+	return result, membership
+}
+
+// stronglyConnectedComponents finds the strongly connected components using Tarjan's algorithm. The node IDs
+// within each component are sorted, and the components are sorted by their first node ID.
+func stronglyConnectedComponents(connectionsFromNode map[string]map[string]struct{}) [][]string {
+	nodeIDs := make([]string, 0, len(connectionsFromNode))
+	for nodeID := range connectionsFromNode {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var result [][]string
+	var visit func(nodeID string)
+	visit = func(nodeID string) {
+		index[nodeID] = len(index)
+		lowLink[nodeID] = index[nodeID]
+		stack = append(stack, nodeID)
+		onStack[nodeID] = true
+		for next := range connectionsFromNode[nodeID] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowLink[nodeID] = min(lowLink[nodeID], lowLink[next])
+			} else if onStack[next] {
+				lowLink[nodeID] = min(lowLink[nodeID], index[next])
+			}
+		}
+		if lowLink[nodeID] != index[nodeID] {
+			return
+		}
+		var component []string
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component = append(component, member)
+			if member == nodeID {
+				break
+			}
+		}
+		slices.Sort(component)
+		result = append(result, component)
+	}
+	for _, nodeID := range nodeIDs {
+		if _, visited := index[nodeID]; !visited {
+			visit(nodeID)
+		}
+	}
+	slices.SortFunc(result, func(a, b []string) int {
+		return cmp.Compare(a[0], b[0])
+	})
+	return result
+}
@@ -0,0 +1,101 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_SuggestCycleBreaks(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	connect := func(from, to string, dependencyType dgraph.DependencyType) {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(to))
+		assert.NoError(t, n.ConnectDependency(from, dependencyType))
+	}
+	connect("a", "b", dgraph.AndDependency)
+	connect("b", "c", dgraph.AndDependency)
+	connect("c", "d", dgraph.AndDependency)
+	assert.Equals(t, d.SuggestCycleBreaks(), []dgraph.CycleBreak(nil))
+
+	// Two cycles sharing the a->b->c path, and a separate one between d and e.
+	connect("c", "a", dgraph.OrDependency)
+	connect("d", "a", dgraph.AndDependency)
+	connect("e", "d", dgraph.CompletionAndDependency)
+	connect("d", "e", dgraph.AndDependency)
+	assert.Equals(t, d.FindCycles(), [][]string{{"a", "b", "c", "d", "e"}})
+	suggestions := d.SuggestCycleBreaks()
+	assert.Equals(t, suggestions, []dgraph.CycleBreak{
+		{
+			SourceNodeID:      "b",
+			DestinationNodeID: "c",
+			DependencyType:    dgraph.AndDependency,
+			Alternative:       dgraph.CompletionAndDependency,
+		},
+		{
+			SourceNodeID:      "e",
+			DestinationNodeID: "d",
+			DependencyType:    dgraph.CompletionAndDependency,
+		},
+	})
+	for _, suggestion := range suggestions {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(suggestion.DestinationNodeID))
+		assert.NoError(t, n.DisconnectInbound(suggestion.SourceNodeID))
+	}
+	assert.Equals(t, d.HasCycles(), false)
+}
+
+func TestDirectedGraph_FindCycles(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	connect := func(from, to string) {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(to))
+		assert.NoError(t, n.ConnectDependency(from, dgraph.AndDependency))
+	}
+	connect("a", "b")
+	connect("b", "c")
+	assert.Equals(t, d.FindCycles(), [][]string(nil))
+
+	connect("c", "b")
+	connect("c", "d")
+	connect("e", "f")
+	connect("f", "e")
+	assert.Equals(t, d.FindCycles(), [][]string{{"b", "c"}, {"e", "f"}})
+}
+
+func TestDirectedGraph_Condense(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	connect := func(from, to string) {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(to))
+		assert.NoError(t, n.ConnectDependency(from, dgraph.OrDependency))
+	}
+	connect("a", "b")
+	connect("b", "c")
+	connect("c", "b")
+	connect("a", "c")
+	connect("c", "d")
+	connect("d", "e")
+	connect("e", "d")
+
+	condensed, membership := d.Condense()
+	assert.Equals(t, membership, map[string]string{"a": "a", "b": "b", "c": "b", "d": "d", "e": "d"})
+	assert.Equals(t, condensed.HasCycles(), false)
+	assert.Equals(t, len(condensed.ListNodes()), 3)
+	b := assert.NoErrorR[dgraph.Node[[]string]](t)(condensed.GetNodeByID("b"))
+	assert.Equals(t, b.Item(), []string{"b", "c"})
+	assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+	outbound := assert.NoErrorR[map[string]dgraph.Node[[]string]](t)(b.ListOutboundConnections())
+	assert.Equals(t, len(outbound), 1)
+	assert.MapContainsKey(t, "d", outbound)
+}
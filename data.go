@@ -0,0 +1,45 @@
+package dgraph
+
+// WithDataReadyCheck separates the resolution of a node from the availability of its data. A connection from a
+// Resolved node only counts as resolved for the dependent node once the check returns true for the source and
+// destination node IDs. If the check returns false when the source node is resolved, the dependency stays
+// outstanding until NotifyDataReady is called for the connection and the check passes. Unresolvable nodes are
+// propagated immediately, since their data never becomes available.
+//
+// The check is called while the graph is locked, so it must not call any function of the graph.
+func WithDataReadyCheck(check func(fromNodeID, toNodeID string) bool) Option {
+	return func(c *config) {
+		c.dataReadyCheck = check
+	}
+}
+
+func (d *directedGraph[NodeType]) NotifyDataReady(fromNodeID, toNodeID string) error {
+	fromNodeID = d.config.normalizeID(fromNodeID)
+	toNodeID = d.config.normalizeID(toNodeID)
+	d.lock.Lock()
+	defer d.unlock()
+	if _, ok := d.connectionsFromNode[fromNodeID][toNodeID]; !ok {
+		return &ErrConnectionDoesNotExist{fromNodeID, toNodeID}
+	}
+	connection := [2]string{fromNodeID, toNodeID}
+	if _, pending := d.pendingData[connection]; !pending {
+		// Either the source node has not been resolved yet, or the data was already available.
+		return nil
+	}
+	if !d.config.dataReadyCheck(fromNodeID, toNodeID) {
+		return nil
+	}
+	delete(d.pendingData, connection)
+	return d.nodes[toNodeID].dependencyResolved(fromNodeID, Resolved)
+}
+
+// isDataReady returns true if the resolution of the source node can be applied to the connection. If the data of a
+// Resolved source node is not available yet, the connection is recorded as pending and false is returned.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) isDataReady(fromNodeID, toNodeID string, status ResolutionStatus) bool {
+	if status != Resolved || d.config.dataReadyCheck == nil || d.config.dataReadyCheck(fromNodeID, toNodeID) {
+		return true
+	}
+	d.pendingData[[2]string{fromNodeID, toNodeID}] = struct{}{}
+	return false
+}
@@ -0,0 +1,42 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestWithDataReadyCheck(t *testing.T) {
+	available := map[[2]string]bool{}
+	d := dgraph.New[string](dgraph.WithDataReadyCheck(func(fromNodeID, toNodeID string) bool {
+		return available[[2]string{fromNodeID, toNodeID}]
+	}))
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"step", "consumer", "failing", "other"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["consumer"].ConnectDependency("step", dgraph.AndDependency))
+	assert.NoError(t, nodes["other"].ConnectDependency("failing", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, len(d.PopReadyNodes()), 2)
+
+	// The step completed, but its output is not available yet.
+	assert.NoError(t, nodes["step"].ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.Equals(t, nodes["consumer"].OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"step": dgraph.AndDependency,
+	})
+	assert.NoError(t, d.NotifyDataReady("step", "consumer"))
+	assert.Equals(t, d.HasReadyNodes(), false)
+
+	available[[2]string{"step", "consumer"}] = true
+	assert.NoError(t, d.NotifyDataReady("step", "consumer"))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"consumer": dgraph.Waiting})
+
+	// Unresolvable nodes don't wait for their data.
+	assert.NoError(t, nodes["failing"].ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, nodes["other"].ResolutionStatus(), dgraph.Unresolvable)
+
+	assert.InstanceOf[*dgraph.ErrConnectionDoesNotExist](t, d.NotifyDataReady("consumer", "step"))
+}
@@ -0,0 +1,85 @@
+package dgraph
+
+import (
+	"slices"
+	"time"
+)
+
+func (d *directedGraph[NodeType]) SetDeadline(deadline time.Time) {
+	d.lock.Lock()
+	defer d.unlock()
+	if d.deadlineExceeded {
+		return
+	}
+	d.deadline = deadline
+	d.advanceGeneration()
+	d.armDeadlineTimer()
+	d.checkDeadline()
+}
+
+func (d *directedGraph[NodeType]) Done() <-chan struct{} {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.done
+}
+
+// armDeadlineTimer replaces the deadline timer with one that fires when the current deadline is reached according
+// to the clock. If the clock has not reached the deadline when the timer fires, the timer is armed again.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) armDeadlineTimer() {
+	if d.deadlineTimer != nil {
+		d.deadlineTimer.Stop()
+		d.deadlineTimer = nil
+	}
+	if d.deadline.IsZero() || d.deadlineExceeded {
+		return
+	}
+	deadline := d.deadline
+	d.deadlineTimer = time.AfterFunc(deadline.Sub(d.config.clock()), func() {
+		d.lock.Lock()
+		defer d.unlock()
+		if !d.deadline.Equal(deadline) {
+			return
+		}
+		if !d.checkDeadline() {
+			d.armDeadlineTimer()
+		}
+	})
+}
+
+// checkDeadline resolves all waiting nodes as Unresolvable if the deadline has passed, and returns true if the
+// deadline has been exceeded. The waiting nodes are removed from the ready set, since they need no processing.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) checkDeadline() bool {
+	if d.deadlineExceeded {
+		return true
+	}
+	if d.deadline.IsZero() || d.config.clock().Before(d.deadline) {
+		return false
+	}
+	d.deadlineExceeded = true
+	if d.deadlineTimer != nil {
+		d.deadlineTimer.Stop()
+		d.deadlineTimer = nil
+	}
+	var waitingNodeIDs []string
+	for nodeID, n := range d.nodes {
+		if n.status == Waiting {
+			waitingNodeIDs = append(waitingNodeIDs, nodeID)
+		}
+	}
+	slices.Sort(waitingNodeIDs)
+	for _, nodeID := range waitingNodeIDs {
+		n := d.nodes[nodeID]
+		n.unresolvableCause = &ErrDeadlineExceeded{d.deadline}
+		// Resolving a node can already make the following nodes unresolvable, which then need no resolution.
+		if n.status == Waiting {
+			_ = n.resolveNode(Unresolvable)
+		}
+	}
+	for _, nodeID := range waitingNodeIDs {
+		d.removeReady(nodeID)
+	}
+	close(d.done)
+	return true
+}
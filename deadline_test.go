@@ -0,0 +1,98 @@
+package dgraph_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_SetDeadline(t *testing.T) {
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := dgraph.New[string](dgraph.WithClock(clock.Now))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+
+	deadline := clock.now.Add(time.Hour)
+	d.SetDeadline(deadline)
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	clock.Advance(time.Hour)
+
+	// The deadline is enforced before the resolution is applied.
+	var resolutionErr dgraph.ErrNodeResolutionAlreadySet
+	assert.Equals(t, errors.As(b.ResolveNode(dgraph.Resolved), &resolutionErr), true)
+	select {
+	case <-d.Done():
+	default:
+		t.Fatal("Done() was not closed after the deadline")
+	}
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{})
+	assert.Equals(t, a.ResolutionStatus(), dgraph.Resolved)
+	assert.Nil(t, a.UnresolvableCause())
+	for _, n := range []dgraph.Node[string]{b, c} {
+		assert.Equals(t, n.ResolutionStatus(), dgraph.Unresolvable)
+		var deadlineErr *dgraph.ErrDeadlineExceeded
+		assert.Equals(t, errors.As(n.UnresolvableCause(), &deadlineErr), true)
+		assert.Equals(t, deadlineErr.Deadline, deadline)
+	}
+}
+
+func TestDirectedGraph_SetDeadline_Timer(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	d.SetDeadline(time.Now().Add(10 * time.Millisecond))
+	select {
+	case <-d.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done() was not closed after the deadline")
+	}
+	assert.Equals(t, a.ResolutionStatus(), dgraph.Unresolvable)
+}
+
+func TestDirectedGraph_Done_Reset(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	var wg sync.WaitGroup
+	started := make(chan struct{})
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(started)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-d.Done():
+			default:
+			}
+		}
+	}()
+	<-started
+	for i := 0; i < 1000; i++ {
+		d.SetDeadline(time.Now().Add(-time.Second))
+		<-d.Done()
+		if i%2 == 0 {
+			d.ResetExecution()
+		} else {
+			d.Reset()
+			assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+		}
+		// A new run has a new channel, which is open until its own deadline is exceeded.
+		select {
+		case <-d.Done():
+			t.Fatal("Done() was closed after the reset")
+		default:
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,38 @@
+package dgraph
+
+// Degree is the number of inbound and outbound connections of a node. Self-loops are not counted.
+type Degree struct {
+	In  int
+	Out int
+}
+
+func (d *directedGraph[NodeType]) Degrees() map[string]Degree {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	result := make(map[string]Degree, len(d.nodes))
+	for nodeID := range d.nodes {
+		result[nodeID] = Degree{
+			In:  len(d.connectionsToNode[nodeID]),
+			Out: len(d.connectionsFromNode[nodeID]),
+		}
+	}
+	return result
+}
+
+func (n *node[NodeType]) InDegree() (int, error) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	if n.deleted {
+		return 0, &ErrNodeDeleted{n.id}
+	}
+	return len(n.dg.connectionsToNode[n.id]), nil
+}
+
+func (n *node[NodeType]) OutDegree() (int, error) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	if n.deleted {
+		return 0, &ErrNodeDeleted{n.id}
+	}
+	return len(n.dg.connectionsFromNode[n.id]), nil
+}
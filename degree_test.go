@@ -0,0 +1,25 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Degrees(t *testing.T) {
+	d := fragment(t, "item")
+	run := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("run"))
+	assert.Equals(t, assert.NoErrorR[int](t)(run.InDegree()), 1)
+	assert.Equals(t, assert.NoErrorR[int](t)(run.OutDegree()), 1)
+	assert.Equals(t, d.Degrees(), map[string]dgraph.Degree{
+		"input":   {In: 0, Out: 1},
+		"run":     {In: 1, Out: 1},
+		"outputs": {In: 1, Out: 0},
+	})
+
+	assert.NoError(t, run.Remove())
+	_, err := run.InDegree()
+	assert.InstanceOf[*dgraph.ErrNodeDeleted](t, err)
+	assert.Equals(t, d.Degrees()["input"], dgraph.Degree{})
+}
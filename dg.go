@@ -1,80 +1,180 @@
 package dgraph
 
 import (
-	"fmt"
+	"errors"
 	"maps"
-	"regexp"
+	"math/rand/v2"
 	"slices"
-	"strings"
 	"sync"
+	"time"
 )
 
 // New creates a new directed acyclic graph.
-func New[NodeType any]() DirectedGraph[NodeType] {
+func New[NodeType any](options ...Option) DirectedGraph[NodeType] {
+	return newDirectedGraph[NodeType](newConfig(options))
+}
+
+func newDirectedGraph[NodeType any](c config) *directedGraph[NodeType] {
 	return &directedGraph[NodeType]{
-		&sync.Mutex{},
-		map[string]*node[NodeType]{},
-		map[string]*node[NodeType]{},
-		map[string]map[string]struct{}{},
-		map[string]map[string]struct{}{},
+		config:               c,
+		lock:                 &sync.RWMutex{},
+		nodes:                map[string]*node[NodeType]{},
+		readyForProcessing:   c.newReadySet(),
+		heldReadyIDs:         map[string]struct{}{},
+		connectionsFromNode:  map[string]map[string]struct{}{},
+		connectionsToNode:    map[string]map[string]struct{}{},
+		changedNodes:         map[string]struct{}{},
+		groups:               map[string]*group[NodeType]{},
+		connectionSequence:   map[[2]string]uint64{},
+		connectionMetadata:   map[[2]string]map[string]string{},
+		connectionWeights:    map[[2]string]float64{},
+		connectionConditions: map[[2]string]DependencyCondition[NodeType]{},
+		resourcesInUse:       map[string]int{},
+		randomSource:         c.newRandomSource(),
+		pendingData:          map[[2]string]struct{}{},
+		dependencyStats:      map[DependencyType]DependencyTypeStats{},
+		done:                 make(chan struct{}),
 	}
 }
 
 type directedGraph[NodeType any] struct {
-	lock               *sync.Mutex
+	config config
+	// Functions that call back into user code never hold the lock while doing so. Listeners are queued and called
+	// through dispatch, iterators such as Nodes and Connections iterate over snapshots, and WalkBFS, WalkDFS, and
+	// PopReadyNodesWhere release the lock around each callback. Only the functions passed as options, such as the
+	// check of WithDataReadyCheck, the normalizer of WithIDNormalizer, and the ReadySet of WithReadySet, as well as
+	// the conditions passed to ConnectDependencyIf, are called with the lock held, so they must not call into the
+	// graph.
+	lock               *sync.RWMutex
 	nodes              map[string]*node[NodeType]
-	readyForProcessing map[string]*node[NodeType]
+	readyForProcessing ReadySet
+	// Ready nodes held back by the ready limit, in the order in which they became ready.
+	heldReady []*node[NodeType]
+	// Set of the IDs of the nodes in heldReady.
+	heldReadyIDs map[string]struct{}
 	// Map of the source nodes to a set of the destination nodes.
 	connectionsFromNode map[string]map[string]struct{}
 	// Map of the destination nodes to a set of the source nodes.
 	connectionsToNode map[string]map[string]struct{}
+	// Set of the nodes whose inbound connections changed since the last Reconcile() call.
+	changedNodes map[string]struct{}
+	// Set to true once PushStartingNodes() has been called.
+	started   bool
+	startedAt time.Time
+	groups    map[string]*group[NodeType]
+	// While true, no ready nodes are reported.
+	gateClosed bool
+	// IDs of the nodes in the order they were resolved.
+	resolutionOrder []string
+	// Cache of the cost of the longest downstream chain of each node. Reset on topology changes.
+	downstreamCosts map[string]float64
+	// Position of each connection in insertion order, only tracked if the connection order is preserved.
+	connectionSequence     map[[2]string]uint64
+	nextConnectionSequence uint64
+	// Metadata of the connections that have any.
+	connectionMetadata map[[2]string]map[string]string
+	// Weights of the connections that don't have the default weight.
+	connectionWeights map[[2]string]float64
+	// Conditions of the conditional dependencies. See Node.ConnectDependencyIf.
+	connectionConditions map[[2]string]DependencyCondition[NodeType]
+	// Number of tokens of each resource held by the nodes in the ready set or being processed.
+	resourcesInUse map[string]int
+	// Source of the processing order of map entries if WithSeed is used, nil otherwise.
+	randomSource *rand.PCG
+	// Connections from Resolved nodes whose data is not available yet. See WithDataReadyCheck.
+	pendingData map[[2]string]struct{}
+	// Audit counters of the dependencies by dependency type. See Stats.
+	dependencyStats map[DependencyType]DependencyTypeStats
+	// Deadline after which all waiting nodes are resolved as unresolvable. Zero if not set.
+	deadline         time.Time
+	deadlineTimer    *time.Timer
+	deadlineExceeded bool
+	// Closed once the deadline is exceeded.
+	done  chan struct{}
+	hooks hooks[NodeType]
+	// Listener calls for the events emitted while the lock is held, which are made once it is released.
+	pendingEvents []func()
+	// Guards the listener calls that are queued for dispatch, which are made without the graph lock.
+	dispatchLock  sync.Mutex
+	dispatchQueue []func()
+	// Set while a goroutine is making the queued listener calls. See dispatch.
+	dispatching bool
+	// Nodes resolved while the lock is held, whose terminal status listeners are queued before it is released.
+	pendingTerminal []*node[NodeType]
+	// Closed and cleared when nodes are added to the ready set. Only created while subscribers are waiting.
+	readyChanged chan struct{}
+	// Closed and cleared when an output node is resolved. Only created while WaitOutputs is waiting.
+	outputResolved chan struct{}
+	// Closed and cleared when a node stops being in flight. Only created while Drain is waiting.
+	inFlightChanged chan struct{}
+	// Incremented on every state change. See Generation.
+	generation uint64
+	// Snapshot returned by EdgeSet, reused while the generation doesn't change.
+	edgeSet *EdgeSet
 }
 
-var errorPathRegex, _ = regexp.Compile(`\.(?:error|crashed|failed|deploy_failed)$`)
-
-func (d *directedGraph[NodeType]) Mermaid() string {
+func (d *directedGraph[NodeType]) Clone() DirectedGraph[NodeType] {
 	d.lock.Lock()
-	defer d.lock.Unlock()
-
-	result := []string{
-		"%% Mermaid markdown workflow",
-		"flowchart LR",
-		"%% Success path",
-	}
-	var successPath, errorPath []string
-
-	for source, d := range d.connectionsFromNode {
-		for destination := range d {
-			isErrorPath := errorPathRegex.MatchString(destination)
-			connection := fmt.Sprintf("%s-->%s", source, destination)
-			if isErrorPath {
-				errorPath = append(errorPath, connection)
-			} else {
-				successPath = append(successPath, connection)
-			}
-		}
-	}
-
-	slices.Sort(successPath)
-	slices.Sort(errorPath)
-
-	result = append(result, successPath...)
-	result = append(result, "%% Error path")
-	result = append(result, errorPath...)
-	result = append(result, "%% Mermaid end")
-	return strings.Join(result, "\n") + "\n"
+	defer d.unlock()
+	return d.clone()
 }
 
-func (d *directedGraph[NodeType]) Clone() DirectedGraph[NodeType] {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// clone creates an independent copy of the graph, with a new ready set created by the ReadySet factory and a
+// running deadline timer.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) clone() *directedGraph[NodeType] {
+	newDG := d.copyState(d.config.newReadySet())
+	newDG.armDeadlineTimer()
+	return newDG
+}
 
+// copyState creates an independent copy of the state of the graph with the specified empty ready set. Unlike clone,
+// it has no side effects: the ReadySet factory isn't called and no deadline timer is started. It only reads the
+// graph, so the read lock is sufficient.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) copyState(readySet ReadySet) *directedGraph[NodeType] {
 	newDG := &directedGraph[NodeType]{
-		&sync.Mutex{},
-		make(map[string]*node[NodeType], len(d.nodes)),
-		make(map[string]*node[NodeType]), // Don't copy ready nodes.
-		d.cloneMap(d.connectionsFromNode),
-		d.cloneMap(d.connectionsToNode),
+		config:              d.config,
+		lock:                &sync.RWMutex{},
+		nodes:               make(map[string]*node[NodeType], len(d.nodes)),
+		readyForProcessing:  readySet, // Don't copy ready nodes.
+		heldReadyIDs:        map[string]struct{}{},
+		connectionsFromNode: d.cloneMap(d.connectionsFromNode),
+		connectionsToNode:   d.cloneMap(d.connectionsToNode),
+		changedNodes:        maps.Clone(d.changedNodes),
+		started:             d.started,
+		startedAt:           d.startedAt,
+		groups:              make(map[string]*group[NodeType], len(d.groups)),
+		resolutionOrder:     slices.Clone(d.resolutionOrder),
+		gateClosed:          d.gateClosed,
+		generation:          d.generation,
+	}
+	newDG.connectionSequence = maps.Clone(d.connectionSequence)
+	newDG.nextConnectionSequence = d.nextConnectionSequence
+	newDG.connectionMetadata = make(map[[2]string]map[string]string, len(d.connectionMetadata))
+	for connection, metadata := range d.connectionMetadata {
+		newDG.connectionMetadata[connection] = maps.Clone(metadata)
+	}
+	newDG.connectionWeights = maps.Clone(d.connectionWeights)
+	newDG.connectionConditions = maps.Clone(d.connectionConditions)
+	// Ready nodes are not copied, so the clone holds no resources.
+	newDG.resourcesInUse = map[string]int{}
+	newDG.randomSource = cloneRandomSource(d.randomSource)
+	newDG.pendingData = maps.Clone(d.pendingData)
+	newDG.dependencyStats = maps.Clone(d.dependencyStats)
+	newDG.done = make(chan struct{})
+	newDG.deadline = d.deadline
+	newDG.deadlineExceeded = d.deadlineExceeded
+	if newDG.deadlineExceeded {
+		close(newDG.done)
+	}
+	for name, g := range d.groups {
+		newDG.groups[name] = &group[NodeType]{
+			name:       name,
+			members:    maps.Clone(g.members),
+			dependents: maps.Clone(g.dependents),
+			dg:         newDG,
+		}
 	}
 
 	for nodeID, nodeData := range d.nodes {
@@ -87,7 +187,30 @@ func (d *directedGraph[NodeType]) Clone() DirectedGraph[NodeType] {
 			status:                  nodeData.status,
 			outstandingDependencies: maps.Clone(nodeData.outstandingDependencies),
 			resolvedDependencies:    maps.Clone(nodeData.resolvedDependencies),
+			failedDependencies:      maps.Clone(nodeData.failedDependencies),
+			resolutionHistory:       slices.Clone(nodeData.resolutionHistory),
+			satisfyingOrDependency:  nodeData.satisfyingOrDependency,
+			satisfactionTrace:       slices.Clone(nodeData.satisfactionTrace),
+			addedAt:                 nodeData.addedAt,
+			readyAt:                 nodeData.readyAt,
+			poppedAt:                nodeData.poppedAt,
+			resolvedAt:              nodeData.resolvedAt,
+			unresolvableCause:       nodeData.unresolvableCause,
+			reason:                  nodeData.reason,
+			readyReason:             nodeData.readyReason,
+			output:                  nodeData.output,
+			result:                  nodeData.result,
 		}
+		newDG.nodes[nodeID].resources = maps.Clone(nodeData.resources)
+		newDG.nodes[nodeID].annotations = slices.Clone(nodeData.annotations)
+		newDG.nodes[nodeID].attributes = maps.Clone(nodeData.attributes)
+		newDG.nodes[nodeID].description = nodeData.description
+		newDG.nodes[nodeID].selfLoop = nodeData.selfLoop
+		newDG.nodes[nodeID].iterations = nodeData.iterations
+		newDG.nodes[nodeID].external = nodeData.external
+		newDG.nodes[nodeID].expectedDuration = nodeData.expectedDuration
+		newDG.nodes[nodeID].obviatedTypes = maps.Clone(nodeData.obviatedTypes)
+		newDG.nodes[nodeID].expression = nodeData.cloneExpression()
 	}
 
 	return newDG
@@ -133,8 +256,22 @@ func (d *directedGraph[NodeType]) HasCycles() bool {
 }
 
 func (d *directedGraph[NodeType]) AddNode(id string, item NodeType) (Node[NodeType], error) {
+	id = d.config.normalizeID(id)
 	d.lock.Lock()
-	defer d.lock.Unlock()
+	defer d.unlock()
+	n, err := d.addNode(id, item)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// addNode adds a node with the specified normalized ID.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) addNode(id string, item NodeType) (*node[NodeType], error) {
+	if d.config.idPattern != nil && !d.config.idPattern.MatchString(id) {
+		return nil, &ErrInvalidNodeID{id, d.config.idPattern.String()}
+	}
 	if _, ok := d.nodes[id]; ok {
 		return nil, ErrNodeAlreadyExists{
 			id,
@@ -146,31 +283,34 @@ func (d *directedGraph[NodeType]) AddNode(id string, item NodeType) (Node[NodeTy
 		id:                      id,
 		item:                    item,
 		status:                  Waiting,
+		addedAt:                 d.config.clock(),
 		outstandingDependencies: make(map[string]DependencyType),
 		resolvedDependencies:    make(map[string]DependencyType),
+		failedDependencies:      make(map[string]DependencyType),
 		dg:                      d,
 	}
 	d.connectionsToNode[id] = map[string]struct{}{}
 	d.connectionsFromNode[id] = map[string]struct{}{}
+	d.markChanged(id)
+	d.emitNodeAdded(d.nodes[id])
 	return d.nodes[id], nil
 }
 
 func (d *directedGraph[NodeType]) GetNodeByID(id string) (Node[NodeType], error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+	id = d.config.normalizeID(id)
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 
 	n, ok := d.nodes[id]
 	if !ok {
-		return nil, &ErrNodeNotFound{
-			id,
-		}
+		return nil, d.nodeNotFound(id)
 	}
 	return n, nil
 }
 
 func (d *directedGraph[NodeType]) ListNodes() map[string]Node[NodeType] {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 
 	result := map[string]Node[NodeType]{}
 	for nodeID, n := range d.nodes {
@@ -179,9 +319,25 @@ func (d *directedGraph[NodeType]) ListNodes() map[string]Node[NodeType] {
 	return result
 }
 
+func (d *directedGraph[NodeType]) Nodes() func(yield func(string, Node[NodeType]) bool) {
+	d.lock.RLock()
+	nodes := make([]*node[NodeType], 0, len(d.nodes))
+	for _, n := range d.nodes {
+		nodes = append(nodes, n)
+	}
+	d.lock.RUnlock()
+	return func(yield func(string, Node[NodeType]) bool) {
+		for _, n := range nodes {
+			if !yield(n.id, n) {
+				return
+			}
+		}
+	}
+}
+
 func (d *directedGraph[NodeType]) ListNodesWithoutInboundConnections() map[string]Node[NodeType] {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 
 	result := map[string]Node[NodeType]{}
 	for nodeID, n := range d.nodes {
@@ -193,78 +349,174 @@ func (d *directedGraph[NodeType]) ListNodesWithoutInboundConnections() map[strin
 	return result
 }
 
+func (d *directedGraph[NodeType]) connectNodes(fromID, toID string, dependencyType DependencyType) error {
+	d.lock.Lock()
+	defer d.unlock()
+	return d.connect(fromID, toID, dependencyType)
+}
+
 // Validates the specified node IDs and confirms that a connection between them
 // would be valid, then sets the `to` and `from` connections and adds the
 // dependency to the `to` node.
-func (d *directedGraph[NodeType]) connectNodes(fromID, toID string, dependencyType DependencyType) error {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-	// Make sure both nodes exist and are not deleted.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) connect(fromID, toID string, dependencyType DependencyType) error {
+	if err := d.validateConnection(fromID, toID, dependencyType); err != nil {
+		return err
+	}
+	fromNode := d.nodes[fromID]
+	toNode := d.nodes[toID]
+	// Self-loops are only recorded on the node.
+	if fromID == toID {
+		toNode.selfLoop = true
+		return nil
+	}
+	// Apply the rollback policy for nodes that are already ready but not yet popped. The reject policy is applied by
+	// validateConnection.
+	if d.config.readyNodeConnectionPolicy == ReadyNodeConnectionRollback && d.affectsPendingReady(toNode, dependencyType) {
+		d.removeReady(toID)
+		toNode.ready = false
+		toNode.readyReason = ""
+	}
+	// Update the mappings.
+	d.connectionsFromNode[fromID][toID] = struct{}{}
+	d.connectionsToNode[toID][fromID] = struct{}{}
+	d.recordConnection(fromID, toID)
+	// Update the dependencies
+	toNode.outstandingDependencies[fromID] = dependencyType
+	delete(toNode.obviatedTypes, fromID)
+	d.markChanged(toID)
+	d.emitConnect(fromNode, toNode, dependencyType)
+	return nil
+}
+
+// validateConnection checks that both nodes exist and are not deleted, that the connection is neither a
+// disallowed self-connection nor a duplicate, and that it is not refused by the ReadyNodeConnectionReject policy.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) validateConnection(fromID, toID string, dependencyType DependencyType) error {
 	fromNode, ok := d.nodes[fromID]
 	if !ok {
-		return &ErrNodeNotFound{fromID}
+		return d.nodeNotFound(fromID)
 	} else if fromNode.deleted {
 		return &ErrNodeDeleted{fromID}
 	}
 	toNode, ok := d.nodes[toID]
 	if !ok {
-		return &ErrNodeNotFound{toID}
+		return d.nodeNotFound(toID)
 	} else if toNode.deleted {
 		return &ErrNodeDeleted{toID}
 	}
-	// Check that it's a non-self and non-duplicate connection.
 	if fromID == toID {
-		return &ErrCannotConnectToSelf{fromID}
+		if !d.config.selfLoops || dependencyType != CompletionAndDependency {
+			return &ErrCannotConnectToSelf{fromID}
+		}
+		if toNode.selfLoop {
+			return &ErrConnectionAlreadyExists{fromID, toID}
+		}
+		return nil
 	}
 	if _, ok := d.connectionsFromNode[fromID][toID]; ok {
 		return &ErrConnectionAlreadyExists{fromID, toID}
 	}
-	// Update the mappings.
-	d.connectionsFromNode[fromID][toID] = struct{}{}
-	d.connectionsToNode[toID][fromID] = struct{}{}
-	// Update the dependencies
-	toNode.outstandingDependencies[fromID] = dependencyType
+	if d.config.readyNodeConnectionPolicy == ReadyNodeConnectionReject && d.affectsPendingReady(toNode, dependencyType) {
+		return &ErrNodeAlreadyReady{toID, fromID}
+	}
 	return nil
 }
 
+// affectsPendingReady returns true if a dependency of the specified type would hold back the node if it wasn't
+// already ready, and the node is ready but not yet popped, so the ReadyNodeConnectionPolicy applies.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) affectsPendingReady(toNode *node[NodeType], dependencyType DependencyType) bool {
+	return d.isPendingReady(toNode.id) && toNode.status == Waiting && isHardDependency(dependencyType)
+}
+
 func (d *directedGraph[NodeType]) PushStartingNodes() error {
 	d.lock.Lock()
-	defer d.lock.Unlock()
+	defer d.unlock()
+	d.pushStartingNodes()
+	return nil
+}
 
+// pushStartingNodes starts the graph and marks the nodes without required dependencies as ready.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) pushStartingNodes() {
+	d.started = true
+	d.advanceGeneration()
+	d.startedAt = d.config.clock()
+
+	// Sorted, so that starting nodes held back by the ready limit are released in a predictable order.
+	nodeIDs := make([]string, 0, len(d.nodes))
+	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
 nextNode:
-	for nodeID, n := range d.nodes {
+	for _, nodeID := range nodeIDs {
+		n := d.nodes[nodeID]
+		if n.external {
+			continue
+		}
 		for _, dependency := range n.outstandingDependencies {
 			if isHardDependency(dependency) {
 				continue nextNode
 			}
 		}
-		d.readyForProcessing[nodeID] = n
+		n.ready = true
+		n.readyReason = ReadyNoDependencies
+		d.pushReady(n)
+	}
+}
+
+// markChanged records that the inbound connections of the node changed.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) markChanged(nodeID string) {
+	d.changedNodes[nodeID] = struct{}{}
+	d.downstreamCosts = nil
+	d.advanceGeneration()
+}
+
+// isDependencyFailed returns true if the resolution of a dependency with the specified type cannot satisfy the
+// dependency. Completion-AND dependencies are satisfied by any resolution, and on-unresolvable dependencies are only
+// satisfied if the dependency is unresolvable.
+func isDependencyFailed(dependencyType DependencyType, dependencyResolution ResolutionStatus) bool {
+	switch dependencyType {
+	case CompletionAndDependency:
+		return false
+	case OnUnresolvableDependency:
+		return dependencyResolution != Unresolvable
+	default:
+		return dependencyResolution == Unresolvable
 	}
-	return nil
 }
 
 func isHardDependency(dependencyType DependencyType) bool {
 	return dependencyType != ObviatedDependency && dependencyType != OptionalDependency
 }
 
+func (d *directedGraph[NodeType]) ResolutionOrder() []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return slices.Clone(d.resolutionOrder)
+}
+
 func (d *directedGraph[NodeType]) HasReadyNodes() bool {
 	d.lock.Lock()
-	defer d.lock.Unlock()
-	return len(d.readyForProcessing) != 0
+	defer d.unlock()
+	d.checkDeadline()
+	return !d.gateClosed && d.readyForProcessing.Len() != 0
 }
 
 func (d *directedGraph[NodeType]) PopReadyNodes() map[string]ResolutionStatus {
-	result := make(map[string]ResolutionStatus)
 	// The statuses may be modified while or after this function is called,
 	// so this needs to be done under lock to satisfy the go race detector.
 	// For example, a ready waiting node being marked Resolved or Unresolvable by
 	// a user that retrieves the node by ID.
 	d.lock.Lock()
-	defer d.lock.Unlock()
-	for _, node := range d.readyForProcessing {
-		result[node.ID()] = node.status
+	defer d.unlock()
+	result := make(map[string]ResolutionStatus)
+	for _, n := range d.popReady(-1, "") {
+		result[n.id] = n.status
 	}
-	clear(d.readyForProcessing)
 	return result
 }
 
@@ -276,7 +528,33 @@ type node[NodeType any] struct {
 	status                  ResolutionStatus
 	outstandingDependencies map[string]DependencyType
 	resolvedDependencies    map[string]DependencyType
-	dg                      *directedGraph[NodeType]
+	failedDependencies      map[string]DependencyType
+	resolutionHistory       []ResolvedDependency
+	addedAt                 time.Time
+	readyAt                 time.Time
+	poppedAt                time.Time
+	resolvedAt              time.Time
+	satisfyingOrDependency  string
+	satisfactionTrace       []DependencyEvent
+	unresolvableCause       error
+	reason                  error
+	readyReason             ReadyReason
+	output                  bool
+	result                  any
+	resources               map[string]int
+	holdsResources          bool
+	annotations             []string
+	description             string
+	attributes              map[string]string
+	selfLoop                bool
+	iterations              int
+	external                bool
+	expectedDuration        time.Duration
+	// Expression of the ExpressionDependency dependencies, or nil. See Node.ConnectExpression.
+	expression *Expression
+	// Original types of the dependencies that were changed to ObviatedDependency.
+	obviatedTypes map[string]DependencyType
+	dg            *directedGraph[NodeType]
 }
 
 func (n *node[NodeType]) ID() string {
@@ -287,24 +565,99 @@ func (n *node[NodeType]) Item() NodeType {
 	return n.item
 }
 
+func (n *node[NodeType]) ResolutionStatus() ResolutionStatus {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.status
+}
+
+func (n *node[NodeType]) UnresolvableCause() error {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.unresolvableCause
+}
+
+func (n *node[NodeType]) IsReady() bool {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.ready
+}
+
 func (n *node[NodeType]) OutstandingDependencies() map[string]DependencyType {
-	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
 	return maps.Clone(n.outstandingDependencies)
 }
 
 func (n *node[NodeType]) ResolvedDependencies() map[string]DependencyType {
-	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
 	return maps.Clone(n.resolvedDependencies)
 }
 
+func (n *node[NodeType]) ResolvedDependencyHistory() []ResolvedDependency {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return slices.Clone(n.resolutionHistory)
+}
+
+func (n *node[NodeType]) SatisfyingOrDependency() (string, bool) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.satisfyingOrDependency, n.satisfyingOrDependency != ""
+}
+
+func (n *node[NodeType]) SatisfactionTrace() []DependencyEvent {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return slices.Clone(n.satisfactionTrace)
+}
+
+func (n *node[NodeType]) DependencyReport() DependencyReport {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	report := DependencyReport{
+		Resolved:    map[string]DependencyState{},
+		Outstanding: map[string]DependencyState{},
+		Obviated:    map[string]DependencyState{},
+	}
+	add := func(target map[string]DependencyState, dependencyNodeID string, dependencyType DependencyType) {
+		if dependencyType == ObviatedDependency {
+			target = report.Obviated
+		}
+		var status ResolutionStatus
+		if dependencyNode, ok := n.dg.nodes[dependencyNodeID]; ok {
+			status = dependencyNode.status
+		}
+		target[dependencyNodeID] = DependencyState{dependencyType, status}
+	}
+	for dependencyNodeID, dependencyType := range n.resolvedDependencies {
+		add(report.Resolved, dependencyNodeID, dependencyType)
+	}
+	for dependencyNodeID, dependencyType := range n.failedDependencies {
+		add(report.Resolved, dependencyNodeID, dependencyType)
+	}
+	for dependencyNodeID, dependencyType := range n.outstandingDependencies {
+		add(report.Outstanding, dependencyNodeID, dependencyType)
+	}
+	// Cancelled nodes no longer wait for the dependencies they didn't receive a resolution of.
+	if n.status == Cancelled {
+		for dependencyNodeID := range n.dg.connectionsToNode[n.id] {
+			if _, ok := report.Resolved[dependencyNodeID]; !ok {
+				add(report.Obviated, dependencyNodeID, ObviatedDependency)
+			}
+		}
+	}
+	return report
+}
+
 // ResolveNode is the externally accessible way to resolve the node.
 // This function will take care of the locking, then call the internal
 // resolveNode function.
 func (n *node[NodeType]) ResolveNode(status ResolutionStatus) error {
 	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	defer n.dg.unlock()
+	n.dg.checkDeadline()
 	return n.resolveNode(status)
 }
 
@@ -314,7 +667,7 @@ func (n *node[NodeType]) resolveNode(newStatus ResolutionStatus) error {
 		return ErrNodeDeleted{n.id}
 	}
 	if n.status != Waiting {
-		if n.status == Resolved || n.status == Unresolvable && newStatus != Unresolvable {
+		if n.status == Resolved || n.status == Cancelled || n.status == Unresolvable && newStatus != Unresolvable {
 			return ErrNodeResolutionAlreadySet{n.id, n.status, newStatus}
 		} else if n.status == Unresolvable {
 			return nil // Allow nodes to be unresolved multiple times. But no processing is required.
@@ -323,88 +676,151 @@ func (n *node[NodeType]) resolveNode(newStatus ResolutionStatus) error {
 		}
 	}
 	n.status = newStatus
+	n.dg.advanceGeneration()
 	if newStatus == Waiting {
 		return nil // Don't propagate a waiting status.
 	}
-	// Propagate to outbound connections.
-	outboundConnections := n.dg.connectionsFromNode[n.ID()]
-	for outboundConnectionID := range outboundConnections {
+	n.dg.resolutionOrder = append(n.dg.resolutionOrder, n.id)
+	if n.dg.releaseResources(n) {
+		n.dg.releaseHeldReady()
+	}
+	n.resolvedAt = n.dg.config.clock()
+	n.dg.emitNodeResolved(n, newStatus)
+	n.dg.notifyInFlightChanged()
+	if n.output {
+		n.dg.notifyOutputResolved()
+	}
+	// Propagate to outbound connections. A failure to notify one dependent must not leave the others behind, so
+	// all of them are notified before the errors are returned.
+	var errs []error
+	for _, outboundConnectionID := range seededKeys(n.dg.randomSource, n.dg.connectionsFromNode[n.id]) {
+		if !n.dg.isDataReady(n.id, outboundConnectionID, newStatus) {
+			continue
+		}
 		err := n.dg.nodes[outboundConnectionID].dependencyResolved(n.ID(), newStatus)
 		if err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if newStatus == Unresolvable && n.dg.config.optionalObviation {
+		return n.obviateOptionalDependents()
+	}
 	return nil
 }
 
 // Connect connects forward from the called node to the node with the ID specified
 // in fromNodeID. It has an AndDependency type for legacy reasons.
 func (n *node[NodeType]) Connect(nodeID string) error {
-	return n.dg.connectNodes(n.id, nodeID, AndDependency)
+	return n.dg.connectNodes(n.id, n.dg.config.normalizeID(nodeID), AndDependency)
 }
 
 // ConnectDependency connects backward and sets a dependency. The connection is made
 // from the node with the ID specified to the called node.
 func (n *node[NodeType]) ConnectDependency(fromNodeID string, dependencyType DependencyType) error {
-	return n.dg.connectNodes(fromNodeID, n.id, dependencyType)
+	return n.dg.connectNodes(n.dg.config.normalizeID(fromNodeID), n.id, dependencyType)
 }
 
 func (n *node[NodeType]) DisconnectInbound(fromNodeID string) error {
+	fromNodeID = n.dg.config.normalizeID(fromNodeID)
 	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	defer n.dg.unlock()
 	if n.deleted {
 		return &ErrNodeDeleted{n.id}
 	}
+	if fromNodeID == n.id && n.selfLoop {
+		n.selfLoop = false
+		return nil
+	}
 	if _, ok := n.dg.nodes[fromNodeID]; !ok {
-		return &ErrNodeNotFound{fromNodeID}
+		return n.dg.nodeNotFound(fromNodeID)
 	}
 	if _, ok := n.dg.connectionsToNode[n.id][fromNodeID]; !ok {
 		return &ErrConnectionDoesNotExist{n.id, fromNodeID}
 	}
-	delete(n.dg.connectionsToNode[n.id], fromNodeID)
-	delete(n.dg.connectionsFromNode[fromNodeID], n.id)
+	n.dg.removeConnection(fromNodeID, n.id)
 	return nil
 }
 
 func (n *node[NodeType]) DisconnectOutbound(toNodeID string) error {
+	toNodeID = n.dg.config.normalizeID(toNodeID)
 	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	defer n.dg.unlock()
 	if n.deleted {
 		return &ErrNodeDeleted{n.id}
 	}
+	if toNodeID == n.id && n.selfLoop {
+		n.selfLoop = false
+		return nil
+	}
 	if _, ok := n.dg.nodes[toNodeID]; !ok {
-		return &ErrNodeNotFound{toNodeID}
+		return n.dg.nodeNotFound(toNodeID)
 	}
 	if _, ok := n.dg.connectionsFromNode[n.id][toNodeID]; !ok {
 		return &ErrConnectionDoesNotExist{n.id, toNodeID}
 	}
-	delete(n.dg.connectionsFromNode[n.id], toNodeID)
-	delete(n.dg.connectionsToNode[toNodeID], n.id)
+	n.dg.removeConnection(n.id, toNodeID)
 	return nil
 }
 
+// removeConnection removes an existing connection. The outstanding dependencies are updated by Reconcile.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) removeConnection(fromID, toID string) {
+	delete(d.connectionsFromNode[fromID], toID)
+	delete(d.connectionsToNode[toID], fromID)
+	d.forgetConnection(fromID, toID)
+	d.markChanged(toID)
+}
+
 func (n *node[NodeType]) Remove() error {
 	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	defer n.dg.unlock()
 	if n.deleted {
 		return &ErrNodeDeleted{n.id}
 	}
+	n.remove()
+	return nil
+}
+
+// remove removes the node and all of its connections from the graph.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) remove() {
+	n.dg.removeReady(n.id)
 	for toNodeID := range n.dg.connectionsFromNode[n.id] {
 		delete(n.dg.connectionsToNode[toNodeID], n.id)
+		n.dg.forgetConnection(n.id, toNodeID)
+		n.dg.markChanged(toNodeID)
 	}
 	delete(n.dg.connectionsFromNode, n.id)
 	for fromNodeID := range n.dg.connectionsToNode[n.id] {
 		delete(n.dg.connectionsFromNode[fromNodeID], n.id)
+		n.dg.forgetConnection(fromNodeID, n.id)
 	}
 	delete(n.dg.connectionsToNode, n.id)
+	for _, g := range n.dg.groups {
+		delete(g.members, n.id)
+		delete(g.dependents, n.id)
+	}
 	delete(n.dg.nodes, n.id)
+	delete(n.dg.changedNodes, n.id)
+	n.dg.downstreamCosts = nil
+	n.dg.advanceGeneration()
+	if n.dg.releaseResources(n) {
+		n.dg.releaseHeldReady()
+	}
 	n.deleted = true
-	return nil
+	n.dg.notifyInFlightChanged()
+	if n.output {
+		// The remaining outputs may all be final now.
+		n.dg.notifyOutputResolved()
+	}
 }
 
 func (n *node[NodeType]) ListInboundConnections() (map[string]Node[NodeType], error) {
-	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
 	if n.deleted {
 		return nil, &ErrNodeDeleted{n.id}
 	}
@@ -416,8 +832,8 @@ func (n *node[NodeType]) ListInboundConnections() (map[string]Node[NodeType], er
 }
 
 func (n *node[NodeType]) ListOutboundConnections() (map[string]Node[NodeType], error) {
-	n.dg.lock.Lock()
-	defer n.dg.lock.Unlock()
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
 	if n.deleted {
 		return nil, &ErrNodeDeleted{n.id}
 	}
@@ -439,36 +855,63 @@ func (n *node[NodeType]) dependencyResolved(dependencyNodeID string, dependencyR
 		// Illegal state
 		return ErrNotifiedOfWaiting{n.id, dependencyNodeID}
 	}
+	if n.status != Waiting {
+		n.dependencyIgnored(dependencyNodeID, dependencyResolution)
+		return nil
+	}
 	dependencyType, isOutstandingDependency := n.outstandingDependencies[dependencyNodeID]
 	if !isOutstandingDependency {
 		// Now determine if the missing item was because the dependency was already resolved, or
 		// because there was never a connection.
 		_, isConnected := n.dg.connectionsToNode[n.id][dependencyNodeID]
-		if isConnected {
-			// As designed, this is an internal function. So we guard against this in resolveNode.
-			panic(ErrDuplicateDependencyResolution{n.id, dependencyNodeID})
-		} else {
-			panic(ErrConnectionDoesNotExist{dependencyNodeID, n.id})
+		// Both cases indicate that the notification raced with a change to the connections, so they are
+		// reported to the caller instead of treated as impossible states.
+		if !isConnected {
+			return &ErrConnectionDoesNotExist{dependencyNodeID, n.id}
+		}
+		return &ErrDuplicateDependencyResolution{
+			NodeID:           n.id,
+			DependencyID:     dependencyNodeID,
+			DependencyType:   n.dependencyType(dependencyNodeID),
+			NodeStatus:       n.status,
+			DependencyStatus: dependencyResolution,
 		}
 	}
 	if dependencyResolution == Resolved {
-		n.resolvedDependencies[dependencyNodeID] = dependencyType
+		n.recordResolvedDependency(dependencyNodeID, dependencyType)
+	} else {
+		n.failedDependencies[dependencyNodeID] = dependencyType
 	}
 	delete(n.outstandingDependencies, dependencyNodeID)
+	n.dg.advanceGeneration()
 	if !isHardDependency(dependencyType) {
+		if dependencyType == ObviatedDependency {
+			n.traceDependency(dependencyNodeID, dependencyType, dependencyResolution, DependencyObviated)
+		} else {
+			n.traceDependency(dependencyNodeID, dependencyType, dependencyResolution, DependencyIgnored)
+		}
 		return nil // Nothing to do.
 	}
-	// If the dependency is unresolvable, mark self as unresolvable if current type is AND,
+	if dependencyType == ExpressionDependency && n.expression != nil {
+		return n.expressionDependencyResolved(dependencyNodeID, dependencyResolution)
+	}
+	// If the dependency failed, mark self as unresolvable if current type is not OR,
 	// or if there are no remaining OR dependencies.
-	// Treat as resolved if is just a completion-AND dependency.
-	if dependencyResolution == Unresolvable && dependencyType != CompletionAndDependency {
+	if n.isDependencyFailed(dependencyNodeID, dependencyType, dependencyResolution) {
 		// Check for the unresolvable case.
-		if dependencyType == AndDependency || !n.hasOutstandingDependency(OrDependency) {
+		if dependencyType != OrDependency || !n.hasOutstandingDependency(OrDependency) {
 			// Missing requirement. Mark as unresolvable, which propagates to outbound connections.
-			n.markReady()
+			n.traceDependency(dependencyNodeID, dependencyType, dependencyResolution, DependencyFailed)
+			if n.unresolvableCause == nil {
+				n.unresolvableCause = n.dependencyFailureCause(dependencyNodeID, dependencyType, dependencyResolution)
+			}
+			n.markReady(ReadyUnresolvableDependency)
 			return n.resolveNode(Unresolvable)
 		}
+		// Other OR dependencies can still satisfy the node.
+		n.traceDependency(dependencyNodeID, dependencyType, dependencyResolution, DependencyIgnored)
 	} else {
+		n.traceDependency(dependencyNodeID, dependencyType, dependencyResolution, DependencySatisfied)
 		var hasOrDependency bool
 		if dependencyType == OrDependency {
 			n.markObviated(OrDependency)
@@ -476,22 +919,83 @@ func (n *node[NodeType]) dependencyResolved(dependencyNodeID string, dependencyR
 		} else {
 			hasOrDependency = n.hasOutstandingDependency(OrDependency)
 		}
-		hasAndDependency := n.hasOutstandingDependency(AndDependency) || n.hasOutstandingDependency(CompletionAndDependency)
+		hasAndDependency := n.hasOutstandingDependency(AndDependency) ||
+			n.hasOutstandingDependency(CompletionAndDependency) ||
+			n.hasOutstandingDependency(OnUnresolvableDependency) ||
+			n.hasOutstandingDependency(ExpressionDependency)
 		// Now determine if it's ready to be finalized (no more deferred dependencies).
 		if !(hasAndDependency || hasOrDependency) {
 			// Mark as ready for processing internally and in the DAG.
-			n.markReady()
+			if dependencyType == OrDependency {
+				n.markReady(ReadyOrSatisfied)
+			} else {
+				n.markReady(ReadyDependenciesResolved)
+			}
 		}
 	}
 	return nil
 }
 
+// dependencyIgnored records the resolution of a dependency of a node that is no longer Waiting, without changing
+// the readiness or the status of the node. Cancelled nodes have no outstanding dependencies left to record.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) dependencyIgnored(dependencyNodeID string, dependencyResolution ResolutionStatus) {
+	dependencyType, isOutstandingDependency := n.outstandingDependencies[dependencyNodeID]
+	if !isOutstandingDependency {
+		return
+	}
+	delete(n.outstandingDependencies, dependencyNodeID)
+	if dependencyResolution == Resolved {
+		n.recordResolvedDependency(dependencyNodeID, dependencyType)
+	} else {
+		n.failedDependencies[dependencyNodeID] = dependencyType
+	}
+	n.dg.advanceGeneration()
+	n.traceDependency(dependencyNodeID, dependencyType, dependencyResolution, DependencyIgnored)
+}
+
+// Records a resolved dependency in the resolved dependencies and their history.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) recordResolvedDependency(dependencyNodeID string, dependencyType DependencyType) {
+	n.resolvedDependencies[dependencyNodeID] = dependencyType
+	n.resolutionHistory = append(n.resolutionHistory, ResolvedDependency{
+		NodeID:         dependencyNodeID,
+		DependencyType: dependencyType,
+		Order:          len(n.resolutionHistory),
+		ResolvedAt:     n.dg.config.clock(),
+	})
+	if dependencyType == OrDependency {
+		n.satisfyingOrDependency = dependencyNodeID
+	}
+}
+
+// Records a dependency resolution in the satisfaction trace.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) traceDependency(
+	dependencyNodeID string,
+	dependencyType DependencyType,
+	dependencyResolution ResolutionStatus,
+	effect DependencyEffect,
+) {
+	// Obviated dependencies are counted when they are obviated, under their original type.
+	if effect != DependencyObviated {
+		n.dg.countDependency(dependencyType, effect)
+	}
+	n.satisfactionTrace = append(n.satisfactionTrace, DependencyEvent{
+		DependencyID:   dependencyNodeID,
+		DependencyType: dependencyType,
+		Status:         dependencyResolution,
+		Effect:         effect,
+	})
+}
+
 // Marks a node as ready, and marks all outstanding optional dependencies as obviated.
 // Caller should have appropriate mutex locked before calling.
-func (n *node[NodeType]) markReady() {
+func (n *node[NodeType]) markReady(reason ReadyReason) {
 	n.markObviated(OptionalDependency)
 	n.ready = true
-	n.dg.readyForProcessing[n.id] = n
+	n.readyReason = reason
+	n.dg.pushReady(n)
 }
 
 // Caller should have appropriate mutex locked before calling.
@@ -499,10 +1003,28 @@ func (n *node[NodeType]) markObviated(typeToMark DependencyType) {
 	for dependency, dependencyType := range n.outstandingDependencies {
 		if dependencyType == typeToMark {
 			n.outstandingDependencies[dependency] = ObviatedDependency
+			if n.obviatedTypes == nil {
+				n.obviatedTypes = map[string]DependencyType{}
+			}
+			n.obviatedTypes[dependency] = typeToMark
+			n.dg.countDependency(typeToMark, DependencyObviated)
 		}
 	}
 }
 
+// dependencyType returns the type of the dependency on the specified node, whether it is outstanding, resolved, or
+// failed.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) dependencyType(dependencyNodeID string) DependencyType {
+	if dependencyType, ok := n.outstandingDependencies[dependencyNodeID]; ok {
+		return dependencyType
+	}
+	if dependencyType, ok := n.resolvedDependencies[dependencyNodeID]; ok {
+		return dependencyType
+	}
+	return n.failedDependencies[dependencyNodeID]
+}
+
 // Caller should have appropriate mutex locked before calling.
 func (n *node[NodeType]) hasOutstandingDependency(expectedDependencyType DependencyType) bool {
 	for _, dependencyType := range n.outstandingDependencies {
@@ -512,3 +1034,13 @@ func (n *node[NodeType]) hasOutstandingDependency(expectedDependencyType Depende
 	}
 	return false
 }
+
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) hasOutstandingHardDependency() bool {
+	for _, dependencyType := range n.outstandingDependencies {
+		if isHardDependency(dependencyType) {
+			return true
+		}
+	}
+	return false
+}
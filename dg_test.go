@@ -1,6 +1,10 @@
 package dgraph_test
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"go.arcalot.io/assert"
@@ -145,6 +149,31 @@ func TestDirectedGraph_CloneWithReadyCheck(t *testing.T) {
 	assert.Equals(t, d2.HasReadyNodes(), true)
 }
 
+func TestDirectedGraph_CloneChecked(t *testing.T) {
+	d1 := dgraph.New[string]()
+	d1n1, err := d1.AddNode("node-1", "test1")
+	assert.NoError(t, err)
+	_, err = d1.AddNode("node-2", "test2")
+	assert.NoError(t, err)
+	assert.NoError(t, d1n1.ConnectDependency("node-2", dgraph.OrDependency))
+	group, err := d1.AddGroup("group")
+	assert.NoError(t, err)
+	assert.NoError(t, group.AddMember("node-1"))
+
+	d2, err := d1.CloneChecked()
+	assert.NoError(t, err)
+	d2n1, err := d2.GetNodeByID("node-1")
+	assert.NoError(t, err)
+	assert.NoError(t, d2n1.DisconnectInbound("node-2"))
+
+	d1Inbound, err := d1n1.ListInboundConnections()
+	assert.NoError(t, err)
+	assert.Equals(t, len(d1Inbound), 1)
+	d2Inbound, err := d2n1.ListInboundConnections()
+	assert.NoError(t, err)
+	assert.Equals(t, len(d2Inbound), 0)
+}
+
 func TestDirectedGraph_HasCycles(t *testing.T) {
 	d := dgraph.New[string]()
 	n1, err := d.AddNode("node-1", "test1")
@@ -1027,3 +1056,293 @@ steps.example.starting-->steps.example.starting.started
 
 	assert.Equals(t, d.Mermaid(), expected)
 }
+
+func TestDirectedGraph_ResolvedDependencyHistory(t *testing.T) {
+	d := dgraph.New[string]()
+	rootNode, err := d.AddNode("root", "root")
+	assert.NoError(t, err)
+	for _, id := range []string{"or_1", "or_2", "and_1"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, rootNode.ConnectDependency("or_1", dgraph.OrDependency))
+	assert.NoError(t, rootNode.ConnectDependency("or_2", dgraph.OrDependency))
+	assert.NoError(t, rootNode.ConnectDependency("and_1", dgraph.AndDependency))
+	_, ok := rootNode.SatisfyingOrDependency()
+	assert.Equals(t, ok, false)
+
+	for _, id := range []string{"and_1", "or_2", "or_1"} {
+		n, err := d.GetNodeByID(id)
+		assert.NoError(t, err)
+		assert.NoError(t, n.ResolveNode(dgraph.Resolved))
+	}
+	satisfiedBy, ok := rootNode.SatisfyingOrDependency()
+	assert.Equals(t, ok, true)
+	assert.Equals(t, satisfiedBy, "or_2")
+
+	history := rootNode.ResolvedDependencyHistory()
+	assert.Equals(t, len(history), 3)
+	expected := []struct {
+		id             string
+		dependencyType dgraph.DependencyType
+	}{
+		{"and_1", dgraph.AndDependency},
+		{"or_2", dgraph.OrDependency},
+		{"or_1", dgraph.ObviatedDependency},
+	}
+	for i, entry := range history {
+		assert.Equals(t, entry.Order, i)
+		assert.Equals(t, entry.NodeID, expected[i].id)
+		assert.Equals(t, entry.DependencyType, expected[i].dependencyType)
+		if i > 0 {
+			assert.Equals(t, entry.ResolvedAt.Before(history[i-1].ResolvedAt), false)
+		}
+	}
+}
+
+func TestDirectedGraph_SatisfactionTrace(t *testing.T) {
+	d := dgraph.New[string]()
+	rootNode, err := d.AddNode("root", "root")
+	assert.NoError(t, err)
+	dependencies := map[string]dgraph.DependencyType{
+		"or_1":       dgraph.OrDependency,
+		"or_2":       dgraph.OrDependency,
+		"or_3":       dgraph.OrDependency,
+		"optional_1": dgraph.OptionalDependency,
+	}
+	for id, dependencyType := range dependencies {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+		assert.NoError(t, rootNode.ConnectDependency(id, dependencyType))
+	}
+	resolutions := []struct {
+		id     string
+		status dgraph.ResolutionStatus
+	}{
+		{"optional_1", dgraph.Resolved},
+		{"or_1", dgraph.Unresolvable},
+		{"or_2", dgraph.Resolved},
+		{"or_3", dgraph.Resolved},
+	}
+	for _, resolution := range resolutions {
+		n, err := d.GetNodeByID(resolution.id)
+		assert.NoError(t, err)
+		assert.NoError(t, n.ResolveNode(resolution.status))
+	}
+	assert.Equals(t, rootNode.SatisfactionTrace(), []dgraph.DependencyEvent{
+		{"optional_1", dgraph.OptionalDependency, dgraph.Resolved, dgraph.DependencyIgnored},
+		{"or_1", dgraph.OrDependency, dgraph.Unresolvable, dgraph.DependencyIgnored},
+		{"or_2", dgraph.OrDependency, dgraph.Resolved, dgraph.DependencySatisfied},
+		{"or_3", dgraph.ObviatedDependency, dgraph.Resolved, dgraph.DependencyObviated},
+	})
+
+	failingNode, err := d.AddNode("failing", "failing")
+	assert.NoError(t, err)
+	assert.NoError(t, failingNode.ConnectDependency("root", dgraph.AndDependency))
+	assert.NoError(t, rootNode.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, failingNode.SatisfactionTrace(), []dgraph.DependencyEvent{
+		{"root", dgraph.AndDependency, dgraph.Unresolvable, dgraph.DependencyFailed},
+	})
+}
+
+func TestDirectedGraph_ReadyNodeConnectionPolicy(t *testing.T) {
+	setup := func(policy dgraph.ReadyNodeConnectionPolicy) (dgraph.DirectedGraph[string], dgraph.Node[string]) {
+		d := dgraph.New[string](dgraph.WithReadyNodeConnectionPolicy(policy))
+		target := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("target", "target"))
+		assert.NoError(t, d.PushStartingNodes())
+		_, err := d.AddNode("late", "late")
+		assert.NoError(t, err)
+		return d, target
+	}
+	t.Run("allow", func(t *testing.T) {
+		d, target := setup(dgraph.ReadyNodeConnectionAllow)
+		assert.NoError(t, target.ConnectDependency("late", dgraph.AndDependency))
+		assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"target": dgraph.Waiting})
+	})
+	t.Run("reject", func(t *testing.T) {
+		d, target := setup(dgraph.ReadyNodeConnectionReject)
+		err := target.ConnectDependency("late", dgraph.AndDependency)
+		assert.InstanceOf[*dgraph.ErrNodeAlreadyReady](t, err)
+		// Optional dependencies don't affect readiness, so they are accepted.
+		assert.NoError(t, target.ConnectDependency("late", dgraph.OptionalDependency))
+		assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"target": dgraph.Waiting})
+	})
+	t.Run("rollback", func(t *testing.T) {
+		d, target := setup(dgraph.ReadyNodeConnectionRollback)
+		assert.NoError(t, target.ConnectDependency("late", dgraph.AndDependency))
+		assert.Equals(t, d.HasReadyNodes(), false)
+		late := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("late"))
+		assert.NoError(t, late.ResolveNode(dgraph.Resolved))
+		assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"target": dgraph.Waiting})
+	})
+}
+
+func TestDirectedGraph_IDNormalizer(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithIDNormalizer(func(id string) string {
+		return strings.ToLower(strings.TrimSpace(id))
+	}))
+	n1 := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(" Steps.Example ", "test1"))
+	assert.Equals(t, n1.ID(), "steps.example")
+	_, err := d.AddNode("STEPS.EXAMPLE", "duplicate")
+	assert.InstanceOf[dgraph.ErrNodeAlreadyExists](t, err)
+	n2 := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("Outputs", "test2"))
+	assert.NoError(t, n2.ConnectDependency("steps.EXAMPLE", dgraph.AndDependency))
+	assert.Equals(t, n2.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"steps.example": dgraph.AndDependency,
+	})
+	n3, err := d.GetNodeByID("  outputs")
+	assert.NoError(t, err)
+	assert.Equals(t, n3, n2)
+	assert.NoError(t, n3.DisconnectInbound("Steps.Example"))
+}
+
+func TestDirectedGraph_ResolutionOrder(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.Equals(t, d.ResolutionOrder(), []string(nil))
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, d.ResolutionOrder(), []string{"b", "a", "c"})
+}
+
+func TestDirectedGraph_NodeNotFoundSuggestions(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"steps.example.outputs", "steps.example.output.success", "input", "steps.other"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	var notFound *dgraph.ErrNodeNotFound
+	_, err := d.GetNodeByID("steps.exampel.outputs")
+	assert.Equals(t, errors.As(err, &notFound), true)
+	assert.Equals(t, notFound.Suggestions(), []string{"steps.example.outputs"})
+	assert.Equals(
+		t,
+		err.Error(),
+		`node with ID "steps.exampel.outputs" not found; did you mean "steps.example.outputs"?`,
+	)
+
+	n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("input"))
+	assert.Equals(t, errors.As(n.Connect("steps"), &notFound), true)
+	assert.Equals(t, notFound.Suggestions(), []string{
+		"steps.other",
+		"steps.example.outputs",
+		"steps.example.output.success",
+	})
+
+	_, err = d.GetNodeByID("completely-different")
+	assert.Equals(t, errors.As(err, &notFound), true)
+	assert.Equals(t, len(notFound.Suggestions()), 0)
+	// Errors created by callers have no suggestions.
+	assert.Equals(t, dgraph.ErrNodeNotFound{NodeID: "a"}.Error(), `node with ID "a" not found`)
+}
+
+func TestDependencyReport(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("d"))
+	assert.NoError(t, n.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, n.ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, n.ConnectDependency("c", dgraph.AndDependency))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+
+	assert.Equals(t, n.DependencyReport(), dgraph.DependencyReport{
+		Resolved: map[string]dgraph.DependencyState{
+			"a": {DependencyType: dgraph.OrDependency, Status: dgraph.Resolved},
+		},
+		Outstanding: map[string]dgraph.DependencyState{
+			"c": {DependencyType: dgraph.AndDependency, Status: dgraph.Waiting},
+		},
+		Obviated: map[string]dgraph.DependencyState{
+			"b": {DependencyType: dgraph.ObviatedDependency, Status: dgraph.Waiting},
+		},
+	})
+}
+
+func TestOnUnresolvableDependency(t *testing.T) {
+	d := dgraph.New[string]()
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"failing", "succeeding", "handler", "skipped", "after-skipped"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["handler"].ConnectDependency("failing", dgraph.OnUnresolvableDependency))
+	assert.NoError(t, nodes["skipped"].ConnectDependency("succeeding", dgraph.OnUnresolvableDependency))
+	assert.NoError(t, nodes["after-skipped"].ConnectDependency("skipped", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, len(d.PopReadyNodes()), 2)
+
+	assert.NoError(t, nodes["failing"].ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"handler": dgraph.Waiting})
+
+	assert.NoError(t, nodes["succeeding"].ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"skipped":       dgraph.Unresolvable,
+		"after-skipped": dgraph.Unresolvable,
+	})
+}
+
+func TestErrDuplicateDependencyResolution(t *testing.T) {
+	err := dgraph.ErrDuplicateDependencyResolution{
+		NodeID:           "b",
+		DependencyID:     "a",
+		DependencyType:   dgraph.AndDependency,
+		NodeStatus:       dgraph.Waiting,
+		DependencyStatus: dgraph.Resolved,
+	}
+	assert.Equals(
+		t,
+		err.Error(),
+		`attempted to re-resolve and dependency "a" (status "resolved") of node "b" (status "waiting"); the`+
+			` connection remains, but there are no outstanding requirements`,
+	)
+}
+
+func TestDirectedGraph_ConcurrentReads(t *testing.T) {
+	d := dgraph.New[int]()
+	for i := 0; i < 50; i++ {
+		n := assert.NoErrorR[dgraph.Node[int]](t)(d.AddNode(fmt.Sprintf("node-%d", i), i))
+		if i > 0 {
+			assert.NoError(t, n.ConnectDependency(fmt.Sprintf("node-%d", i-1), dgraph.AndDependency))
+		}
+	}
+	assert.NoError(t, d.PushStartingNodes())
+
+	// Readers poll the graph while it is resolved. Run with -race to check the read locking.
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for _, n := range d.ListNodes() {
+					_ = n.ResolutionStatus()
+					_ = n.IsReady()
+				}
+				_, _ = d.GetNodeByID("node-49")
+			}
+		}()
+	}
+	for resolved := 0; resolved < 50; {
+		for nodeID := range d.PopReadyNodes() {
+			n := assert.NoErrorR[dgraph.Node[int]](t)(d.GetNodeByID(nodeID))
+			assert.NoError(t, n.ResolveNode(dgraph.Resolved))
+			resolved++
+		}
+	}
+	close(done)
+	readers.Wait()
+	last := assert.NoErrorR[dgraph.Node[int]](t)(d.GetNodeByID("node-49"))
+	assert.Equals(t, last.ResolutionStatus(), dgraph.Resolved)
+}
@@ -0,0 +1,102 @@
+package dgraph
+
+import (
+	"cmp"
+	"slices"
+)
+
+// GraphDiff describes the changes from one graph to another. Node IDs are sorted, and connections are sorted by
+// source and destination node ID.
+type GraphDiff struct {
+	// AddedNodes contains the IDs of the nodes that only exist in the other graph.
+	AddedNodes []string
+	// RemovedNodes contains the IDs of the nodes that only exist in the original graph.
+	RemovedNodes []string
+	// AddedConnections contains the connections that only exist in the other graph, as listed by that graph.
+	AddedConnections []Connection
+	// RemovedConnections contains the connections that only exist in the original graph, as listed by that graph.
+	RemovedConnections []Connection
+	// ChangedDependencyTypes contains the connections that exist in both graphs with different dependency types.
+	ChangedDependencyTypes []DependencyTypeChange
+}
+
+// DependencyTypeChange is a connection whose dependency type differs between two graphs.
+type DependencyTypeChange struct {
+	SourceNodeID      string
+	DestinationNodeID string
+	OldType           DependencyType
+	NewType           DependencyType
+}
+
+// IsEmpty returns true if the graphs have the same nodes and connections with the same dependency types.
+func (g GraphDiff) IsEmpty() bool {
+	return len(g.AddedNodes) == 0 && len(g.RemovedNodes) == 0 && len(g.AddedConnections) == 0 &&
+		len(g.RemovedConnections) == 0 && len(g.ChangedDependencyTypes) == 0
+}
+
+func (d *directedGraph[NodeType]) Diff(other DirectedGraph[NodeType]) GraphDiff {
+	return diffTopology(d.ListNodes(), other.ListNodes(), d.ListConnections(), other.ListConnections())
+}
+
+// diffTopology computes the changes from the base nodes and connections to the other ones.
+func diffTopology[NodeType any](
+	baseNodes, otherNodes map[string]Node[NodeType],
+	baseConnections, otherConnections []Connection,
+) GraphDiff {
+	var result GraphDiff
+	for nodeID := range baseNodes {
+		if _, ok := otherNodes[nodeID]; !ok {
+			result.RemovedNodes = append(result.RemovedNodes, nodeID)
+		}
+	}
+	for nodeID := range otherNodes {
+		if _, ok := baseNodes[nodeID]; !ok {
+			result.AddedNodes = append(result.AddedNodes, nodeID)
+		}
+	}
+	slices.Sort(result.RemovedNodes)
+	slices.Sort(result.AddedNodes)
+
+	connectionMap := func(connections []Connection) map[[2]string]Connection {
+		result := make(map[[2]string]Connection, len(connections))
+		for _, connection := range connections {
+			result[[2]string{connection.SourceNodeID, connection.DestinationNodeID}] = connection
+		}
+		return result
+	}
+	baseMap, otherMap := connectionMap(baseConnections), connectionMap(otherConnections)
+	for pair, connection := range baseMap {
+		otherConnection, ok := otherMap[pair]
+		switch {
+		case !ok:
+			result.RemovedConnections = append(result.RemovedConnections, connection)
+		case otherConnection.DependencyType != connection.DependencyType:
+			result.ChangedDependencyTypes = append(result.ChangedDependencyTypes, DependencyTypeChange{
+				SourceNodeID:      pair[0],
+				DestinationNodeID: pair[1],
+				OldType:           connection.DependencyType,
+				NewType:           otherConnection.DependencyType,
+			})
+		}
+	}
+	for pair, connection := range otherMap {
+		if _, ok := baseMap[pair]; !ok {
+			result.AddedConnections = append(result.AddedConnections, connection)
+		}
+	}
+	compareConnections := func(a, b Connection) int {
+		return cmp.Or(
+			cmp.Compare(a.SourceNodeID, b.SourceNodeID),
+			cmp.Compare(a.DestinationNodeID, b.DestinationNodeID),
+		)
+	}
+	slices.SortFunc(result.RemovedConnections, compareConnections)
+	slices.SortFunc(result.AddedConnections, compareConnections)
+	slices.SortFunc(result.ChangedDependencyTypes, func(a, b DependencyTypeChange) int {
+		return cmp.Or(
+			cmp.Compare(a.SourceNodeID, b.SourceNodeID),
+			cmp.Compare(a.DestinationNodeID, b.DestinationNodeID),
+		)
+	})
+	return result
+}
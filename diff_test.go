@@ -0,0 +1,53 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Diff(t *testing.T) {
+	build := func(
+		nodeIDs []string,
+		connections [][2]string,
+		dependencyTypes []dgraph.DependencyType,
+	) dgraph.DirectedGraph[string] {
+		d := dgraph.New[string]()
+		for _, id := range nodeIDs {
+			_, err := d.AddNode(id, id)
+			assert.NoError(t, err)
+		}
+		for i, connection := range connections {
+			n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(connection[1]))
+			assert.NoError(t, n.ConnectDependency(connection[0], dependencyTypes[i]))
+		}
+		return d
+	}
+	deployed := build(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}},
+		[]dgraph.DependencyType{dgraph.AndDependency, dgraph.AndDependency},
+	)
+	regenerated := build(
+		[]string{"a", "b", "d"},
+		[][2]string{{"a", "b"}, {"b", "d"}},
+		[]dgraph.DependencyType{dgraph.OrDependency, dgraph.AndDependency},
+	)
+	assert.Equals(t, deployed.Diff(deployed).IsEmpty(), true)
+
+	diff := deployed.Diff(regenerated)
+	assert.Equals(t, diff.IsEmpty(), false)
+	assert.Equals(t, diff.AddedNodes, []string{"d"})
+	assert.Equals(t, diff.RemovedNodes, []string{"c"})
+	assert.Equals(t, len(diff.AddedConnections), 1)
+	assert.Equals(t, diff.AddedConnections[0].DestinationNodeID, "d")
+	assert.Equals(t, len(diff.RemovedConnections), 1)
+	assert.Equals(t, diff.RemovedConnections[0].DestinationNodeID, "c")
+	assert.Equals(t, diff.ChangedDependencyTypes, []dgraph.DependencyTypeChange{{
+		SourceNodeID:      "a",
+		DestinationNodeID: "b",
+		OldType:           dgraph.AndDependency,
+		NewType:           dgraph.OrDependency,
+	}})
+}
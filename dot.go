@@ -0,0 +1,66 @@
+package dgraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotStatusColors maps the resolution statuses to the fill colors of the nodes in DOT output.
+var dotStatusColors = map[ResolutionStatus]string{
+	Waiting:      "white",
+	Resolved:     "palegreen",
+	Unresolvable: "lightcoral",
+	Cancelled:    "lightgray",
+}
+
+func (d *directedGraph[NodeType]) DOT() string {
+	return d.DOTFiltered(ExportFilter{})
+}
+
+func (d *directedGraph[NodeType]) DOTFiltered(filter ExportFilter) string {
+	d.lock.RLock()
+	snapshot := d.renderSnapshot(filter)
+	d.lock.RUnlock()
+
+	result := []string{"digraph {"}
+	for _, n := range snapshot.nodes {
+		attributes := fmt.Sprintf("style=filled, fillcolor=%s", dotStatusColors[n.status])
+		if n.label != "" {
+			attributes += ", label=" + dotQuote(n.label)
+		}
+		// The description comes first in the tooltip, followed by the annotations.
+		var tooltip []string
+		if n.description != "" {
+			tooltip = append(tooltip, n.description)
+		}
+		tooltip = append(tooltip, n.annotations...)
+		if len(tooltip) > 0 {
+			attributes += ", tooltip=" + dotQuote(strings.Join(tooltip, "\n"))
+		}
+		result = append(result, fmt.Sprintf("\t%s [%s];", dotQuote(n.id), attributes))
+	}
+	for _, connection := range snapshot.connections {
+		var attributes []string
+		if connection.label != "" {
+			attributes = append(attributes, "label="+dotQuote(connection.label))
+		}
+		if connection.description != "" {
+			attributes = append(attributes, "tooltip="+dotQuote(connection.description))
+		}
+		if connection.errorPath {
+			attributes = append(attributes, "style=dashed", "color=red")
+		}
+		edge := fmt.Sprintf("\t%s -> %s", dotQuote(connection.from), dotQuote(connection.to))
+		if len(attributes) > 0 {
+			edge += " [" + strings.Join(attributes, ", ") + "]"
+		}
+		result = append(result, edge+";")
+	}
+	result = append(result, "}")
+	return strings.Join(result, "\n") + "\n"
+}
+
+// dotQuote returns the ID as a quoted DOT string.
+func dotQuote(id string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(id) + `"`
+}
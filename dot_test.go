@@ -0,0 +1,95 @@
+package dgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_DOT(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(`say "hi"`, "b"))
+	_, err := d.AddNode("c", "c")
+	assert.NoError(t, err)
+	assert.NoError(t, a.ConnectWithMetadata(b.ID(), map[string]string{dgraph.LabelMetadataKey: "on success"}))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.DOT(), `digraph {
+	"a" [style=filled, fillcolor=palegreen];
+	"c" [style=filled, fillcolor=white];
+	"say \"hi\"" [style=filled, fillcolor=white];
+	"a" -> "say \"hi\"" [label="on success"];
+}
+`)
+	assert.Equals(t, d.DOTFiltered(dgraph.ExportFilter{Statuses: []dgraph.ResolutionStatus{dgraph.Waiting}}), `digraph {
+	"c" [style=filled, fillcolor=white];
+	"say \"hi\"" [style=filled, fillcolor=white];
+}
+`)
+}
+
+func TestDirectedGraph_DOTAnnotations(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoError(t, a.Annotate("retries 3x", "requires approval"))
+	assert.Equals(t, a.Annotations(), []string{"retries 3x", "requires approval"})
+	assert.Equals(t, d.DOT(), `digraph {
+	"a" [style=filled, fillcolor=white, tooltip="retries 3x\nrequires approval"];
+}
+`)
+}
+
+func TestDirectedGraph_DOTErrorPath(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithErrorPathClassifier(func(nodeID string) bool {
+		return strings.HasPrefix(nodeID, "on-failure")
+	}))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	_, err := d.AddNode("on-failure", "f")
+	assert.NoError(t, err)
+	assert.NoError(t, a.Connect("on-failure"))
+	assert.Equals(t, d.DOT(), `digraph {
+	"a" [style=filled, fillcolor=white];
+	"on-failure" [style=filled, fillcolor=white];
+	"a" -> "on-failure" [style=dashed, color=red];
+}
+`)
+}
+
+func TestDirectedGraph_DOTDescriptions(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	_, err := d.AddNode("b", "b")
+	assert.NoError(t, err)
+	assert.NoError(t, a.SetDescription("Builds the image."))
+	assert.NoError(t, a.Annotate("retries 3x"))
+	assert.Equals(t, a.Description(), "Builds the image.")
+	assert.NoError(t, a.ConnectWithMetadata("b", map[string]string{
+		dgraph.DescriptionMetadataKey: "b needs the image",
+	}))
+	assert.Equals(t, d.DOT(), `digraph {
+	"a" [style=filled, fillcolor=white, tooltip="Builds the image.\nretries 3x"];
+	"b" [style=filled, fillcolor=white];
+	"a" -> "b" [tooltip="b needs the image"];
+}
+`)
+}
+
+func TestDirectedGraph_DOTNodeAttributes(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoError(t, a.SetAttr(dgraph.LabelMetadataKey, "Build"))
+	assert.NoError(t, a.SetAttr("tag", "ci"))
+	value, ok := a.Attr("tag")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, value, "ci")
+	_, ok = a.Attr("missing")
+	assert.Equals(t, ok, false)
+	assert.Equals(t, a.Attrs(), map[string]string{dgraph.LabelMetadataKey: "Build", "tag": "ci"})
+	assert.Equals(t, d.DOT(), `digraph {
+	"a" [style=filled, fillcolor=white, label="Build"];
+}
+`)
+}
@@ -0,0 +1,59 @@
+package dgraph
+
+import "context"
+
+func (d *directedGraph[NodeType]) Drain(ctx context.Context) error {
+	d.lock.Lock()
+	if !d.gateClosed {
+		d.gateClosed = true
+		d.advanceGeneration()
+	}
+	d.unlock()
+	for {
+		d.lock.Lock()
+		d.checkDeadline()
+		if !d.hasInFlight() {
+			d.unlock()
+			return nil
+		}
+		if d.inFlightChanged == nil {
+			d.inFlightChanged = make(chan struct{})
+		}
+		inFlightChanged := d.inFlightChanged
+		done := d.done
+		d.unlock()
+		select {
+		case <-inFlightChanged:
+		case <-done:
+			// The deadline resolves all nodes in flight, so the next iteration returns.
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// isInFlight returns true if the node has been popped, but not resolved yet.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) isInFlight(n *node[NodeType]) bool {
+	return n.status == Waiting && !n.poppedAt.IsZero() && !d.isPendingReady(n.id)
+}
+
+// hasInFlight returns true if any node has been popped, but not resolved yet.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) hasInFlight() bool {
+	for _, n := range d.nodes {
+		if d.isInFlight(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyInFlightChanged wakes up the callers of Drain.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) notifyInFlightChanged() {
+	if d.inFlightChanged != nil {
+		close(d.inFlightChanged)
+		d.inFlightChanged = nil
+	}
+}
@@ -0,0 +1,65 @@
+package dgraph_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Drain(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	drained := make(chan error)
+	go func() {
+		drained <- d.Drain(ctx)
+	}()
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while b was in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, <-drained)
+
+	// c became ready while draining, but is left for later resumption.
+	assert.Equals(t, d.IsGateOpen(), false)
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.Equals(t, c.ResolutionStatus(), dgraph.Waiting)
+	d.SetGate(true)
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"c"})
+}
+
+func TestDirectedGraph_DrainContext(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equals(t, errors.Is(d.Drain(ctx), context.Canceled), true)
+	assert.Equals(t, d.IsGateOpen(), false)
+}
+
+func TestDirectedGraph_DrainIdle(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoError(t, d.PushStartingNodes())
+	// Nothing has been popped, so there is nothing to wait for.
+	assert.NoError(t, d.Drain(context.Background()))
+	assert.Equals(t, d.HasReadyNodes(), false)
+}
@@ -0,0 +1,55 @@
+package dgraph
+
+import "time"
+
+func (n *node[NodeType]) SetExpectedDuration(duration time.Duration) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	n.expectedDuration = duration
+	n.dg.downstreamCosts = nil
+	n.dg.advanceGeneration()
+	return nil
+}
+
+func (n *node[NodeType]) ExpectedDuration() time.Duration {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.expectedDuration
+}
+
+func (d *directedGraph[NodeType]) EstimateMakespan() (time.Duration, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	order, cycle := d.topologicalOrder()
+	if cycle != nil {
+		return 0, &ErrGraphHasCycles{Cycle: cycle}
+	}
+	finish := make(map[string]time.Duration, len(order))
+	var result time.Duration
+	for _, nodeID := range order {
+		var start time.Duration
+		for fromNodeID := range d.connectionsToNode[nodeID] {
+			start = max(start, finish[fromNodeID])
+		}
+		finish[nodeID] = start + d.nodes[nodeID].expectedDuration
+		result = max(result, finish[nodeID])
+	}
+	return result, nil
+}
+
+// nodeCost returns the cost of the node used for critical path scheduling: the result of the weight function of
+// WithCriticalPathPriority if there is one, otherwise the expected duration of the node in seconds if it has one,
+// otherwise 1.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) nodeCost(nodeID string) float64 {
+	if d.config.nodeWeight != nil {
+		return d.config.nodeWeight(nodeID)
+	}
+	if duration := d.nodes[nodeID].expectedDuration; duration > 0 {
+		return duration.Seconds()
+	}
+	return 1
+}
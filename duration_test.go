@@ -0,0 +1,36 @@
+package dgraph_test
+
+import (
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_ExpectedDuration(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithCriticalPathPriority(nil))
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"a", "b", "heavy", "end"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["b"].ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, nodes["end"].ConnectDependency("b", dgraph.AndDependency))
+	assert.NoError(t, nodes["end"].ConnectDependency("heavy", dgraph.AndDependency))
+	assert.Equals(t, nodeIDs(d.CriticalPath()), []string{"a", "b", "end"})
+	assert.Equals(t, assert.NoErrorR[time.Duration](t)(d.EstimateMakespan()), time.Duration(0))
+
+	// The scheduler and the estimate both use the hints.
+	for id, duration := range map[string]time.Duration{"a": time.Second, "b": time.Second, "heavy": time.Minute} {
+		assert.NoError(t, nodes[id].SetExpectedDuration(duration))
+	}
+	assert.Equals(t, nodes["heavy"].ExpectedDuration(), time.Minute)
+	assert.Equals(t, nodeIDs(d.CriticalPath()), []string{"heavy", "end"})
+	assert.Equals(t, assert.NoErrorR[time.Duration](t)(d.EstimateMakespan()), time.Minute)
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"heavy", "a"})
+
+	assert.NoError(t, nodes["a"].ConnectDependency("end", dgraph.AndDependency))
+	_, err := d.EstimateMakespan()
+	assert.InstanceOf[*dgraph.ErrGraphHasCycles](t, err)
+}
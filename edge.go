@@ -0,0 +1,195 @@
+package dgraph
+
+import "maps"
+
+// Edge is a handle to a single connection, returned by Node.ConnectDependencyEdge and DirectedGraph.GetEdge. It allows changing the
+// connection without looking it up again by its node IDs.
+type Edge interface {
+	// SourceNodeID returns the ID of the dependency node.
+	SourceNodeID() string
+	// DestinationNodeID returns the ID of the node depending on the source node.
+	DestinationNodeID() string
+	// DependencyType returns the current dependency type of the connection. If the connection no longer exists, an
+	// ErrConnectionDoesNotExist is returned.
+	DependencyType() (DependencyType, error)
+	// ChangeType changes the type of a dependency that has not been resolved yet. If it has been resolved, an
+	// ErrDependencyAlreadyResolved is returned. The readiness of the destination node is updated by Reconcile(),
+	// and a node that is already ready is not affected by changing the dependency to a required type.
+	ChangeType(dependencyType DependencyType) error
+	// Obviate changes the dependency to an ObviatedDependency, so that it no longer has an effect on the
+	// destination node. This is the same as calling ChangeType with ObviatedDependency.
+	Obviate() error
+	// Disconnect removes the connection, like Node.DisconnectInbound on the destination node.
+	Disconnect() error
+	// Metadata returns a copy of the key/value metadata of the connection.
+	Metadata() (map[string]string, error)
+	// SetMetadata sets a metadata value of the connection. Use LabelMetadataKey to set the label of the connection.
+	SetMetadata(key string, value string) error
+	// Weight returns the weight of the connection, which is DefaultConnectionWeight unless set with SetWeight.
+	Weight() (float64, error)
+	// SetWeight sets the weight of the connection used by ShortestPath and LongestPath, for example the estimated
+	// duration of the source node.
+	SetWeight(weight float64) error
+}
+
+type edge[NodeType any] struct {
+	from *node[NodeType]
+	to   *node[NodeType]
+	dg   *directedGraph[NodeType]
+}
+
+func (n *node[NodeType]) ConnectDependencyEdge(fromNodeID string, dependencyType DependencyType) (Edge, error) {
+	fromNodeID = n.dg.config.normalizeID(fromNodeID)
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if err := n.dg.connect(fromNodeID, n.id, dependencyType); err != nil {
+		return nil, err
+	}
+	return &edge[NodeType]{n.dg.nodes[fromNodeID], n, n.dg}, nil
+}
+
+func (d *directedGraph[NodeType]) GetEdge(fromNodeID, toNodeID string) (Edge, error) {
+	fromNodeID = d.config.normalizeID(fromNodeID)
+	toNodeID = d.config.normalizeID(toNodeID)
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	fromNode, ok := d.nodes[fromNodeID]
+	if !ok {
+		return nil, d.nodeNotFound(fromNodeID)
+	}
+	toNode, ok := d.nodes[toNodeID]
+	if !ok {
+		return nil, d.nodeNotFound(toNodeID)
+	}
+	e := &edge[NodeType]{fromNode, toNode, d}
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (n *node[NodeType]) ConnectWithMetadata(toNodeID string, metadata map[string]string) error {
+	toNodeID = n.dg.config.normalizeID(toNodeID)
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if err := n.dg.connect(n.id, toNodeID, AndDependency); err != nil {
+		return err
+	}
+	if len(metadata) > 0 {
+		n.dg.connectionMetadata[[2]string{n.id, toNodeID}] = maps.Clone(metadata)
+	}
+	return nil
+}
+
+func (e *edge[NodeType]) SourceNodeID() string {
+	return e.from.id
+}
+
+func (e *edge[NodeType]) DestinationNodeID() string {
+	return e.to.id
+}
+
+func (e *edge[NodeType]) DependencyType() (DependencyType, error) {
+	e.dg.lock.RLock()
+	defer e.dg.lock.RUnlock()
+	if err := e.check(); err != nil {
+		return "", err
+	}
+	return e.to.dependencyType(e.from.id), nil
+}
+
+func (e *edge[NodeType]) ChangeType(dependencyType DependencyType) error {
+	e.dg.lock.Lock()
+	defer e.dg.unlock()
+	if err := e.check(); err != nil {
+		return err
+	}
+	if _, outstanding := e.to.outstandingDependencies[e.from.id]; !outstanding {
+		return &ErrDependencyAlreadyResolved{e.to.id, e.from.id}
+	}
+	if e.to.declaredDependencyType(e.from.id) == ExpressionDependency {
+		e.to.removeFromExpression(e.from.id)
+	}
+	e.to.outstandingDependencies[e.from.id] = dependencyType
+	delete(e.to.obviatedTypes, e.from.id)
+	delete(e.dg.connectionConditions, [2]string{e.from.id, e.to.id})
+	e.dg.markChanged(e.to.id)
+	return nil
+}
+
+func (e *edge[NodeType]) Obviate() error {
+	return e.ChangeType(ObviatedDependency)
+}
+
+func (e *edge[NodeType]) Disconnect() error {
+	e.dg.lock.Lock()
+	defer e.dg.unlock()
+	if err := e.check(); err != nil {
+		return err
+	}
+	e.dg.removeConnection(e.from.id, e.to.id)
+	return nil
+}
+
+func (e *edge[NodeType]) Metadata() (map[string]string, error) {
+	e.dg.lock.RLock()
+	defer e.dg.lock.RUnlock()
+	if err := e.check(); err != nil {
+		return nil, err
+	}
+	result := maps.Clone(e.dg.connectionMetadata[[2]string{e.from.id, e.to.id}])
+	if result == nil {
+		result = map[string]string{}
+	}
+	return result, nil
+}
+
+func (e *edge[NodeType]) SetMetadata(key string, value string) error {
+	e.dg.lock.Lock()
+	defer e.dg.unlock()
+	if err := e.check(); err != nil {
+		return err
+	}
+	connection := [2]string{e.from.id, e.to.id}
+	if e.dg.connectionMetadata[connection] == nil {
+		e.dg.connectionMetadata[connection] = map[string]string{}
+	}
+	e.dg.connectionMetadata[connection][key] = value
+	e.dg.advanceGeneration()
+	return nil
+}
+
+func (e *edge[NodeType]) Weight() (float64, error) {
+	e.dg.lock.RLock()
+	defer e.dg.lock.RUnlock()
+	if err := e.check(); err != nil {
+		return 0, err
+	}
+	return e.dg.connectionWeight(e.from.id, e.to.id), nil
+}
+
+func (e *edge[NodeType]) SetWeight(weight float64) error {
+	e.dg.lock.Lock()
+	defer e.dg.unlock()
+	if err := e.check(); err != nil {
+		return err
+	}
+	e.dg.connectionWeights[[2]string{e.from.id, e.to.id}] = weight
+	e.dg.advanceGeneration()
+	return nil
+}
+
+// check returns an error if either node has been removed or the connection no longer exists.
+// Caller should have appropriate mutex locked before calling.
+func (e *edge[NodeType]) check() error {
+	if e.from.deleted {
+		return &ErrNodeDeleted{e.from.id}
+	}
+	if e.to.deleted {
+		return &ErrNodeDeleted{e.to.id}
+	}
+	if _, ok := e.dg.connectionsFromNode[e.from.id][e.to.id]; !ok {
+		return &ErrConnectionDoesNotExist{e.from.id, e.to.id}
+	}
+	return nil
+}
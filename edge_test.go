@@ -0,0 +1,69 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestEdge(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	ac := assert.NoErrorR[dgraph.Edge](t)(c.ConnectDependencyEdge("a", dgraph.AndDependency))
+	bc := assert.NoErrorR[dgraph.Edge](t)(c.ConnectDependencyEdge("b", dgraph.AndDependency))
+	assert.Equals(t, ac.SourceNodeID(), "a")
+	assert.Equals(t, ac.DestinationNodeID(), "c")
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.InstanceOf[*dgraph.ErrDependencyAlreadyResolved](t, ac.ChangeType(dgraph.OptionalDependency))
+	assert.Equals(t, assert.NoErrorR[dgraph.DependencyType](t)(ac.DependencyType()), dgraph.AndDependency)
+
+	// Obviating the last required dependency makes the node ready on reconciliation.
+	assert.NoError(t, bc.Obviate())
+	assert.Equals(t, assert.NoErrorR[dgraph.DependencyType](t)(bc.DependencyType()), dgraph.ObviatedDependency)
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"c": dgraph.Waiting})
+
+	assert.NoError(t, bc.Disconnect())
+	inbound := assert.NoErrorR[map[string]dgraph.Node[string]](t)(c.ListInboundConnections())
+	assert.Equals(t, len(inbound), 1)
+	assert.InstanceOf[*dgraph.ErrConnectionDoesNotExist](t, bc.Disconnect())
+	assert.NoError(t, b.Remove())
+	assert.InstanceOf[*dgraph.ErrNodeDeleted](t, bc.Obviate())
+}
+
+func TestEdge_Metadata(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	_, err := d.AddNode("b", "b")
+	assert.NoError(t, err)
+	assert.NoError(t, a.ConnectWithMetadata("b", map[string]string{dgraph.LabelMetadataKey: "output \"success\""}))
+
+	e := assert.NoErrorR[dgraph.Edge](t)(d.GetEdge("a", "b"))
+	assert.NoError(t, e.SetMetadata("output", "success"))
+	assert.Equals(t, assert.NoErrorR[map[string]string](t)(e.Metadata()), map[string]string{
+		dgraph.LabelMetadataKey: "output \"success\"",
+		"output":                "success",
+	})
+	assert.Equals(t, d.ListConnections()[0].Metadata["output"], "success")
+	assert.Equals(t, d.Mermaid(), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+a-->|"output #quot;success#quot;"|b
+%% Error path
+%% Mermaid end
+`)
+
+	_, err = d.GetEdge("b", "a")
+	assert.InstanceOf[*dgraph.ErrConnectionDoesNotExist](t, err)
+	// Reconnecting does not bring back the metadata of a removed connection.
+	assert.NoError(t, e.Disconnect())
+	assert.NoError(t, a.Connect("b"))
+	e = assert.NoErrorR[dgraph.Edge](t)(d.GetEdge("a", "b"))
+	assert.Equals(t, assert.NoErrorR[map[string]string](t)(e.Metadata()), map[string]string{})
+}
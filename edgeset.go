@@ -0,0 +1,71 @@
+package dgraph
+
+// EdgeSet is an immutable snapshot of the connections of a graph, returned by DirectedGraph.EdgeSet. It doesn't
+// take the lock of the graph, so it is safe for concurrent use and suited for validators that check many
+// connections. It doesn't reflect changes made to the graph after it was taken; compare Generation with
+// DirectedGraph.Generation to detect that it is stale.
+type EdgeSet struct {
+	types        map[[2]string]DependencyType
+	idNormalizer func(string) string
+	generation   uint64
+}
+
+// Contains returns true if there is a connection from the source node to the destination node.
+func (e *EdgeSet) Contains(fromID, toID string) bool {
+	_, ok := e.types[e.key(fromID, toID)]
+	return ok
+}
+
+// DependencyType returns the dependency type of the connection from the source node to the destination node, and
+// false if there is no such connection.
+func (e *EdgeSet) DependencyType(fromID, toID string) (DependencyType, bool) {
+	dependencyType, ok := e.types[e.key(fromID, toID)]
+	return dependencyType, ok
+}
+
+// Len returns the number of connections.
+func (e *EdgeSet) Len() int {
+	return len(e.types)
+}
+
+// Generation returns the generation of the graph when the snapshot was taken.
+func (e *EdgeSet) Generation() uint64 {
+	return e.generation
+}
+
+// All iterates over the source and destination node IDs and the dependency types of all connections in no
+// particular order.
+func (e *EdgeSet) All() func(yield func(fromID, toID string, dependencyType DependencyType) bool) {
+	return func(yield func(fromID, toID string, dependencyType DependencyType) bool) {
+		for pair, dependencyType := range e.types {
+			if !yield(pair[0], pair[1], dependencyType) {
+				return
+			}
+		}
+	}
+}
+
+func (e *EdgeSet) key(fromID, toID string) [2]string {
+	if e.idNormalizer != nil {
+		return [2]string{e.idNormalizer(fromID), e.idNormalizer(toID)}
+	}
+	return [2]string{fromID, toID}
+}
+
+func (d *directedGraph[NodeType]) EdgeSet() *EdgeSet {
+	d.lock.Lock()
+	defer d.unlock()
+	// The snapshot is immutable, so it is shared by all callers until the graph changes.
+	if d.edgeSet != nil && d.edgeSet.generation == d.generation {
+		return d.edgeSet
+	}
+	types := make(map[[2]string]DependencyType)
+	for toID, fromIDs := range d.connectionsToNode {
+		n := d.nodes[toID]
+		for fromID := range fromIDs {
+			types[[2]string{fromID, toID}] = n.dependencyType(fromID)
+		}
+	}
+	d.edgeSet = &EdgeSet{types, d.config.idNormalizer, d.generation}
+	return d.edgeSet
+}
@@ -0,0 +1,53 @@
+package dgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_EdgeSet(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.OrDependency))
+
+	edges := d.EdgeSet()
+	assert.Equals(t, edges.Len(), 2)
+	assert.Equals(t, edges.Contains("a", "c"), true)
+	assert.Equals(t, edges.Contains("c", "a"), false)
+	assert.Equals(t, edges.Contains("a", "b"), false)
+	dependencyType, ok := edges.DependencyType("b", "c")
+	assert.Equals(t, ok, true)
+	assert.Equals(t, dependencyType, dgraph.OrDependency)
+	assert.Equals(t, edges.Generation(), d.Generation())
+	listed := map[[2]string]dgraph.DependencyType{}
+	edges.All()(func(fromID, toID string, dependencyType dgraph.DependencyType) bool {
+		listed[[2]string{fromID, toID}] = dependencyType
+		return true
+	})
+	assert.Equals(t, listed, map[[2]string]dgraph.DependencyType{
+		{"a", "c"}: dgraph.AndDependency,
+		{"b", "c"}: dgraph.OrDependency,
+	})
+
+	// The snapshot is shared until the graph changes, and doesn't reflect later changes.
+	assert.Equals(t, d.EdgeSet() == edges, true)
+	assert.NoError(t, a.Remove())
+	assert.Equals(t, edges.Contains("a", "c"), true)
+	assert.Equals(t, d.EdgeSet() == edges, false)
+	assert.Equals(t, d.EdgeSet().Contains("a", "c"), false)
+	assert.Equals(t, d.EdgeSet().Len(), 1)
+}
+
+func TestDirectedGraph_EdgeSetNormalizesIDs(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithIDNormalizer(strings.ToLower))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.Equals(t, d.EdgeSet().Contains("A", "B"), true)
+}
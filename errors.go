@@ -1,6 +1,10 @@
 package dgraph
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // ErrNodeDeleted indicates that the current node has already been removed from the DirectedGraph.
 type ErrNodeDeleted struct {
@@ -20,13 +24,59 @@ func (e ErrCannotConnectToSelf) Error() string {
 	return fmt.Sprintf("cannot connect node %q to itself", e.NodeID)
 }
 
-// ErrNodeNotFound is an error that is returned if the specified node is not found.
+// ErrNoSelfLoop is returned by Node.CompleteIteration if the node is not connected to itself.
+type ErrNoSelfLoop struct {
+	NodeID string
+}
+
+func (e ErrNoSelfLoop) Error() string {
+	return fmt.Sprintf("node %q has no self-loop, so it cannot iterate", e.NodeID)
+}
+
+// ErrIterationNotRunning is returned by Node.CompleteIteration if the node has not been popped since it last became
+// ready.
+type ErrIterationNotRunning struct {
+	NodeID string
+}
+
+func (e ErrIterationNotRunning) Error() string {
+	return fmt.Sprintf("node %q is not running an iteration; it must be popped first", e.NodeID)
+}
+
+// ErrNodeNotExternal is returned by DirectedGraph.ResolveExternal if the node is not a boundary stub created by
+// DirectedGraph.Partition.
+type ErrNodeNotExternal struct {
+	NodeID string
+}
+
+func (e ErrNodeNotExternal) Error() string {
+	return fmt.Sprintf("node %q is not an external boundary node", e.NodeID)
+}
+
+// ErrNodeNotFound is an error that is returned if the specified node is not found. If there are existing nodes
+// with similar IDs, they are listed in the message and returned by Suggestions.
 type ErrNodeNotFound struct {
 	NodeID string
+	// candidates are the IDs of the nodes that existed when the error was created. The suggestions are only
+	// computed from them when they are needed, so that looking up missing nodes stays cheap.
+	candidates []string
+}
+
+// Suggestions returns the IDs of up to three nodes that are similar to NodeID, closest first.
+func (e ErrNodeNotFound) Suggestions() []string {
+	return suggestIDs(e.NodeID, e.candidates)
 }
 
 func (e ErrNodeNotFound) Error() string {
-	return fmt.Sprintf("node with ID %q not found", e.NodeID)
+	suggestions := e.Suggestions()
+	if len(suggestions) == 0 {
+		return fmt.Sprintf("node with ID %q not found", e.NodeID)
+	}
+	quoted := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		quoted[i] = fmt.Sprintf("%q", suggestion)
+	}
+	return fmt.Sprintf("node with ID %q not found; did you mean %s?", e.NodeID, strings.Join(quoted, " or "))
 }
 
 // ErrNodeAlreadyExists signals that a node with the specified ID already exists.
@@ -38,6 +88,73 @@ func (e ErrNodeAlreadyExists) Error() string {
 	return fmt.Sprintf("node with ID %q already exists", e.NodeID)
 }
 
+// ErrInvalidNodeID indicates that a node ID does not match the ID pattern the graph was configured with.
+type ErrInvalidNodeID struct {
+	NodeID  string
+	Pattern string
+}
+
+func (e ErrInvalidNodeID) Error() string {
+	return fmt.Sprintf("node ID %q does not match the required pattern %q", e.NodeID, e.Pattern)
+}
+
+// ErrGroupAlreadyExists signals that a group with the specified name already exists.
+type ErrGroupAlreadyExists struct {
+	GroupName string
+}
+
+func (e ErrGroupAlreadyExists) Error() string {
+	return fmt.Sprintf("group %q already exists", e.GroupName)
+}
+
+// ErrGraphAlreadyRegistered is returned by Registry.Register if a graph is already registered under the name.
+type ErrGraphAlreadyRegistered struct {
+	Name string
+}
+
+func (e ErrGraphAlreadyRegistered) Error() string {
+	return fmt.Sprintf("a graph is already registered under the name %q", e.Name)
+}
+
+// ErrGraphNotRegistered is returned by Registry if no graph is registered under the name.
+type ErrGraphNotRegistered struct {
+	Name string
+}
+
+func (e ErrGraphNotRegistered) Error() string {
+	return fmt.Sprintf("no graph is registered under the name %q", e.Name)
+}
+
+// ErrGroupNotFound is returned if the specified group does not exist.
+type ErrGroupNotFound struct {
+	GroupName string
+}
+
+func (e ErrGroupNotFound) Error() string {
+	return fmt.Sprintf("group %q not found", e.GroupName)
+}
+
+// ErrGroupMemberNotFound is returned if the specified node is not a member of the group.
+type ErrGroupMemberNotFound struct {
+	GroupName string
+	NodeID    string
+}
+
+func (e ErrGroupMemberNotFound) Error() string {
+	return fmt.Sprintf("node %q is not a member of group %q", e.NodeID, e.GroupName)
+}
+
+// ErrGroupDependencyAlreadyExists is returned by Node.ConnectGroupDependency if the node already depends on the
+// group.
+type ErrGroupDependencyAlreadyExists struct {
+	GroupName string
+	NodeID    string
+}
+
+func (e ErrGroupDependencyAlreadyExists) Error() string {
+	return fmt.Sprintf("node %q already depends on group %q", e.NodeID, e.GroupName)
+}
+
 // ErrConnectionWouldCreateACycle is an error that is returned if the newly created connection would create a cycle.
 type ErrConnectionWouldCreateACycle struct {
 	SourceNodeID      string
@@ -52,6 +169,251 @@ func (e ErrConnectionWouldCreateACycle) Error() string {
 	)
 }
 
+// ErrGraphHasCycles is returned by operations that require the graph to be acyclic.
+type ErrGraphHasCycles struct {
+	// Cycle contains the node IDs of one of the cycles in connection order, if known. The last node connects back
+	// to the first one.
+	Cycle []string
+}
+
+func (e ErrGraphHasCycles) Error() string {
+	if len(e.Cycle) == 0 {
+		return "the graph has cycles"
+	}
+	return fmt.Sprintf(
+		"the graph has cycles: %s -> %s",
+		strings.Join(e.Cycle, " -> "),
+		e.Cycle[0],
+	)
+}
+
+// ErrNoPath is returned if there is no path between two nodes.
+type ErrNoPath struct {
+	SourceNodeID      string
+	DestinationNodeID string
+}
+
+func (e ErrNoPath) Error() string {
+	return fmt.Sprintf("there is no path from node %q to node %q", e.SourceNodeID, e.DestinationNodeID)
+}
+
+// ErrTopologyMismatch is returned by Compare if the two graphs don't have the same nodes and connections, and by
+// RestoreState if the saved state doesn't match the nodes and connections of the graph.
+type ErrTopologyMismatch struct {
+	Reason string
+}
+
+func (e ErrTopologyMismatch) Error() string {
+	return "the graphs have different topologies: " + e.Reason
+}
+
+// ErrInvalidResourceToken is returned by Node.RequireResources if a token is not in the "name" or "name:count"
+// format with a positive count.
+type ErrInvalidResourceToken struct {
+	Token string
+}
+
+func (e ErrInvalidResourceToken) Error() string {
+	return fmt.Sprintf("invalid resource token %q; expected \"name\" or \"name:count\"", e.Token)
+}
+
+// ErrResourceUnavailable is returned by Node.RequireResources if a node requires more tokens of a resource than
+// the graph has, in which case it could never become ready.
+type ErrResourceUnavailable struct {
+	NodeID   string
+	Resource string
+	Required int
+	Capacity int
+}
+
+func (e ErrResourceUnavailable) Error() string {
+	return fmt.Sprintf(
+		"node %q requires %d tokens of resource %q, but only %d are available",
+		e.NodeID, e.Required, e.Resource, e.Capacity,
+	)
+}
+
+// ErrResourcesHeld is returned by Node.RequireResources if the node currently holds the tokens of its previous
+// requirements. The requirements can be changed again once the node is resolved or removed from the ready set.
+type ErrResourcesHeld struct {
+	NodeID string
+}
+
+func (e ErrResourcesHeld) Error() string {
+	return fmt.Sprintf("node %q holds its resource tokens, its requirements cannot be changed", e.NodeID)
+}
+
+// ErrNotSupportedByNamespace is returned by the functions of a view returned by DirectedGraph.Namespace that
+// only make sense for the whole graph, such as RestoreState.
+type ErrNotSupportedByNamespace struct {
+	Prefix    string
+	Operation string
+}
+
+func (e ErrNotSupportedByNamespace) Error() string {
+	return fmt.Sprintf("%s is not supported by the namespace %q", e.Operation, e.Prefix)
+}
+
+// ErrMergeConflicts is returned by Merge if it finds any conflicts. It lists all of them, node conflicts first,
+// and the graph is left unchanged.
+type ErrMergeConflicts struct {
+	Conflicts []MergeConflict
+}
+
+func (e ErrMergeConflicts) Error() string {
+	descriptions := make([]string, len(e.Conflicts))
+	for i, conflict := range e.Conflicts {
+		descriptions[i] = conflict.String()
+	}
+	return fmt.Sprintf("merge failed with %d conflicts: %s", len(e.Conflicts), strings.Join(descriptions, "; "))
+}
+
+// ErrBoundaryViolations is returned by ValidateBoundary if an embedded graph breaks its boundary contract. It lists
+// all violations, inputs first.
+type ErrBoundaryViolations struct {
+	Violations []BoundaryViolation
+}
+
+func (e ErrBoundaryViolations) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		descriptions[i] = violation.String()
+	}
+	return fmt.Sprintf(
+		"boundary validation failed with %d violations: %s", len(e.Violations), strings.Join(descriptions, "; "),
+	)
+}
+
+// ErrCloneVerificationFailed indicates that a cloned graph is not an independent, identical copy of the original.
+type ErrCloneVerificationFailed struct {
+	Reason string
+}
+
+func (e ErrCloneVerificationFailed) Error() string {
+	return fmt.Sprintf("clone verification failed: %s", e.Reason)
+}
+
+// ErrDeadlineExceeded indicates that a node was resolved as unresolvable because the deadline of the graph passed.
+type ErrDeadlineExceeded struct {
+	Deadline time.Time
+}
+
+func (e ErrDeadlineExceeded) Error() string {
+	return fmt.Sprintf("the graph deadline of %s was exceeded", e.Deadline.Format(time.RFC3339))
+}
+
+// ErrNoProgress is returned by DirectedGraph.Ping if the graph has unfinished nodes, but no node has been popped or
+// resolved for longer than the allowed idle time.
+type ErrNoProgress struct {
+	LastProgress time.Time
+	Idle         time.Duration
+}
+
+func (e ErrNoProgress) Error() string {
+	return fmt.Sprintf("no progress since %s (idle for %s)", e.LastProgress.Format(time.RFC3339), e.Idle)
+}
+
+// ErrNodeObviated indicates that a node was resolved as unresolvable because all of its dependencies are optional
+// and unresolvable. See WithOptionalObviation.
+type ErrNodeObviated struct {
+	NodeID string
+}
+
+func (e ErrNodeObviated) Error() string {
+	return fmt.Sprintf("node %q was obviated because all of its optional dependencies are unresolvable", e.NodeID)
+}
+
+// ErrDependencyUnresolvable is the cause of a node that became unresolvable because a required dependency is
+// unresolvable. The Reason is the UnresolvableReason of the dependency, if it has one, so the reason of the node
+// that failed first is propagated through the causes of its dependents.
+type ErrDependencyUnresolvable struct {
+	NodeID         string
+	DependencyID   string
+	DependencyType DependencyType
+	Reason         error
+}
+
+func (e ErrDependencyUnresolvable) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf(
+			"node %q is unresolvable because its %s dependency %q is unresolvable (%v)",
+			e.NodeID, e.DependencyType, e.DependencyID, e.Reason,
+		)
+	}
+	return fmt.Sprintf(
+		"node %q is unresolvable because its %s dependency %q is unresolvable",
+		e.NodeID, e.DependencyType, e.DependencyID,
+	)
+}
+
+func (e ErrDependencyUnresolvable) Unwrap() error {
+	return e.Reason
+}
+
+// ErrDependencyConditionNotMet is the cause of a node that became unresolvable because the condition of a
+// conditional dependency rejected the resolution of the dependency. See Node.ConnectDependencyIf.
+type ErrDependencyConditionNotMet struct {
+	NodeID           string
+	DependencyID     string
+	DependencyStatus ResolutionStatus
+}
+
+func (e ErrDependencyConditionNotMet) Error() string {
+	return fmt.Sprintf(
+		"node %q is unresolvable because the condition of its dependency %q is not met by the status %q",
+		e.NodeID, e.DependencyID, e.DependencyStatus,
+	)
+}
+
+// ErrExpressionNotSatisfied is the cause of a node that became unresolvable because its expression can no longer be
+// satisfied. The Reasons are the UnresolvableReason of each unresolvable dependency of the expression that has one.
+// See Node.ConnectExpression.
+type ErrExpressionNotSatisfied struct {
+	NodeID     string
+	Expression string
+	Reasons    []error
+}
+
+func (e ErrExpressionNotSatisfied) Error() string {
+	return fmt.Sprintf(
+		"node %q is unresolvable because its expression %s can no longer be satisfied", e.NodeID, e.Expression,
+	)
+}
+
+func (e ErrExpressionNotSatisfied) Unwrap() []error {
+	return e.Reasons
+}
+
+// ErrInvalidExpression indicates that an expression passed to Node.ConnectExpression is malformed.
+type ErrInvalidExpression struct {
+	Reason string
+}
+
+func (e ErrInvalidExpression) Error() string {
+	return fmt.Sprintf("invalid expression (%s)", e.Reason)
+}
+
+// ErrNilDependencyCondition indicates that Node.ConnectDependencyIf was called without a condition.
+type ErrNilDependencyCondition struct {
+	NodeID       string
+	DependencyID string
+}
+
+func (e ErrNilDependencyCondition) Error() string {
+	return fmt.Sprintf("the condition of the dependency %q of node %q is nil", e.DependencyID, e.NodeID)
+}
+
+// ErrDependencyAlreadyResolved indicates that a dependency cannot be changed because its resolution has already been
+// processed by the node.
+type ErrDependencyAlreadyResolved struct {
+	NodeID       string
+	DependencyID string
+}
+
+func (e ErrDependencyAlreadyResolved) Error() string {
+	return fmt.Sprintf("dependency %q of node %q has already been resolved", e.DependencyID, e.NodeID)
+}
+
 // ErrConnectionAlreadyExists indicates that the connection you are trying to create already exists.
 type ErrConnectionAlreadyExists struct {
 	SourceNodeID      string
@@ -80,6 +442,21 @@ func (e ErrConnectionDoesNotExist) Error() string {
 	)
 }
 
+// ErrNodeAlreadyReady indicates that a dependency could not be connected because the node is already ready and
+// the graph is configured with ReadyNodeConnectionReject.
+type ErrNodeAlreadyReady struct {
+	NodeID       string
+	DependencyID string
+}
+
+func (e ErrNodeAlreadyReady) Error() string {
+	return fmt.Sprintf(
+		"cannot connect dependency %q to node %q; the node is already ready",
+		e.DependencyID,
+		e.NodeID,
+	)
+}
+
 type ErrNodeResolutionAlreadySet struct {
 	NodeID         string
 	ExistingStatus ResolutionStatus
@@ -104,16 +481,70 @@ func (e ErrNodeResolutionUnknown) Error() string {
 	)
 }
 
+// ErrNodeNotRetryable is returned by Node.ResetResolution if the node cannot be returned to Waiting. The Reason
+// describes why.
+type ErrNodeNotRetryable struct {
+	NodeID string
+	Reason string
+}
+
+func (e ErrNodeNotRetryable) Error() string {
+	return fmt.Sprintf("node %q cannot be retried (%s)", e.NodeID, e.Reason)
+}
+
+// ErrInvalidResolutionRecord is returned by ApplyResolutions if a record of the history is inconsistent with the
+// graph or with the records before it. The Cause describes the inconsistency.
+type ErrInvalidResolutionRecord struct {
+	Index  int
+	Record ResolutionRecord
+	Cause  error
+}
+
+func (e ErrInvalidResolutionRecord) Error() string {
+	return fmt.Sprintf("invalid resolution record %d for node %q (%v)", e.Index, e.Record.NodeID, e.Cause)
+}
+
+func (e ErrInvalidResolutionRecord) Unwrap() error {
+	return e.Cause
+}
+
+// ErrInvalidResolutionStatus indicates that a node was to be resolved with a status other than Resolved or
+// Unresolvable.
+type ErrInvalidResolutionStatus struct {
+	NodeID string
+	Status ResolutionStatus
+}
+
+func (e ErrInvalidResolutionStatus) Error() string {
+	return fmt.Sprintf("node %q cannot be resolved with the status %q", e.NodeID, e.Status)
+}
+
+// ErrNodeNotReady indicates that a node was resolved in a replayed history before its dependencies allowed it to
+// run.
+type ErrNodeNotReady struct {
+	NodeID string
+}
+
+func (e ErrNodeNotReady) Error() string {
+	return fmt.Sprintf("node %q was resolved before it was ready", e.NodeID)
+}
+
+// ErrDuplicateDependencyResolution is returned if a node is notified of the resolution of a dependency that it has
+// already recorded as resolved or failed. The statuses describe the state of both nodes at the time of the
+// notification.
 type ErrDuplicateDependencyResolution struct {
-	NodeID       string
-	DependencyID string
+	NodeID           string
+	DependencyID     string
+	DependencyType   DependencyType
+	NodeStatus       ResolutionStatus
+	DependencyStatus ResolutionStatus
 }
 
 func (e ErrDuplicateDependencyResolution) Error() string {
 	return fmt.Sprintf(
-		"attempted to re-resolve dependency %q of node %q; the connection remains; but there are"+
-			" no outstanding requirements",
-		e.NodeID, e.DependencyID,
+		"attempted to re-resolve %s dependency %q (status %q) of node %q (status %q); the connection remains,"+
+			" but there are no outstanding requirements",
+		e.DependencyType, e.DependencyID, e.DependencyStatus, e.NodeID, e.NodeStatus,
 	)
 }
 
@@ -0,0 +1,135 @@
+package dgraph
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultErrorPathPattern matches the IDs of the error nodes of Arcaflow workflows, such as "steps.example.failed".
+// Connections to these nodes are rendered on the error path unless WithErrorPathClassifier is used.
+var DefaultErrorPathPattern = regexp.MustCompile(`\.(?:error|crashed|failed|deploy_failed)$`)
+
+// WithErrorPathClassifier replaces the function that decides whether a connection to the node with the specified
+// ID is on the error path, which the Mermaid and DOT renderers display separately. The default classifier is
+// DefaultErrorPathPattern.MatchString; a custom regular expression can be used the same way.
+func WithErrorPathClassifier(classifier func(nodeID string) bool) Option {
+	return func(c *config) {
+		c.errorPathClassifier = classifier
+	}
+}
+
+func (c config) isErrorPath(nodeID string) bool {
+	if c.errorPathClassifier == nil {
+		return DefaultErrorPathPattern.MatchString(nodeID)
+	}
+	return c.errorPathClassifier(nodeID)
+}
+
+// ExportFilter restricts which nodes and connections are included when exporting or rendering a graph. The zero
+// value includes the whole graph.
+type ExportFilter struct {
+	// Statuses limits the export to nodes in one of the listed resolution statuses, together with the connections
+	// between them. If empty, nodes of all statuses are included. For example, filtering for Waiting and
+	// Unresolvable produces a view of the remaining work and the failure surface of a partially executed graph.
+	Statuses []ResolutionStatus
+	// DependencyTypes limits the export to connections with one of the listed dependency types. If empty,
+	// connections of all dependency types are included. Nodes are not affected, so a node remains in the output
+	// even if all of its connections are filtered out.
+	DependencyTypes []DependencyType
+	// ExcludedDependencyTypes removes the connections with one of the listed dependency types from the export, for
+	// example CompletionAndDependency and ObviatedDependency to hide bookkeeping connections after a run.
+	ExcludedDependencyTypes []DependencyType
+}
+
+func (f ExportFilter) includesStatus(status ResolutionStatus) bool {
+	return len(f.Statuses) == 0 || slices.Contains(f.Statuses, status)
+}
+
+func (f ExportFilter) includesDependencyType(dependencyType DependencyType) bool {
+	return (len(f.DependencyTypes) == 0 || slices.Contains(f.DependencyTypes, dependencyType)) &&
+		!slices.Contains(f.ExcludedDependencyTypes, dependencyType)
+}
+
+// filteredConnections returns the connections from source to destination nodes where both nodes and the dependency
+// type pass the filter.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) filteredConnections(filter ExportFilter) map[string]map[string]struct{} {
+	result := make(map[string]map[string]struct{}, len(d.connectionsFromNode))
+	for source, destinations := range d.connectionsFromNode {
+		if !filter.includesStatus(d.nodes[source].status) {
+			continue
+		}
+		result[source] = make(map[string]struct{}, len(destinations))
+		for destination := range destinations {
+			n := d.nodes[destination]
+			if filter.includesStatus(n.status) && filter.includesDependencyType(n.dependencyType(source)) {
+				result[source][destination] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+// renderSnapshot is a consistent copy of the parts of the graph needed by the renderers. It is taken while the lock
+// is held, so that the formatting can happen without holding the lock.
+type renderSnapshot[NodeType any] struct {
+	// nodes contains the nodes passing the filter, sorted by ID.
+	nodes []renderNode[NodeType]
+	// connections contains the connections between the nodes passing the filter, ordered as by sortConnections.
+	connections             []renderConnection
+	preserveConnectionOrder bool
+}
+
+type renderNode[NodeType any] struct {
+	id          string
+	status      ResolutionStatus
+	annotations []string
+	description string
+	label       string
+	node        *node[NodeType]
+}
+
+type renderConnection struct {
+	from           string
+	to             string
+	label          string
+	description    string
+	dependencyType DependencyType
+	errorPath      bool
+}
+
+// renderSnapshot copies the nodes and connections passing the filter.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) renderSnapshot(filter ExportFilter) renderSnapshot[NodeType] {
+	snapshot := renderSnapshot[NodeType]{preserveConnectionOrder: d.config.preserveConnectionOrder}
+	for nodeID, n := range d.nodes {
+		if filter.includesStatus(n.status) {
+			snapshot.nodes = append(snapshot.nodes, renderNode[NodeType]{
+				nodeID, n.status, slices.Clone(n.annotations), n.description, n.attributes[LabelMetadataKey], n,
+			})
+		}
+	}
+	slices.SortFunc(snapshot.nodes, func(a, b renderNode[NodeType]) int {
+		return strings.Compare(a.id, b.id)
+	})
+	var connections [][2]string
+	for source, destinations := range d.filteredConnections(filter) {
+		for destination := range destinations {
+			connections = append(connections, [2]string{source, destination})
+		}
+	}
+	d.sortConnections(connections)
+	snapshot.connections = make([]renderConnection, len(connections))
+	for i, connection := range connections {
+		snapshot.connections[i] = renderConnection{
+			from:        connection[0],
+			to:          connection[1],
+			label:       d.connectionMetadata[connection][LabelMetadataKey],
+			description: d.connectionMetadata[connection][DescriptionMetadataKey],
+		}
+		snapshot.connections[i].dependencyType = d.nodes[connection[1]].dependencyType(connection[0])
+		snapshot.connections[i].errorPath = d.config.isErrorPath(connection[1])
+	}
+	return snapshot
+}
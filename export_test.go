@@ -0,0 +1,65 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_MermaidFiltered(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	e := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c.error", "e"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, e.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, c.ResolveNode(dgraph.Unresolvable))
+
+	assert.Equals(t, d.MermaidFiltered(dgraph.ExportFilter{}), d.Mermaid())
+	assert.Equals(t, d.MermaidFiltered(dgraph.ExportFilter{
+		Statuses: []dgraph.ResolutionStatus{dgraph.Waiting, dgraph.Unresolvable},
+	}), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+b-->c
+%% Error path
+b-->c.error
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidFilteredDependencyTypes(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.OrDependency))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.CompletionAndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+
+	// Resolving a obviates the OR dependency of c on b.
+	assert.Equals(t, d.MermaidFiltered(dgraph.ExportFilter{
+		ExcludedDependencyTypes: []dgraph.DependencyType{dgraph.CompletionAndDependency, dgraph.ObviatedDependency},
+	}), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+a-->c
+%% Error path
+%% Mermaid end
+`)
+	assert.Equals(t, d.MermaidFiltered(dgraph.ExportFilter{
+		DependencyTypes: []dgraph.DependencyType{dgraph.CompletionAndDependency},
+	}), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+a-->b
+%% Error path
+%% Mermaid end
+`)
+}
@@ -0,0 +1,378 @@
+package dgraph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Expression is a boolean expression over the dependencies of a node, built with And, Or, and Dependency. It is
+// connected with Node.ConnectExpression, which removes the need for artificial join nodes in complex gating.
+type Expression struct {
+	// Operator is ConditionAll for And, ConditionAny for Or, and ConditionDependency for a single dependency.
+	Operator ConditionOperator
+	// Operands are the subexpressions of ConditionAll and ConditionAny.
+	Operands []Expression
+	// DependencyID is the ID of the dependency of a ConditionDependency.
+	DependencyID string
+	// DependencyType decides which resolution of the dependency satisfies a ConditionDependency. It is one of
+	// AndDependency, CompletionAndDependency, and OnUnresolvableDependency.
+	DependencyType DependencyType
+	// err records an operand that is neither a string nor an Expression, so that ConnectExpression can report it.
+	err error
+}
+
+// And returns an expression that is satisfied if all of its operands are satisfied. Each operand is either an
+// Expression or the ID of an AndDependency as a string.
+func And(operands ...any) Expression {
+	return newExpression(ConditionAll, operands)
+}
+
+// Or returns an expression that is satisfied if any of its operands is satisfied. Each operand is either an
+// Expression or the ID of an AndDependency as a string.
+func Or(operands ...any) Expression {
+	return newExpression(ConditionAny, operands)
+}
+
+// Dependency returns an expression for a single dependency, which is satisfied if the resolution of the dependency
+// satisfies the specified dependency type.
+func Dependency(dependencyID string, dependencyType DependencyType) Expression {
+	return Expression{Operator: ConditionDependency, DependencyID: dependencyID, DependencyType: dependencyType}
+}
+
+func newExpression(operator ConditionOperator, operands []any) Expression {
+	result := Expression{Operator: operator, Operands: make([]Expression, len(operands))}
+	for i, operand := range operands {
+		switch o := operand.(type) {
+		case string:
+			result.Operands[i] = Dependency(o, AndDependency)
+		case Expression:
+			result.Operands[i] = o
+		default:
+			result.Operands[i] = Expression{
+				err: &ErrInvalidExpression{fmt.Sprintf("operand %d has unsupported type %T", i, operand)},
+			}
+		}
+	}
+	return result
+}
+
+// String renders the expression in a function notation, for example "and(or(a, b), c)". Dependencies that are not
+// AndDependency are rendered with their type, for example "on-unresolvable(a)".
+func (e Expression) String() string {
+	switch e.Operator {
+	case ConditionDependency:
+		if e.DependencyType == AndDependency {
+			return e.DependencyID
+		}
+		return fmt.Sprintf("%s(%s)", e.DependencyType, e.DependencyID)
+	case ConditionAll, ConditionAny:
+		operands := make([]string, len(e.Operands))
+		for i, operand := range e.Operands {
+			operands[i] = operand.String()
+		}
+		name := "and"
+		if e.Operator == ConditionAny {
+			name = "or"
+		}
+		return fmt.Sprintf("%s(%s)", name, strings.Join(operands, ", "))
+	default:
+		return "invalid"
+	}
+}
+
+// validate returns an ErrInvalidExpression if the expression is malformed, and otherwise returns a copy of the
+// expression with normalized dependency IDs.
+func (e Expression) validate(normalizeID func(string) string) (Expression, error) {
+	if e.err != nil {
+		return Expression{}, e.err
+	}
+	switch e.Operator {
+	case ConditionDependency:
+		switch e.DependencyType {
+		case AndDependency, CompletionAndDependency, OnUnresolvableDependency:
+		default:
+			return Expression{}, &ErrInvalidExpression{
+				fmt.Sprintf("dependency %q has unsupported type %q", e.DependencyID, e.DependencyType),
+			}
+		}
+		return Dependency(normalizeID(e.DependencyID), e.DependencyType), nil
+	case ConditionAll, ConditionAny:
+		if len(e.Operands) == 0 {
+			return Expression{}, &ErrInvalidExpression{fmt.Sprintf("%s has no operands", e.String())}
+		}
+		result := Expression{Operator: e.Operator, Operands: make([]Expression, len(e.Operands))}
+		for i, operand := range e.Operands {
+			validated, err := operand.validate(normalizeID)
+			if err != nil {
+				return Expression{}, err
+			}
+			result.Operands[i] = validated
+		}
+		return result, nil
+	default:
+		return Expression{}, &ErrInvalidExpression{fmt.Sprintf("unknown operator %q", e.Operator)}
+	}
+}
+
+// dependencyIDs returns the sorted, unique IDs of the dependencies in the expression.
+func (e Expression) dependencyIDs() []string {
+	var result []string
+	e.walk(func(leaf Expression) {
+		result = append(result, leaf.DependencyID)
+	})
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// walk calls the function for each dependency in the expression.
+func (e Expression) walk(fn func(leaf Expression)) {
+	if e.Operator == ConditionDependency {
+		fn(e)
+		return
+	}
+	for _, operand := range e.Operands {
+		operand.walk(fn)
+	}
+}
+
+// clone returns a deep copy of the expression.
+func (e Expression) clone() Expression {
+	if e.Operands != nil {
+		operands := make([]Expression, len(e.Operands))
+		for i, operand := range e.Operands {
+			operands[i] = operand.clone()
+		}
+		e.Operands = operands
+	}
+	return e
+}
+
+// renamed returns a copy of the expression in which the specified dependency is renamed.
+func (e Expression) renamed(oldID, newID string) Expression {
+	e = e.clone()
+	var rename func(e *Expression)
+	rename = func(e *Expression) {
+		if e.Operator == ConditionDependency && e.DependencyID == oldID {
+			e.DependencyID = newID
+		}
+		for i := range e.Operands {
+			rename(&e.Operands[i])
+		}
+	}
+	rename(&e)
+	return e
+}
+
+// without returns a copy of the expression in which the specified dependency is removed. Operators that are left
+// without operands are removed as well. The second return value is false if nothing is left of the expression.
+func (e Expression) without(dependencyID string) (Expression, bool) {
+	if e.Operator == ConditionDependency {
+		return e, e.DependencyID != dependencyID
+	}
+	result := Expression{Operator: e.Operator}
+	for _, operand := range e.Operands {
+		if remaining, ok := operand.without(dependencyID); ok {
+			result.Operands = append(result.Operands, remaining)
+		}
+	}
+	return result, len(result.Operands) > 0
+}
+
+// evaluate evaluates the expression with a three-valued logic. The leaf function returns whether a dependency
+// satisfies its leaf, and whether that is already known. The second return value is false if the result of the
+// expression depends on dependencies that have not been resolved yet.
+func (e Expression) evaluate(leaf func(leaf Expression) (bool, bool)) (bool, bool) {
+	switch e.Operator {
+	case ConditionDependency:
+		return leaf(e)
+	case ConditionAny:
+		known := true
+		for _, operand := range e.Operands {
+			satisfied, operandKnown := operand.evaluate(leaf)
+			if satisfied && operandKnown {
+				return true, true
+			}
+			known = known && operandKnown
+		}
+		return false, known
+	default:
+		known := true
+		for _, operand := range e.Operands {
+			satisfied, operandKnown := operand.evaluate(leaf)
+			if !satisfied && operandKnown {
+				return false, true
+			}
+			known = known && operandKnown
+		}
+		return known, known
+	}
+}
+
+func (n *node[NodeType]) ConnectExpression(expression Expression) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	expression, err := expression.validate(n.dg.config.normalizeID)
+	if err != nil {
+		return err
+	}
+	// Validate all connections first, so that the graph is left unchanged if any of them cannot be made.
+	var newDependencies []string
+	for _, dependencyID := range expression.dependencyIDs() {
+		_, connected := n.dg.connectionsToNode[n.id][dependencyID]
+		if connected && n.expression != nil && n.declaredDependencyType(dependencyID) == ExpressionDependency {
+			// The dependency is shared with an earlier expression.
+			continue
+		}
+		if dependencyID == n.id {
+			return &ErrCannotConnectToSelf{n.id}
+		}
+		if err := n.dg.validateConnection(dependencyID, n.id, ExpressionDependency); err != nil {
+			return err
+		}
+		newDependencies = append(newDependencies, dependencyID)
+	}
+	for _, dependencyID := range newDependencies {
+		if err := n.dg.connect(dependencyID, n.id, ExpressionDependency); err != nil {
+			return err
+		}
+	}
+	if n.expression != nil {
+		expression = Expression{Operator: ConditionAll, Operands: []Expression{*n.expression, expression}}
+	}
+	n.expression = &expression
+	n.dg.markChanged(n.id)
+	return nil
+}
+
+func (n *node[NodeType]) DependencyExpression() (Expression, bool) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	if n.expression == nil {
+		return Expression{}, false
+	}
+	return n.expression.clone(), true
+}
+
+// cloneExpression returns a deep copy of the expression of the node, or nil if it has none.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) cloneExpression() *Expression {
+	if n.expression == nil {
+		return nil
+	}
+	expression := n.expression.clone()
+	return &expression
+}
+
+// removeFromExpression removes a dependency that was disconnected or obviated from the expression of the node.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) removeFromExpression(dependencyNodeID string) {
+	if n.expression == nil {
+		return
+	}
+	if remaining, ok := n.expression.without(dependencyNodeID); ok {
+		n.expression = &remaining
+	} else {
+		n.expression = nil
+	}
+}
+
+// expressionDependencyResolved applies the resolution of a dependency of the expression of the node. The node
+// becomes ready once the expression and all other hard dependencies are satisfied, and unresolvable once the
+// expression can no longer be satisfied. It is only called for Waiting nodes, since dependencyResolved ignores the
+// resolutions for all others, including the nodes whose expression has already failed.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) expressionDependencyResolved(
+	dependencyNodeID string,
+	dependencyResolution ResolutionStatus,
+) error {
+	satisfied, known := n.expression.evaluate(n.expressionLeaf)
+	switch {
+	case !known:
+		n.traceDependency(dependencyNodeID, ExpressionDependency, dependencyResolution, DependencyIgnored)
+		return nil
+	case !satisfied:
+		n.traceDependency(dependencyNodeID, ExpressionDependency, dependencyResolution, DependencyFailed)
+		return n.expressionFailed()
+	}
+	n.traceDependency(dependencyNodeID, ExpressionDependency, dependencyResolution, DependencySatisfied)
+	n.markObviated(ExpressionDependency)
+	if !n.hasOutstandingHardDependency() {
+		n.markReady(ReadyDependenciesResolved)
+	}
+	return nil
+}
+
+// reconcileExpression re-evaluates the expression of the node after its inbound connections changed. Readiness
+// is left to reconcile, so that the node doesn't become ready before the graph is started.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) reconcileExpression() error {
+	if n.expression == nil || n.ready || n.status != Waiting || !n.hasOutstandingDependency(ExpressionDependency) {
+		return nil
+	}
+	satisfied, known := n.expression.evaluate(n.expressionLeaf)
+	switch {
+	case !known:
+		return nil
+	case !satisfied:
+		return n.expressionFailed()
+	}
+	n.markObviated(ExpressionDependency)
+	return nil
+}
+
+// expressionFailed marks the node as unresolvable because its expression can no longer be satisfied.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) expressionFailed() error {
+	if n.unresolvableCause == nil {
+		var reasons []error
+		for _, dependencyNodeID := range sortedKeys(n.failedDependencies) {
+			if reason := n.dg.nodes[dependencyNodeID].unresolvableReason(); reason != nil {
+				reasons = append(reasons, reason)
+			}
+		}
+		n.unresolvableCause = &ErrExpressionNotSatisfied{n.id, n.expression.String(), reasons}
+	}
+	n.markReady(ReadyUnresolvableDependency)
+	return n.resolveNode(Unresolvable)
+}
+
+// expressionLeaf returns whether a dependency satisfies its leaf in the expression of the node, and whether that is
+// already known.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) expressionLeaf(leaf Expression) (bool, bool) {
+	if _, ok := n.resolvedDependencies[leaf.DependencyID]; ok {
+		return conditionSatisfied(leaf.DependencyType, Resolved), true
+	}
+	if _, ok := n.failedDependencies[leaf.DependencyID]; ok {
+		return conditionSatisfied(leaf.DependencyType, Unresolvable), true
+	}
+	return false, false
+}
+
+// expressionCondition converts a part of the expression of the node into a ReadinessCondition with the current
+// state of the graph.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) expressionCondition(e Expression) ReadinessCondition {
+	result := ReadinessCondition{Operator: e.Operator, DependencyID: e.DependencyID, DependencyType: e.DependencyType}
+	if e.Operator == ConditionDependency {
+		result.Status = n.dg.nodes[e.DependencyID].status
+		satisfied, known := n.expressionLeaf(e)
+		result.Satisfied = satisfied && known
+		return result
+	}
+	result.Satisfied = e.Operator == ConditionAll
+	for _, operand := range e.Operands {
+		condition := n.expressionCondition(operand)
+		result.Operands = append(result.Operands, condition)
+		if e.Operator == ConditionAll {
+			result.Satisfied = result.Satisfied && condition.Satisfied
+		} else {
+			result.Satisfied = result.Satisfied || condition.Satisfied
+		}
+	}
+	return result
+}
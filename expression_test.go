@@ -0,0 +1,157 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// newExpressionGraph creates a started graph with the nodes a, b, c, and x, where x depends on and(or(a, b), c).
+func newExpressionGraph(t *testing.T) (dgraph.DirectedGraph[string], dgraph.Node[string]) {
+	d := dgraph.New[string]()
+	for _, nodeID := range []string{"a", "b", "c", "x"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("x"))
+	assert.NoError(t, x.ConnectExpression(dgraph.And(dgraph.Or("a", "b"), "c")))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b", "c"})
+	return d, x
+}
+
+func TestNode_ConnectExpression(t *testing.T) {
+	d, x := newExpressionGraph(t)
+	expression, ok := x.DependencyExpression()
+	assert.Equals(t, ok, true)
+	assert.Equals(t, expression.String(), "and(or(a, b), c)")
+	assert.Equals(t, x.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.ExpressionDependency,
+		"b": dgraph.ExpressionDependency,
+		"c": dgraph.ExpressionDependency,
+	})
+
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, x.IsReady(), false)
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.NoError(t, c.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, x.IsReady(), true)
+	// The dependency that is no longer needed is obviated.
+	assert.Equals(t, x.OutstandingDependencies(), map[string]dgraph.DependencyType{"a": dgraph.ObviatedDependency})
+	assert.Equals(t, x.ReadinessCondition().Satisfied, true)
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"x"})
+}
+
+func TestNode_ConnectExpression_NotSatisfied(t *testing.T) {
+	d, x := newExpressionGraph(t)
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, x.ResolutionStatus(), dgraph.Waiting)
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, x.ResolutionStatus(), dgraph.Unresolvable)
+	assert.InstanceOf[*dgraph.ErrExpressionNotSatisfied](t, x.UnresolvableCause())
+	assert.Equals(t, x.UnresolvableCause().(*dgraph.ErrExpressionNotSatisfied).Expression, "and(or(a, b), c)")
+}
+
+func TestNode_ConnectExpression_DependencyTypes(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, nodeID := range []string{"step", "cleanup", "handler"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	handler := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("handler"))
+	assert.NoError(t, handler.ConnectExpression(dgraph.And(
+		dgraph.Dependency("step", dgraph.OnUnresolvableDependency),
+		dgraph.Dependency("cleanup", dgraph.CompletionAndDependency),
+	)))
+	expression, _ := handler.DependencyExpression()
+	assert.Equals(t, expression.String(), "and(on-unresolvable(step), completion-and(cleanup))")
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	step := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("step"))
+	assert.NoError(t, step.ResolveNode(dgraph.Unresolvable))
+	cleanup := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("cleanup"))
+	assert.NoError(t, cleanup.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, handler.IsReady(), true)
+	assert.Equals(t, handler.ResolutionStatus(), dgraph.Waiting)
+}
+
+func TestNode_ConnectExpression_Invalid(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.InstanceOf[*dgraph.ErrInvalidExpression](t, a.ConnectExpression(dgraph.And("b", 42)))
+	assert.InstanceOf[*dgraph.ErrInvalidExpression](t, a.ConnectExpression(dgraph.Or()))
+	assert.InstanceOf[*dgraph.ErrInvalidExpression](t, a.ConnectExpression(
+		dgraph.Dependency("b", dgraph.OptionalDependency),
+	))
+	assert.InstanceOf[*dgraph.ErrCannotConnectToSelf](t, a.ConnectExpression(dgraph.Or("a", "b")))
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, a.ConnectExpression(dgraph.Or("b", "c")))
+	// Failed expressions leave the graph unchanged.
+	assert.Equals(t, a.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+	_, ok := a.DependencyExpression()
+	assert.Equals(t, ok, false)
+}
+
+func TestNode_ConnectExpression_Disconnect(t *testing.T) {
+	d, x := newExpressionGraph(t)
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.NoError(t, x.DisconnectInbound("b"))
+	assert.NoError(t, d.Reconcile())
+	// Only "a" is left in the OR, so the expression can no longer be satisfied.
+	expression, _ := x.DependencyExpression()
+	assert.Equals(t, expression.String(), "and(or(a), c)")
+	assert.Equals(t, x.ResolutionStatus(), dgraph.Unresolvable)
+}
+
+func TestDirectedGraph_Compile_Expression(t *testing.T) {
+	d, _ := newExpressionGraph(t)
+	plan := assert.NoErrorR[*dgraph.ExecutionPlan[string]](t)(d.Compile())
+	run := plan.NewRun()
+	assert.Equals(t, run.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Waiting,
+		"b": dgraph.Waiting,
+		"c": dgraph.Waiting,
+	})
+	assert.NoError(t, run.ResolveNode("a", dgraph.Resolved))
+	assert.Equals(t, run.HasReadyNodes(), false)
+	assert.NoError(t, run.ResolveNode("c", dgraph.Resolved))
+	assert.Equals(t, run.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"x": dgraph.Waiting})
+}
+
+func TestNode_ConnectExpression_Copies(t *testing.T) {
+	newOrGraph := func() dgraph.DirectedGraph[string] {
+		d := dgraph.New[string]()
+		for _, nodeID := range []string{"a", "b", "c"} {
+			assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+		}
+		c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+		assert.NoError(t, c.ConnectExpression(dgraph.Or("a", "b")))
+		return d
+	}
+	merged := dgraph.New[string]()
+	assert.NoError(t, merged.Merge(newOrGraph(), dgraph.WithMergeIDPrefix("x.")))
+	sequenced := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(
+		dgraph.Sequence(newOrGraph(), dgraph.New[string](), dgraph.AndDependency),
+	)
+	for name, tc := range map[string]struct {
+		d      dgraph.DirectedGraph[string]
+		prefix string
+	}{
+		"merge":    {merged, "x."},
+		"sequence": {sequenced, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := assert.NoErrorR[dgraph.Node[string]](t)(tc.d.GetNodeByID(tc.prefix + "c"))
+			expression, ok := c.DependencyExpression()
+			assert.Equals(t, ok, true)
+			assert.Equals(t, expression.String(), "or("+tc.prefix+"a, "+tc.prefix+"b)")
+			assert.NoError(t, tc.d.PushStartingNodes())
+			a := assert.NoErrorR[dgraph.Node[string]](t)(tc.d.GetNodeByID(tc.prefix + "a"))
+			assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+			assert.Equals(t, c.IsReady(), true)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package dgraph
+
+// FailureHandler is an on-failure companion node added by DirectedGraph.AddFailureHandlers.
+type FailureHandler[NodeType any] struct {
+	// ID is the ID of the handler node.
+	ID   string
+	Item NodeType
+}
+
+func (d *directedGraph[NodeType]) AddFailureHandlers(
+	handlers map[string]FailureHandler[NodeType],
+) (map[string]Node[NodeType], error) {
+	primaryIDs := make(map[string]string, len(handlers))
+	for primaryID := range handlers {
+		primaryIDs[primaryID] = d.config.normalizeID(primaryID)
+	}
+	d.lock.Lock()
+	defer d.unlock()
+	// Validate all handlers first, so that the graph is left unchanged if any of them can't be added.
+	order := sortedKeys(primaryIDs)
+	seen := make(map[string]struct{}, len(handlers))
+	for _, primaryID := range order {
+		normalizedPrimaryID := primaryIDs[primaryID]
+		if _, ok := d.nodes[normalizedPrimaryID]; !ok {
+			return nil, d.nodeNotFound(normalizedPrimaryID)
+		}
+		handlerID := d.config.normalizeID(handlers[primaryID].ID)
+		if d.config.idPattern != nil && !d.config.idPattern.MatchString(handlerID) {
+			return nil, &ErrInvalidNodeID{handlerID, d.config.idPattern.String()}
+		}
+		if _, ok := d.nodes[handlerID]; ok {
+			return nil, ErrNodeAlreadyExists{handlerID}
+		}
+		if _, ok := seen[handlerID]; ok {
+			return nil, ErrNodeAlreadyExists{handlerID}
+		}
+		seen[handlerID] = struct{}{}
+	}
+	result := make(map[string]Node[NodeType], len(handlers))
+	for _, primaryID := range order {
+		handler, err := d.addNode(d.config.normalizeID(handlers[primaryID].ID), handlers[primaryID].Item)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.connect(primaryIDs[primaryID], handler.id, OnUnresolvableDependency); err != nil {
+			return nil, err
+		}
+		// Handlers added while the graph is running must not miss a primary that has already been resolved.
+		delete(d.changedNodes, handler.id)
+		if err := handler.reconcile(); err != nil {
+			return nil, err
+		}
+		result[primaryIDs[primaryID]] = handler
+	}
+	return result, nil
+}
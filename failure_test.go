@@ -0,0 +1,67 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_AddFailureHandlers(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.AndDependency))
+	handlers := assert.NoErrorR[map[string]dgraph.Node[string]](t)(d.AddFailureHandlers(
+		map[string]dgraph.FailureHandler[string]{
+			"a": {ID: "a-cleanup", Item: "cleanup a"},
+			"c": {ID: "c-cleanup", Item: "cleanup c"},
+		},
+	))
+	assert.Equals(t, handlers["a"].ID(), "a-cleanup")
+	assert.Equals(t, handlers["c"].Item(), "cleanup c")
+	assert.Equals(t, handlers["c"].OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"c": dgraph.OnUnresolvableDependency,
+	})
+
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	// b fails, so c becomes unresolvable without running, and the handler of c runs.
+	assert.NoError(t, b.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, handlers["a"].ResolutionStatus(), dgraph.Unresolvable)
+	ready := d.PopReadyNodes()
+	assert.Equals(t, ready["c-cleanup"], dgraph.Waiting)
+	assert.Equals(t, ready["c"], dgraph.Unresolvable)
+}
+
+func TestDirectedGraph_AddFailureHandlersRunning(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	// The primary has already failed, so the handler is ready right away.
+	assert.NoErrorR[map[string]dgraph.Node[string]](t)(d.AddFailureHandlers(
+		map[string]dgraph.FailureHandler[string]{"a": {ID: "a-cleanup"}},
+	))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a-cleanup"})
+}
+
+func TestDirectedGraph_AddFailureHandlersInvalid(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	_, err := d.AddFailureHandlers(map[string]dgraph.FailureHandler[string]{
+		"a": {ID: "cleanup"},
+		"b": {ID: "cleanup"},
+	})
+	assert.InstanceOf[dgraph.ErrNodeAlreadyExists](t, err)
+	_, err = d.AddFailureHandlers(map[string]dgraph.FailureHandler[string]{
+		"a": {ID: "a-cleanup"},
+		"x": {ID: "x-cleanup"},
+	})
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+	assert.Equals(t, len(d.ListNodes()), 2)
+}
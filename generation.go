@@ -0,0 +1,13 @@
+package dgraph
+
+func (d *directedGraph[NodeType]) Generation() uint64 {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.generation
+}
+
+// advanceGeneration records a change of the state of the graph.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) advanceGeneration() {
+	d.generation++
+}
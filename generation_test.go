@@ -0,0 +1,47 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Generation(t *testing.T) {
+	d := dgraph.New[string]()
+	generation := d.Generation()
+	// advanced checks that the generation increased since the last call, and remembers the current one.
+	advanced := func() bool {
+		current := d.Generation()
+		result := current > generation
+		generation = current
+		return result
+	}
+
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.Equals(t, advanced(), true)
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.Equals(t, advanced(), true)
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, advanced(), true)
+
+	// Reads don't change the generation.
+	d.ListNodes()
+	d.HasReadyNodes()
+	d.Health()
+	assert.Equals(t, advanced(), false)
+
+	d.PopReadyNodes()
+	assert.Equals(t, advanced(), true)
+	d.PopReadyNodes()
+	assert.Equals(t, advanced(), false)
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, advanced(), true)
+	assert.Equals(t, d.Health().Generation, generation)
+
+	// Snapshots carry the generation.
+	data := assert.NoErrorR[[]byte](t)(d.ExportJSON())
+	imported := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(dgraph.ImportJSON[string](data))
+	assert.Equals(t, imported.Generation(), generation)
+}
@@ -0,0 +1,11 @@
+module go.arcalot.io/dgraph/graphadapter
+
+go 1.22.0
+
+require (
+	github.com/dominikbraun/graph v0.23.0
+	go.arcalot.io/assert v1.8.0
+	go.arcalot.io/dgraph v0.0.0
+)
+
+replace go.arcalot.io/dgraph => ../
@@ -0,0 +1,219 @@
+// Package graphadapter connects dgraph to github.com/dominikbraun/graph. NewStore exposes a dgraph graph as a
+// graph.Store, so the algorithms of that library can run directly on graphs built with dgraph, and FromGraph
+// imports a graph.Graph into a new dgraph graph.
+//
+// The dependency type of a connection is carried in the DependencyTypeAttribute edge attribute. Edges without the
+// attribute are treated as AndDependency.
+package graphadapter
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	"github.com/dominikbraun/graph"
+	"go.arcalot.io/dgraph"
+)
+
+// DependencyTypeAttribute is the edge attribute holding the dgraph.DependencyType of a connection.
+const DependencyTypeAttribute = "dgraph.dependency_type"
+
+// NewStore returns a graph.Store backed by the specified directed graph. Changes made through the store are applied
+// to the directed graph directly, and vice versa. Use it with graph.NewWithStore and graph.Directed():
+//
+//	g := graph.NewWithStore(hashFunc, graphadapter.NewStore(d), graph.Directed(), graph.Acyclic())
+//
+// Vertex properties are not stored, and edge weights and data are discarded. Updating an edge can only change its
+// dependency type.
+func NewStore[T any](d dgraph.DirectedGraph[T]) graph.Store[string, T] {
+	return &store[T]{d}
+}
+
+type store[T any] struct {
+	dg dgraph.DirectedGraph[T]
+}
+
+func (s *store[T]) AddVertex(hash string, value T, _ graph.VertexProperties) error {
+	_, err := s.dg.AddNode(hash, value)
+	var alreadyExists dgraph.ErrNodeAlreadyExists
+	if errors.As(err, &alreadyExists) {
+		return graph.ErrVertexAlreadyExists
+	}
+	return err
+}
+
+func (s *store[T]) Vertex(hash string) (T, graph.VertexProperties, error) {
+	n, err := s.dg.GetNodeByID(hash)
+	if err != nil {
+		var defaultValue T
+		return defaultValue, graph.VertexProperties{}, vertexError(err)
+	}
+	return n.Item(), graph.VertexProperties{Attributes: map[string]string{}}, nil
+}
+
+func (s *store[T]) RemoveVertex(hash string) error {
+	n, err := s.dg.GetNodeByID(hash)
+	if err != nil {
+		return vertexError(err)
+	}
+	return n.Remove()
+}
+
+func (s *store[T]) ListVertices() ([]string, error) {
+	nodes := s.dg.ListNodes()
+	result := make([]string, 0, len(nodes))
+	for nodeID := range nodes {
+		result = append(result, nodeID)
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+func (s *store[T]) VertexCount() (int, error) {
+	return len(s.dg.ListNodes()), nil
+}
+
+func (s *store[T]) AddEdge(sourceHash, targetHash string, edge graph.Edge[string]) error {
+	target, err := s.dg.GetNodeByID(targetHash)
+	if err != nil {
+		return vertexError(err)
+	}
+	err = target.ConnectDependency(sourceHash, edgeDependencyType(edge))
+	var alreadyExists *dgraph.ErrConnectionAlreadyExists
+	if errors.As(err, &alreadyExists) {
+		return graph.ErrEdgeAlreadyExists
+	}
+	return vertexError(err)
+}
+
+func (s *store[T]) UpdateEdge(sourceHash, targetHash string, edge graph.Edge[string]) error {
+	target, err := s.dg.GetNodeByID(targetHash)
+	if err != nil {
+		return vertexError(err)
+	}
+	current, err := s.Edge(sourceHash, targetHash)
+	if err != nil {
+		return err
+	}
+	if edgeDependencyType(current) == edgeDependencyType(edge) {
+		return nil
+	}
+	if err := target.DisconnectInbound(sourceHash); err != nil {
+		return err
+	}
+	return target.ConnectDependency(sourceHash, edgeDependencyType(edge))
+}
+
+func (s *store[T]) RemoveEdge(sourceHash, targetHash string) error {
+	source, err := s.dg.GetNodeByID(sourceHash)
+	if err != nil {
+		return vertexError(err)
+	}
+	err = source.DisconnectOutbound(targetHash)
+	var doesNotExist *dgraph.ErrConnectionDoesNotExist
+	if errors.As(err, &doesNotExist) {
+		return graph.ErrEdgeNotFound
+	}
+	return vertexError(err)
+}
+
+func (s *store[T]) Edge(sourceHash, targetHash string) (graph.Edge[string], error) {
+	target, err := s.dg.GetNodeByID(targetHash)
+	var notFound *dgraph.ErrNodeNotFound
+	if errors.As(err, &notFound) {
+		return graph.Edge[string]{}, graph.ErrEdgeNotFound
+	} else if err != nil {
+		return graph.Edge[string]{}, err
+	}
+	inbound, err := target.ListInboundConnections()
+	if err != nil {
+		return graph.Edge[string]{}, err
+	}
+	if _, ok := inbound[sourceHash]; !ok {
+		return graph.Edge[string]{}, graph.ErrEdgeNotFound
+	}
+	return newEdge(sourceHash, target), nil
+}
+
+func (s *store[T]) ListEdges() ([]graph.Edge[string], error) {
+	var result []graph.Edge[string]
+	for _, target := range s.dg.ListNodes() {
+		inbound, err := target.ListInboundConnections()
+		if err != nil {
+			return nil, err
+		}
+		for sourceID := range inbound {
+			result = append(result, newEdge(sourceID, target))
+		}
+	}
+	slices.SortFunc(result, func(a, b graph.Edge[string]) int {
+		if a.Source != b.Source {
+			return cmp.Compare(a.Source, b.Source)
+		}
+		return cmp.Compare(a.Target, b.Target)
+	})
+	return result, nil
+}
+
+// FromGraph creates a new directed graph with the vertices and edges of the specified graph. The dependency types
+// are taken from the DependencyTypeAttribute of the edges.
+func FromGraph[T any](g graph.Graph[string, T], options ...dgraph.Option) (dgraph.DirectedGraph[T], error) {
+	result := dgraph.New[T](options...)
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+	for hash := range adjacencyMap {
+		value, err := g.Vertex(hash)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := result.AddNode(hash, value); err != nil {
+			return nil, err
+		}
+	}
+	edges, err := g.Edges()
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range edges {
+		target, err := result.GetNodeByID(edge.Target)
+		if err != nil {
+			return nil, err
+		}
+		if err := target.ConnectDependency(edge.Source, edgeDependencyType(edge)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// vertexError translates an ErrNodeNotFound into graph.ErrVertexNotFound. Other errors are returned unchanged.
+func vertexError(err error) error {
+	var notFound *dgraph.ErrNodeNotFound
+	if errors.As(err, &notFound) {
+		return graph.ErrVertexNotFound
+	}
+	return err
+}
+
+func newEdge[T any](sourceID string, target dgraph.Node[T]) graph.Edge[string] {
+	dependencyType, ok := target.OutstandingDependencies()[sourceID]
+	if !ok {
+		dependencyType = target.ResolvedDependencies()[sourceID]
+	}
+	return graph.Edge[string]{
+		Source: sourceID,
+		Target: target.ID(),
+		Properties: graph.EdgeProperties{
+			Attributes: map[string]string{DependencyTypeAttribute: string(dependencyType)},
+		},
+	}
+}
+
+func edgeDependencyType(edge graph.Edge[string]) dgraph.DependencyType {
+	if dependencyType, ok := edge.Properties.Attributes[DependencyTypeAttribute]; ok && dependencyType != "" {
+		return dgraph.DependencyType(dependencyType)
+	}
+	return dgraph.AndDependency
+}
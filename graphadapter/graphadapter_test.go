@@ -0,0 +1,67 @@
+package graphadapter_test
+
+import (
+	"testing"
+
+	"github.com/dominikbraun/graph"
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+	"go.arcalot.io/dgraph/graphadapter"
+)
+
+func TestNewStore(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.OrDependency))
+
+	g := graph.NewWithStore(graph.StringHash, graphadapter.NewStore(d), graph.Directed(), graph.PreventCycles())
+	// Changes through the graph library are visible in dgraph.
+	assert.NoError(t, g.AddVertex("c"))
+	assert.NoError(t, g.AddEdge("b", "c"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{"b": dgraph.AndDependency})
+	assert.Equals(t, g.AddEdge("c", "a"), graph.ErrEdgeCreatesCycle)
+
+	// The algorithms of the graph library work on the dgraph graph.
+	order, err := graph.TopologicalSort(g)
+	assert.NoError(t, err)
+	assert.Equals(t, order, []string{"a", "b", "c"})
+	edge, err := g.Edge("a", "b")
+	assert.NoError(t, err)
+	assert.Equals(t, edge.Properties.Attributes[graphadapter.DependencyTypeAttribute], string(dgraph.OrDependency))
+}
+
+func TestFromGraph(t *testing.T) {
+	g := graph.New(graph.StringHash, graph.Directed())
+	assert.NoError(t, g.AddVertex("a"))
+	assert.NoError(t, g.AddVertex("b"))
+	assert.NoError(t, g.AddEdge("a", "b", graph.EdgeAttribute(
+		graphadapter.DependencyTypeAttribute, string(dgraph.CompletionAndDependency),
+	)))
+
+	d, err := graphadapter.FromGraph(g)
+	assert.NoError(t, err)
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.Equals(t, b.Item(), "b")
+	assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.CompletionAndDependency,
+	})
+}
+
+func TestNewStore_Errors(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	store := graphadapter.NewStore(d)
+
+	_, _, err := store.Vertex("missing")
+	assert.Equals(t, err, graph.ErrVertexNotFound)
+	assert.Equals(t, store.AddEdge("missing", "a", graph.Edge[string]{}), graph.ErrVertexNotFound)
+	assert.Equals(t, store.RemoveEdge("a", "missing"), graph.ErrVertexNotFound)
+	assert.Equals(t, store.RemoveEdge("a", "b"), graph.ErrEdgeNotFound)
+	_, err = store.Edge("a", "missing")
+	assert.Equals(t, err, graph.ErrEdgeNotFound)
+	// Errors other than missing nodes are returned unchanged.
+	assert.InstanceOf[*dgraph.ErrCannotConnectToSelf](t, store.AddEdge("a", "a", graph.Edge[string]{}))
+}
@@ -0,0 +1,170 @@
+package dgraph
+
+import "slices"
+
+// GroupDependencyMode determines how a node depends on the members of a group.
+type GroupDependencyMode string
+
+const (
+	// AllMembers makes every member of the group an AndDependency of the node.
+	AllMembers GroupDependencyMode = "all"
+	// AnyMember makes every member of the group an OrDependency of the node. Note that these join the other OR
+	// dependencies of the node, if any.
+	AnyMember GroupDependencyMode = "any"
+)
+
+func (m GroupDependencyMode) dependencyType() DependencyType {
+	if m == AnyMember {
+		return OrDependency
+	}
+	return AndDependency
+}
+
+// Group is a named set of nodes that other nodes can depend on as a whole. Membership changes are reflected in the
+// connections of all nodes that depend on the group. Removing a member disconnects it from the dependents, so
+// Reconcile() should be called afterwards to update their readiness.
+type Group interface {
+	// Name returns the unique name of the group.
+	Name() string
+	// AddMember adds the node with the specified ID to the group and connects it to all dependents of the group.
+	// If the node does not exist, ErrNodeNotFound is returned. If any of the connections can't be made, the error is
+	// returned and neither the group nor the connections are changed.
+	AddMember(nodeID string) error
+	// RemoveMember removes the node with the specified ID from the group and disconnects it from all dependents of
+	// the group. If the node is not a member, ErrGroupMemberNotFound is returned.
+	RemoveMember(nodeID string) error
+	// ListMembers returns the sorted IDs of all members of the group.
+	ListMembers() []string
+	// StatusCounts returns the number of members in each resolution status.
+	StatusCounts() StatusCounts
+	// IsComplete returns true if no member of the group is Waiting.
+	IsComplete() bool
+}
+
+type group[NodeType any] struct {
+	name    string
+	members map[string]struct{}
+	// Map of the dependent node IDs to the mode with which they depend on the group.
+	dependents map[string]GroupDependencyMode
+	dg         *directedGraph[NodeType]
+}
+
+func (d *directedGraph[NodeType]) AddGroup(name string) (Group, error) {
+	d.lock.Lock()
+	defer d.unlock()
+	if _, ok := d.groups[name]; ok {
+		return nil, &ErrGroupAlreadyExists{name}
+	}
+	g := &group[NodeType]{
+		name:       name,
+		members:    map[string]struct{}{},
+		dependents: map[string]GroupDependencyMode{},
+		dg:         d,
+	}
+	d.groups[name] = g
+	d.advanceGeneration()
+	return g, nil
+}
+
+func (d *directedGraph[NodeType]) GetGroup(name string) (Group, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	g, ok := d.groups[name]
+	if !ok {
+		return nil, &ErrGroupNotFound{name}
+	}
+	return g, nil
+}
+
+func (g *group[NodeType]) Name() string {
+	return g.name
+}
+
+func (g *group[NodeType]) AddMember(nodeID string) error {
+	nodeID = g.dg.config.normalizeID(nodeID)
+	g.dg.lock.Lock()
+	defer g.dg.unlock()
+	if _, ok := g.dg.nodes[nodeID]; !ok {
+		return g.dg.nodeNotFound(nodeID)
+	}
+	if _, ok := g.members[nodeID]; ok {
+		return nil
+	}
+	dependentIDs := slices.DeleteFunc(seededKeys(g.dg.randomSource, g.dependents), func(dependentID string) bool {
+		return dependentID == nodeID
+	})
+	// Validate all connections first, so that the graph is left unchanged if any of them is invalid.
+	for _, dependentID := range dependentIDs {
+		if err := g.dg.validateConnection(nodeID, dependentID, g.dependents[dependentID].dependencyType()); err != nil {
+			return err
+		}
+	}
+	for _, dependentID := range dependentIDs {
+		if err := g.dg.connect(nodeID, dependentID, g.dependents[dependentID].dependencyType()); err != nil {
+			return err
+		}
+	}
+	g.members[nodeID] = struct{}{}
+	g.dg.advanceGeneration()
+	return nil
+}
+
+func (g *group[NodeType]) RemoveMember(nodeID string) error {
+	nodeID = g.dg.config.normalizeID(nodeID)
+	g.dg.lock.Lock()
+	defer g.dg.unlock()
+	if _, ok := g.members[nodeID]; !ok {
+		return &ErrGroupMemberNotFound{g.name, nodeID}
+	}
+	for dependentID := range g.dependents {
+		if _, ok := g.dg.connectionsFromNode[nodeID][dependentID]; !ok {
+			continue
+		}
+		g.dg.removeConnection(nodeID, dependentID)
+	}
+	delete(g.members, nodeID)
+	g.dg.advanceGeneration()
+	return nil
+}
+
+func (g *group[NodeType]) ListMembers() []string {
+	g.dg.lock.RLock()
+	defer g.dg.lock.RUnlock()
+	result := make([]string, 0, len(g.members))
+	for memberID := range g.members {
+		result = append(result, memberID)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func (n *node[NodeType]) ConnectGroupDependency(groupName string, mode GroupDependencyMode) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	g, ok := n.dg.groups[groupName]
+	if !ok {
+		return &ErrGroupNotFound{groupName}
+	}
+	if _, ok := g.dependents[n.id]; ok {
+		return &ErrGroupDependencyAlreadyExists{groupName, n.id}
+	}
+	memberIDs := slices.DeleteFunc(seededKeys(n.dg.randomSource, g.members), func(memberID string) bool {
+		return memberID == n.id
+	})
+	// Validate all connections first, so that the graph is left unchanged if any of them is invalid.
+	for _, memberID := range memberIDs {
+		if err := n.dg.validateConnection(memberID, n.id, mode.dependencyType()); err != nil {
+			return err
+		}
+	}
+	for _, memberID := range memberIDs {
+		if err := n.dg.connect(memberID, n.id, mode.dependencyType()); err != nil {
+			return err
+		}
+	}
+	g.dependents[n.id] = mode
+	return nil
+}
@@ -0,0 +1,99 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_GroupDependencies(t *testing.T) {
+	d := dgraph.New[string]()
+	stage, err := d.AddGroup("stage")
+	assert.NoError(t, err)
+	_, err = d.AddGroup("stage")
+	assert.InstanceOf[*dgraph.ErrGroupAlreadyExists](t, err)
+
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	all := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("all", "all"))
+	anyOf := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("any", "any"))
+	assert.NoError(t, stage.AddMember(a.ID()))
+	assert.NoError(t, all.ConnectGroupDependency("stage", dgraph.AllMembers))
+	assert.NoError(t, anyOf.ConnectGroupDependency("stage", dgraph.AnyMember))
+	assert.InstanceOf[*dgraph.ErrGroupNotFound](t, all.ConnectGroupDependency("missing", dgraph.AllMembers))
+
+	// Members added later are connected automatically.
+	assert.NoError(t, stage.AddMember(b.ID()))
+	assert.Equals(t, stage.ListMembers(), []string{"a", "b"})
+	assert.Equals(t, all.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.AndDependency,
+		"b": dgraph.AndDependency,
+	})
+	assert.Equals(t, anyOf.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.OrDependency,
+		"b": dgraph.OrDependency,
+	})
+
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Waiting,
+		"b": dgraph.Waiting,
+	})
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"any": dgraph.Waiting})
+
+	// Removing the outstanding member releases the node depending on all members.
+	assert.NoError(t, stage.RemoveMember(b.ID()))
+	assert.InstanceOf[*dgraph.ErrGroupMemberNotFound](t, stage.RemoveMember(b.ID()))
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"all": dgraph.Waiting})
+}
+
+func TestDirectedGraph_GroupDependencies_Invalid(t *testing.T) {
+	d := dgraph.New[string]()
+	stage := assert.NoErrorR[dgraph.Group](t)(d.AddGroup("stage"))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("x", "x"))
+	y := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("y", "y"))
+	assert.NoError(t, stage.AddMember(a.ID()))
+	assert.NoError(t, stage.AddMember(b.ID()))
+	assert.NoError(t, x.ConnectGroupDependency("stage", dgraph.AllMembers))
+	assert.InstanceOf[*dgraph.ErrGroupDependencyAlreadyExists](t, x.ConnectGroupDependency("stage", dgraph.AnyMember))
+
+	// y already depends on b, so connecting it to the group fails without connecting it to a.
+	assert.NoError(t, y.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.InstanceOf[*dgraph.ErrConnectionAlreadyExists](t, y.ConnectGroupDependency("stage", dgraph.AllMembers))
+	assert.Equals(t, y.OutstandingDependencies(), map[string]dgraph.DependencyType{"b": dgraph.AndDependency})
+
+	// c already has x as a dependent, so adding it to the group fails without connecting it to z.
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	z := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("z", "z"))
+	assert.NoError(t, x.ConnectDependency(c.ID(), dgraph.AndDependency))
+	other := assert.NoErrorR[dgraph.Group](t)(d.AddGroup("other"))
+	assert.NoError(t, z.ConnectGroupDependency("other", dgraph.AllMembers))
+	assert.NoError(t, x.ConnectGroupDependency("other", dgraph.AllMembers))
+	assert.InstanceOf[*dgraph.ErrConnectionAlreadyExists](t, other.AddMember(c.ID()))
+	assert.Equals(t, other.ListMembers(), []string{})
+	assert.Equals(t, z.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+}
+
+func TestGroup_RemoveMember_ForgetsConnection(t *testing.T) {
+	d := dgraph.New[string]()
+	stage := assert.NoErrorR[dgraph.Group](t)(d.AddGroup("stage"))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("x", "x"))
+	assert.NoError(t, stage.AddMember(a.ID()))
+	assert.NoError(t, x.ConnectGroupDependency("stage", dgraph.AllMembers))
+	edge := assert.NoErrorR[dgraph.Edge](t)(d.GetEdge("a", "x"))
+	assert.NoError(t, edge.SetWeight(5))
+	assert.NoError(t, edge.SetMetadata("label", "x"))
+
+	// The connection made when the member is added again doesn't inherit the state of the removed one.
+	assert.NoError(t, stage.RemoveMember(a.ID()))
+	assert.NoError(t, stage.AddMember(a.ID()))
+	edge = assert.NoErrorR[dgraph.Edge](t)(d.GetEdge("a", "x"))
+	assert.Equals(t, assert.NoErrorR[float64](t)(edge.Weight()), 1.0)
+	assert.Equals(t, assert.NoErrorR[map[string]string](t)(edge.Metadata()), map[string]string{})
+}
@@ -0,0 +1,85 @@
+package dgraph
+
+import (
+	"slices"
+	"time"
+)
+
+// Health is a snapshot of the execution progress of a graph, returned by DirectedGraph.Health.
+type Health struct {
+	// InFlight lists the IDs of the nodes that have been popped, but not resolved yet, sorted by ID.
+	InFlight []string
+	// QueueDepth is the number of ready nodes that have not been popped yet.
+	QueueDepth int
+	// HeldBack is the number of ready nodes held back by WithMaxReadyNodes or WithResources.
+	HeldBack int
+	// Waiting is the number of nodes that are not ready yet, because they are waiting for their dependencies.
+	Waiting int
+	// ResourceUtilization is the ratio of the tokens of each resource of WithResources that are in use.
+	ResourceUtilization map[string]float64
+	// LastProgress is the last time the graph was started, or a node was popped or resolved. It is zero if the
+	// graph has not been started.
+	LastProgress time.Time
+	// Generation is the generation of the graph the snapshot was taken at. See DirectedGraph.Generation.
+	Generation uint64
+}
+
+// Done returns true if no node is in flight, ready, or waiting for its dependencies.
+func (h Health) Done() bool {
+	return len(h.InFlight) == 0 && h.QueueDepth == 0 && h.HeldBack == 0 && h.Waiting == 0
+}
+
+func (d *directedGraph[NodeType]) Health() Health {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.health()
+}
+
+func (d *directedGraph[NodeType]) Ping(maxIdle time.Duration) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	health := d.health()
+	if !d.started || health.Done() {
+		return nil
+	}
+	if idle := d.config.clock().Sub(health.LastProgress); idle > maxIdle {
+		return &ErrNoProgress{health.LastProgress, idle}
+	}
+	return nil
+}
+
+// health collects the current Health of the graph.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) health() Health {
+	result := Health{
+		InFlight:            []string{},
+		QueueDepth:          d.readyForProcessing.Len(),
+		HeldBack:            len(d.heldReady),
+		ResourceUtilization: make(map[string]float64, len(d.config.resourceCapacities)),
+		Generation:          d.generation,
+	}
+	if d.started {
+		result.LastProgress = d.startedAt
+	}
+	for nodeID, n := range d.nodes {
+		for _, progress := range []time.Time{n.poppedAt, n.resolvedAt} {
+			if progress.After(result.LastProgress) {
+				result.LastProgress = progress
+			}
+		}
+		switch {
+		case n.status != Waiting || d.isPendingReady(nodeID):
+		case !n.poppedAt.IsZero():
+			result.InFlight = append(result.InFlight, nodeID)
+		case !n.ready:
+			result.Waiting++
+		}
+	}
+	slices.Sort(result.InFlight)
+	for resource, capacity := range d.config.resourceCapacities {
+		if capacity > 0 {
+			result.ResourceUtilization[resource] = float64(d.resourcesInUse[resource]) / float64(capacity)
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package dgraph_test
+
+import (
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Health(t *testing.T) {
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := dgraph.New[string](dgraph.WithClock(clock.Now), dgraph.WithResources(map[string]int{"gpu": 2}))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, b.RequireResources("gpu"))
+	assert.NoError(t, d.Ping(0))
+
+	assert.NoError(t, d.PushStartingNodes())
+	startedAt := clock.Now()
+	clock.Advance(time.Second)
+	assert.Equals(t, d.Health(), dgraph.Health{
+		InFlight:            []string{},
+		QueueDepth:          2,
+		Waiting:             1,
+		ResourceUtilization: map[string]float64{"gpu": 0.5},
+		LastProgress:        startedAt,
+		Generation:          d.Generation(),
+	})
+
+	d.PopReadyNodes()
+	assert.NoError(t, d.Ping(time.Minute))
+	clock.Advance(2 * time.Minute)
+	health := d.Health()
+	assert.Equals(t, health.InFlight, []string{"a", "b"})
+	assert.Equals(t, health.LastProgress, startedAt.Add(time.Second))
+	assert.InstanceOf[*dgraph.ErrNoProgress](t, d.Ping(time.Minute))
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, d.Ping(time.Minute))
+	d.PopReadyNodes()
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, c.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.Health().Done(), true)
+	clock.Advance(time.Hour)
+	assert.NoError(t, d.Ping(time.Minute))
+}
@@ -0,0 +1,176 @@
+package dgraph
+
+// hooks are the listeners registered on a graph.
+type hooks[NodeType any] struct {
+	nodeAdded    []func(node Node[NodeType])
+	nodeReady    []func(node Node[NodeType])
+	nodeResolved []func(node Node[NodeType], status ResolutionStatus)
+	connect      []func(from Node[NodeType], to Node[NodeType], dependencyType DependencyType)
+	resolved     []func(node Node[NodeType])
+	unresolvable []func(node Node[NodeType], cause error)
+	skipped      []func(node Node[NodeType], cause error)
+}
+
+func (d *directedGraph[NodeType]) OnNodeAdded(listener func(node Node[NodeType])) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.nodeAdded = append(d.hooks.nodeAdded, listener)
+}
+
+func (d *directedGraph[NodeType]) OnNodeReady(listener func(node Node[NodeType])) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.nodeReady = append(d.hooks.nodeReady, listener)
+}
+
+func (d *directedGraph[NodeType]) OnNodeResolved(listener func(node Node[NodeType], status ResolutionStatus)) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.nodeResolved = append(d.hooks.nodeResolved, listener)
+}
+
+func (d *directedGraph[NodeType]) OnConnect(
+	listener func(from Node[NodeType], to Node[NodeType], dependencyType DependencyType),
+) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.connect = append(d.hooks.connect, listener)
+}
+
+func (d *directedGraph[NodeType]) OnResolved(listener func(node Node[NodeType])) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.resolved = append(d.hooks.resolved, listener)
+}
+
+func (d *directedGraph[NodeType]) OnUnresolvable(listener func(node Node[NodeType], cause error)) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.unresolvable = append(d.hooks.unresolvable, listener)
+}
+
+func (d *directedGraph[NodeType]) OnSkipped(listener func(node Node[NodeType], cause error)) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hooks.skipped = append(d.hooks.skipped, listener)
+}
+
+// unlock releases the lock, then calls the listeners of the events that were emitted while it was held. Listeners
+// are called without the lock, so they can call back into the graph. The terminal status listeners are called
+// last, with the causes as they are after the propagation of all resolutions.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) unlock() {
+	for _, n := range d.pendingTerminal {
+		d.emitTerminalStatus(n)
+	}
+	d.pendingTerminal = nil
+	events := d.pendingEvents
+	d.pendingEvents = nil
+	d.lock.Unlock()
+	d.dispatch(events)
+}
+
+// dispatch queues the listener calls and makes them in order, unless another call of dispatch is already making
+// them. Events emitted by a listener that calls back into the graph are therefore queued behind the remaining
+// events instead of being dispatched recursively, so listeners see the events in the order they happened.
+// Caller must not hold the graph lock.
+func (d *directedGraph[NodeType]) dispatch(events []func()) {
+	if len(events) == 0 {
+		return
+	}
+	d.dispatchLock.Lock()
+	d.dispatchQueue = append(d.dispatchQueue, events...)
+	if d.dispatching {
+		d.dispatchLock.Unlock()
+		return
+	}
+	d.dispatching = true
+	d.dispatchLock.Unlock()
+	completed := false
+	defer func() {
+		if !completed {
+			// A listener panicked. The remaining events are left for the next dispatch.
+			d.dispatchLock.Lock()
+			d.dispatching = false
+			d.dispatchLock.Unlock()
+		}
+	}()
+	for {
+		d.dispatchLock.Lock()
+		if len(d.dispatchQueue) == 0 {
+			d.dispatchQueue = nil
+			d.dispatching = false
+			d.dispatchLock.Unlock()
+			completed = true
+			return
+		}
+		event := d.dispatchQueue[0]
+		d.dispatchQueue = d.dispatchQueue[1:]
+		d.dispatchLock.Unlock()
+		event()
+	}
+}
+
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) emitNodeAdded(n *node[NodeType]) {
+	for _, listener := range d.hooks.nodeAdded {
+		d.pendingEvents = append(d.pendingEvents, func() { listener(n) })
+	}
+}
+
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) emitNodeReady(n *node[NodeType]) {
+	for _, listener := range d.hooks.nodeReady {
+		d.pendingEvents = append(d.pendingEvents, func() { listener(n) })
+	}
+}
+
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) emitNodeResolved(n *node[NodeType], status ResolutionStatus) {
+	for _, listener := range d.hooks.nodeResolved {
+		d.pendingEvents = append(d.pendingEvents, func() { listener(n, status) })
+	}
+	if len(d.hooks.resolved) > 0 || len(d.hooks.unresolvable) > 0 || len(d.hooks.skipped) > 0 {
+		d.pendingTerminal = append(d.pendingTerminal, n)
+	}
+}
+
+// emitTerminalStatus queues the calls of the listeners for the final status of the node. Unresolvable nodes whose
+// dependencies prevented them from running are reported as skipped.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) emitTerminalStatus(n *node[NodeType]) {
+	cause := n.unresolvableCause
+	switch {
+	case n.status == Resolved:
+		for _, listener := range d.hooks.resolved {
+			d.pendingEvents = append(d.pendingEvents, func() { listener(n) })
+		}
+	case n.status == Cancelled:
+		// Cancellations are only reported to the OnNodeResolved listeners.
+	case isSkipCause(cause):
+		for _, listener := range d.hooks.skipped {
+			d.pendingEvents = append(d.pendingEvents, func() { listener(n, cause) })
+		}
+	default:
+		for _, listener := range d.hooks.unresolvable {
+			d.pendingEvents = append(d.pendingEvents, func() { listener(n, cause) })
+		}
+	}
+}
+
+// isSkipCause returns true if the cause means that the node was made unresolvable by its dependencies.
+func isSkipCause(cause error) bool {
+	switch cause.(type) {
+	case *ErrDependencyUnresolvable, *ErrDependencyConditionNotMet, *ErrExpressionNotSatisfied, *ErrNodeObviated:
+		return true
+	default:
+		return false
+	}
+}
+
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) emitConnect(from, to *node[NodeType], dependencyType DependencyType) {
+	for _, listener := range d.hooks.connect {
+		d.pendingEvents = append(d.pendingEvents, func() { listener(from, to, dependencyType) })
+	}
+}
@@ -0,0 +1,108 @@
+package dgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Hooks(t *testing.T) {
+	d := dgraph.New[string]()
+	var events []string
+	d.OnNodeAdded(func(node dgraph.Node[string]) {
+		events = append(events, "added "+node.ID())
+	})
+	d.OnConnect(func(from dgraph.Node[string], to dgraph.Node[string], dependencyType dgraph.DependencyType) {
+		events = append(events, fmt.Sprintf("connect %s->%s %s", from.ID(), to.ID(), dependencyType))
+	})
+	d.OnNodeReady(func(node dgraph.Node[string]) {
+		// Listeners are called without the lock, so they can use the graph.
+		events = append(events, fmt.Sprintf("ready %s %s", node.ID(), node.ResolutionStatus()))
+	})
+	d.OnNodeResolved(func(node dgraph.Node[string], status dgraph.ResolutionStatus) {
+		events = append(events, fmt.Sprintf("resolved %s %s", node.ID(), status))
+	})
+
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+
+	assert.Equals(t, events, []string{
+		"added a",
+		"added b",
+		"added c",
+		"connect a->b and",
+		"connect b->c and",
+		"ready a waiting",
+		"resolved a unresolvable",
+		"ready b unresolvable",
+		"resolved b unresolvable",
+		"ready c unresolvable",
+		"resolved c unresolvable",
+	})
+}
+
+func TestDirectedGraph_TerminalStatusHooks(t *testing.T) {
+	d := dgraph.New[string]()
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"a", "b", "c"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["c"].ConnectDependency("b", dgraph.AndDependency))
+	var events []string
+	d.OnResolved(func(node dgraph.Node[string]) {
+		events = append(events, "resolved "+node.ID())
+	})
+	d.OnUnresolvable(func(node dgraph.Node[string], cause error) {
+		events = append(events, fmt.Sprintf("unresolvable %s (%v)", node.ID(), cause))
+	})
+	d.OnSkipped(func(node dgraph.Node[string], cause error) {
+		events = append(events, fmt.Sprintf("skipped %s (%v)", node.ID(), cause))
+	})
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, nodes["a"].ResolveNode(dgraph.Resolved))
+	assert.NoError(t, nodes["b"].ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, events, []string{
+		"resolved a",
+		"unresolvable b (<nil>)",
+		`skipped c (node "c" is unresolvable because its and dependency "b" is unresolvable)`,
+	})
+}
+
+func TestDirectedGraph_ReentrantHooks(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	d.OnNodeReady(func(node dgraph.Node[string]) {
+		// Resolving the node calls back into the graph from a listener.
+		assert.NoError(t, node.ResolveNode(dgraph.Resolved))
+	})
+	var events []string
+	d.OnNodeReady(func(node dgraph.Node[string]) {
+		events = append(events, "ready "+node.ID())
+	})
+	d.OnNodeResolved(func(node dgraph.Node[string], status dgraph.ResolutionStatus) {
+		events = append(events, fmt.Sprintf("resolved %s %s", node.ID(), status))
+	})
+	assert.NoError(t, d.PushStartingNodes())
+
+	// The events caused by the first listener are delivered after all listeners of the earlier events.
+	assert.Equals(t, events, []string{
+		"ready a",
+		"resolved a resolved",
+		"ready b",
+		"resolved b resolved",
+		"ready c",
+		"resolved c resolved",
+	})
+	assert.Equals(t, c.ResolutionStatus(), dgraph.Resolved)
+}
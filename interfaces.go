@@ -1,5 +1,10 @@
 package dgraph
 
+import (
+	"context"
+	"time"
+)
+
 type DependencyType string
 
 const (
@@ -10,6 +15,13 @@ const (
 	AndDependency DependencyType = "and"
 	// CompletionAndDependency means the dependency will resolve due to either resolution or failure.
 	CompletionAndDependency DependencyType = "completion-and"
+	// OnUnresolvableDependency means the dependency is required to be Unresolvable. The node only becomes ready
+	// if the dependency is resolved as Unresolvable, and becomes unresolvable itself if the dependency is
+	// Resolved. This is useful for error handlers and fallbacks.
+	OnUnresolvableDependency DependencyType = "on-unresolvable"
+	// ExpressionDependency is the type of the dependencies of the expression of a node. The expression decides
+	// whether their resolutions satisfy the node. See Node.ConnectExpression.
+	ExpressionDependency DependencyType = "expression"
 	// OptionalDependency means the resolution of the dependency is tracked, but it has no effect
 	// on the ready or failure state of a node.
 	OptionalDependency DependencyType = "optional"
@@ -29,24 +41,266 @@ const (
 	Waiting      ResolutionStatus = "waiting"
 	Resolved     ResolutionStatus = "resolved"
 	Unresolvable ResolutionStatus = "unresolvable"
+	// Cancelled is the status of the nodes cancelled by DirectedGraph.Cancel or DirectedGraph.CancelAll. Unlike
+	// Unresolvable, it is not a failure, and it is not propagated to the dependents of the node.
+	Cancelled ResolutionStatus = "cancelled"
 )
 
+// ResolvedDependency describes a single dependency of a node that has been resolved, in the order the resolutions
+// were received by the node.
+type ResolvedDependency struct {
+	// NodeID is the ID of the dependency node.
+	NodeID string
+	// DependencyType is the type of the dependency at the time it was resolved. An OR dependency that was resolved
+	// after another OR dependency satisfied the group is reported as ObviatedDependency.
+	DependencyType DependencyType
+	// Order is the zero-based position of this resolution among all resolved dependencies of the node.
+	Order int
+	// ResolvedAt is the time at which the node was notified of the resolution.
+	ResolvedAt time.Time
+}
+
+// DependencyEffect describes the effect that the resolution of a dependency had on the dependent node.
+type DependencyEffect string
+
+const (
+	// DependencySatisfied means the resolution counted towards the readiness of the node.
+	DependencySatisfied DependencyEffect = "satisfied"
+	// DependencyObviated means the resolution had no effect because the dependency was already obviated.
+	DependencyObviated DependencyEffect = "obviated"
+	// DependencyIgnored means the resolution was tracked, but had no effect on the node. This is the case for
+	// optional dependencies, and for unresolvable OR dependencies while other OR dependencies are outstanding.
+	DependencyIgnored DependencyEffect = "ignored"
+	// DependencyFailed means the resolution made the node unresolvable.
+	DependencyFailed DependencyEffect = "failed"
+)
+
+// DependencyState is the state of a single dependency in a DependencyReport.
+type DependencyState struct {
+	// DependencyType is the type of the dependency. For resolved dependencies, it is the same type that
+	// ResolvedDependencies reports.
+	DependencyType DependencyType
+	// Status is the resolution status of the dependency node.
+	Status ResolutionStatus
+}
+
+// DependencyReport is a consistent snapshot of the dependencies of a node. Each map is keyed by the dependency node
+// ID, and every dependency appears in exactly one of the maps.
+type DependencyReport struct {
+	// Resolved contains the dependencies that have been resolved and had an effect on the node.
+	Resolved map[string]DependencyState
+	// Outstanding contains the dependencies that have not been resolved yet and can still affect the node.
+	Outstanding map[string]DependencyState
+	// Obviated contains the dependencies that no longer have an effect on the node, whether they have been
+	// resolved or not.
+	Obviated map[string]DependencyState
+}
+
+// DependencyEvent is a single entry in the satisfaction trace of a node.
+type DependencyEvent struct {
+	// DependencyID is the ID of the dependency node that was resolved.
+	DependencyID string
+	// DependencyType is the type of the dependency at the time it was resolved.
+	DependencyType DependencyType
+	// Status is the resolution status of the dependency node.
+	Status ResolutionStatus
+	// Effect is the effect the resolution had on the dependent node.
+	Effect DependencyEffect
+}
+
 // DirectedGraph is the representation of a Directed Graph width nodes and directed connections.
 type DirectedGraph[NodeType any] interface {
 	// AddNode adds a node with the specified ID. If the node already exists, it returns an ErrNodeAlreadyExists.
 	AddNode(id string, item NodeType) (Node[NodeType], error)
+	// AddNodes adds all nodes of the map, keyed by ID, while taking the lock only once. The nodes are added in ID
+	// order. If any node can't be added, for example because it already exists, the error is returned and none of
+	// the nodes are added.
+	AddNodes(items map[string]NodeType) error
+	// ConnectDependencies makes the node with the specified ID depend on all nodes of the map with the mapped
+	// dependency types, while taking the lock only once. It works like calling Node.ConnectDependency for each
+	// of them in ID order, but if any connection is invalid, the error is returned and no connection is made.
+	ConnectDependencies(toID string, dependencies map[string]DependencyType) error
+	// AddFailureHandlers adds a companion node for each node of the map, keyed by the ID of the primary node,
+	// which becomes ready exactly when its primary is resolved as Unresolvable, either because it failed or
+	// because one of its own dependencies failed. If the primary is Resolved, its handler becomes Unresolvable
+	// instead, which propagates to the dependents of the handler like any other failure. Handlers added to a
+	// running graph take the current status of their primary into account immediately. If any handler can't be
+	// added, the error is returned and none of them are added. The handler nodes are returned keyed by the IDs
+	// of their primaries.
+	AddFailureHandlers(handlers map[string]FailureHandler[NodeType]) (map[string]Node[NodeType], error)
 	// GetNodeByID returns a node with the specified ID. If the specified node does not exist, an ErrNodeNotFound is
 	// returned.
 	GetNodeByID(id string) (Node[NodeType], error)
+	// AddGroup adds a named group of nodes that other nodes can depend on as a whole. If the group already exists,
+	// an ErrGroupAlreadyExists is returned.
+	AddGroup(name string) (Group, error)
+	// GetGroup returns the group with the specified name. If the group does not exist, an ErrGroupNotFound is
+	// returned.
+	GetGroup(name string) (Group, error)
 	// ListNodes lists all nodes in the graph.
 	ListNodes() map[string]Node[NodeType]
+	// Nodes returns an iterator over all nodes in the graph and their IDs, in no particular order, which can be
+	// used with a range-over-func loop. It has the same type as iter.Seq2[string, Node[NodeType]]. The nodes are
+	// captured when Nodes is called, but no map is built, and the lock is not held while iterating.
+	Nodes() func(yield func(string, Node[NodeType]) bool)
 	// ListNodesWithoutInboundConnections lists all nodes that do not have an inbound connection. This is useful for
 	// performing a topological sort.
 	ListNodesWithoutInboundConnections() map[string]Node[NodeType]
+	// Ancestors returns all nodes the specified node depends on directly or transitively, regardless of the
+	// dependency type. The node itself is only included if it is part of a cycle. If the node does not exist, an
+	// ErrNodeNotFound is returned.
+	Ancestors(nodeID string) (map[string]Node[NodeType], error)
+	// Descendants returns all nodes that depend on the specified node directly or transitively, which are the
+	// nodes affected if the node fails. The node itself is only included if it is part of a cycle. If the node
+	// does not exist, an ErrNodeNotFound is returned.
+	Descendants(nodeID string) (map[string]Node[NodeType], error)
+	// WalkBFS calls visit for the start node and every node reachable from it over outbound connections, breadth
+	// first. Dependents of a node are visited in the order of their IDs. If visit returns an error, the walk ends
+	// and the error is returned, except for ErrStopWalk, which ends the walk without an error. The lock is not held
+	// while visit is called, so it can use the graph; nodes added during the walk may or may not be visited. If
+	// the start node does not exist, an ErrNodeNotFound is returned.
+	WalkBFS(start string, visit func(node Node[NodeType]) error) error
+	// WalkDFS works like WalkBFS, but visits the nodes depth first, in preorder.
+	WalkDFS(start string, visit func(node Node[NodeType]) error) error
+	// StatusCounts returns the number of nodes in each resolution status, counting only nodes whose ID starts
+	// with the prefix. This can be used to track an embedded part of a workflow, for example with the prefix
+	// "steps.example.". An empty prefix counts all nodes.
+	StatusCounts(prefix string) StatusCounts
+	// NodeStates returns the resolution status and readiness of every node, captured together, so that progress
+	// views get consistent counts instead of querying the nodes one by one while the graph changes.
+	NodeStates() map[string]NodeState
+	// IsComplete returns true if no node whose ID starts with the prefix is Waiting. Group.IsComplete provides the
+	// same for the members of a group.
+	IsComplete(prefix string) bool
+	// GetEdge returns a handle to the connection between the specified nodes. If either node does not exist, an
+	// ErrNodeNotFound is returned, and if the connection does not exist, an ErrConnectionDoesNotExist.
+	GetEdge(fromNodeID, toNodeID string) (Edge, error)
+	// ListConnections lists all connections of the graph with their dependency types and states. The connections
+	// are sorted by source and destination node ID, or listed in insertion order if the graph was created with
+	// WithConnectionOrder.
+	ListConnections() []Connection
+	// Connections returns an iterator over the same connections as ListConnections, which can be used with a
+	// range-over-func loop. The connections are captured when Connections is called.
+	Connections() func(yield func(Connection) bool)
+	// ListEdges returns a handle to every connection of the graph, in the same order as ListConnections.
+	ListEdges() []Edge
+	// Edges returns an iterator over the same edges as ListEdges, which can be used with a range-over-func loop. It
+	// has the same type as iter.Seq[Edge].
+	Edges() func(yield func(Edge) bool)
+	// AdjacencyMatrix returns the adjacency matrix of the graph together with the node IDs, sorted by ID, that
+	// index its rows and columns. The value at [i][j] is true if there is a connection from node i to node j,
+	// regardless of its dependency type.
+	AdjacencyMatrix() ([][]bool, []string)
+	// EdgeSet returns an immutable snapshot of the connections and their dependency types, which can be queried
+	// without taking the lock of the graph. The same snapshot is returned until the graph changes.
+	EdgeSet() *EdgeSet
+	// UndirectedView returns a live, read-only view of the graph that ignores the direction of connections.
+	UndirectedView() UndirectedView[NodeType]
+	// Generation returns a counter that is incremented on every change of the state of the graph, such as added
+	// nodes and connections, resolutions, and changes of the ready set. Caches and UIs can compare generations to
+	// cheaply detect that a snapshot is stale. Health and ExportJSON include the generation they were taken at.
+	Generation() uint64
+	// Namespace returns a live view of the nodes whose IDs start with the prefix, in which IDs are relative to the
+	// prefix, so that code working on a sub-workflow doesn't need to know where it is embedded in the graph. The
+	// view only contains its nodes and the connections between them, but dependencies on nodes outside the view
+	// still affect readiness. Nodes added through the view get the prefix. Functions that affect the whole graph,
+	// such as SetGate and Drain, act on the graph. RestoreState returns an ErrNotSupportedByNamespace.
+	Namespace(prefix string) DirectedGraph[NodeType]
+	// NotifyDataReady re-evaluates the data ready check of WithDataReadyCheck for the connection between the two
+	// nodes. If the source node is Resolved and the check now passes, the dependency is resolved for the
+	// destination node. If the connection does not exist, an ErrConnectionDoesNotExist is returned.
+	NotifyDataReady(fromNodeID, toNodeID string) error
+	// Diff returns the nodes and connections added or removed in the other graph compared to this graph, and the
+	// connections whose dependency type differs. Since resolving a graph changes dependency types, for example
+	// when OR dependencies are obviated, Diff is meant for graphs that have not been started.
+	Diff(other DirectedGraph[NodeType]) GraphDiff
+	// Merge adds the nodes and connections of the other graph to this graph, keeping the current dependency types,
+	// metadata, and weights of the connections. Resolution states are not merged. Nodes with IDs that already exist
+	// are handled according to WithMergeConflictPolicy, and connections that already exist are kept as they are.
+	// All conflicts are collected before the graph is changed and returned together as an ErrMergeConflicts.
+	Merge(other DirectedGraph[NodeType], options ...MergeOption) error
+	// Degrees returns the inbound and outbound connection counts of all nodes, keyed by node ID.
+	Degrees() map[string]Degree
+	// ValidateBoundary checks that a graph embedded with the given ID prefix, for example by Merge, is wired into
+	// this graph as the contract declares. Every input must have an inbound connection from a node outside the
+	// prefix, and every output must be connected to its parent node. All violations are returned together as an
+	// ErrBoundaryViolations, so composition mistakes surface before the graph stalls at runtime.
+	ValidateBoundary(prefix string, contract BoundaryContract) error
+	// Reset removes all nodes, connections, groups, listeners, and the deadline, and returns the graph to the state
+	// it had after New, while keeping the options and the allocated capacity for rebuilding it. Nodes obtained
+	// before the reset act as removed nodes. See Pool for reusing graphs across goroutines.
+	Reset()
+	// ResetExecution returns the graph to the state it had before PushStartingNodes, so that it can be executed
+	// again without cloning it. All nodes become Waiting and not ready, their dependencies become outstanding
+	// again with the types they were connected with, and the ready set, resources, statistics, and deadline are
+	// cleared. Nodes, items, connections, groups, and listeners are kept.
+	ResetExecution()
+	// SaveState serializes the execution state of the graph: the resolution statuses, the outstanding, resolved,
+	// and failed dependencies, the nodes being processed, and the ready set. Unlike ExportJSON, items and
+	// connections are not included, so the state can be restored onto a graph rebuilt from the same definition.
+	SaveState() ([]byte, error)
+	// RestoreState applies the output of SaveState to a graph with the same nodes and connections, so that a
+	// partially executed workflow can be resumed after a restart. Nodes that were ready are put back into the ready
+	// set without calling listeners, and nodes that were being processed are treated as popped. Timings,
+	// resolution histories, results, and pending stream data are not persisted. If the nodes or connections
+	// differ, an ErrTopologyMismatch is returned and the graph is not changed.
+	RestoreState(data []byte) error
 	// Clone creates an independent copy of the current directed graph.
 	Clone() DirectedGraph[NodeType]
+	// CloneChecked creates an independent copy of the current directed graph like Clone, then verifies that the
+	// copy shares no mutable state with the original and has an identical topology. If the verification fails, an
+	// ErrCloneVerificationFailed is returned.
+	CloneChecked() (DirectedGraph[NodeType], error)
+	// ExportJSON serializes the nodes, connections, dependency types, and resolution statuses of the graph, so that
+	// it can be reconstructed with ImportJSON. Items are marshalled with encoding/json, which uses json.Marshaler
+	// if the node type implements it.
+	ExportJSON() ([]byte, error)
+	// ExportJSONFiltered works like ExportJSON, but only exports the nodes and connections selected by the filter,
+	// like MermaidFiltered and DOTFiltered.
+	ExportJSONFiltered(filter ExportFilter) ([]byte, error)
+	// Compile creates an immutable ExecutionPlan from the current topology and the declared dependency types of the
+	// graph, which can be executed many times using ExecutionPlan.NewRun() without cloning the graph. Dependencies
+	// obviated while the graph was executed keep their declared type, so a started graph compiles to the same plan
+	// as before it was started. If the graph has cycles, an ErrGraphHasCycles is returned.
+	Compile() (*ExecutionPlan[NodeType], error)
+	// TopologicalSort returns all nodes in dependency order, so that every node comes after all nodes it depends
+	// on. Nodes that are not ordered relative to each other are sorted by ID. If the graph has cycles, an
+	// ErrGraphHasCycles containing one of the cycles is returned.
+	TopologicalSort() ([]Node[NodeType], error)
 	// HasCycles performs cycle detection and returns true if the DirectedGraph has cycles.
 	HasCycles() bool
+	// FindCycles returns the groups of nodes that participate in cycles. Each group is a strongly connected
+	// component of the graph, so every node in a group can reach every other node in the same group. The IDs in
+	// each group are sorted, and the groups are sorted by their first ID. If the graph has no cycles, nil is
+	// returned.
+	FindCycles() [][]string
+	// Condense collapses each strongly connected component into a single node, which results in a graph without
+	// cycles. Each node of the returned graph is identified by the lowest node ID of its component and has the
+	// sorted IDs of all nodes in the component as its item. Components are connected with AndDependency if any
+	// of their nodes are connected. The second return value maps each node ID of this graph to the ID of its
+	// component.
+	Condense() (DirectedGraph[[]string], map[string]string)
+	// SuggestCycleBreaks returns a set of connections whose removal would break all cycles in the graph, or nil
+	// if the graph has no cycles. The set is minimal in the sense that removing any connection from it leaves a
+	// cycle, but it is computed heuristically and is not guaranteed to be the smallest possible set. Connections
+	// that are not a CompletionAndDependency also suggest converting them to one as an alternative to removing them.
+	SuggestCycleBreaks() []CycleBreak
+	// ShortestPath returns the path from the first node to the second one with the lowest total connection
+	// weight, including both nodes, along with that weight. Connection weights are set with Edge.SetWeight. If
+	// there is no path, an ErrNoPath is returned, and if the graph has cycles, an ErrGraphHasCycles.
+	ShortestPath(fromNodeID, toNodeID string) ([]Node[NodeType], float64, error)
+	// LongestPath works like ShortestPath, but returns the path with the highest total connection weight. With
+	// estimated durations as weights, this is the chain of nodes that determines the total duration.
+	LongestPath(fromNodeID, toNodeID string) ([]Node[NodeType], float64, error)
+	// CriticalPath returns the longest chain of dependencies in the graph in dependency order, which is the chain
+	// of nodes that gates the total execution time. Each node costs the weight returned by the function passed to
+	// WithCriticalPathPriority. Without a weight function, each node costs its expected duration in seconds, or 1
+	// if it has none. Chains of equal cost are broken by node ID. If the graph has cycles, nil is returned.
+	CriticalPath() []Node[NodeType]
+	// EstimateMakespan returns the time needed to process the whole graph with unlimited parallelism, based on the
+	// expected durations of the nodes set with Node.SetExpectedDuration. Nodes without an expected duration take no
+	// time. If the graph has cycles, an ErrGraphHasCycles is returned.
+	EstimateMakespan() (time.Duration, error)
 	// PopReadyNodes returns of a list of all nodes that have no outstanding required dependencies,
 	// and are therefore ready, and clears the list. Statuses may be stale after return.
 	// A node becomes ready when all of its AND dependencies and at least one of
@@ -54,14 +308,166 @@ type DirectedGraph[NodeType any] interface {
 	// Note that the resolution state of a node is independent of its readiness and that the
 	// status varies depending on the behavior of the calling code.
 	PopReadyNodes() map[string]ResolutionStatus
+	// PopReadyNodesWithReasons works like PopReadyNodes, but also returns why each node became ready, captured at
+	// the same time as the status.
+	PopReadyNodesWithReasons() map[string]ReadyNodeInfo
+	// PopReadyNodesOrdered works like PopReadyNodes, but returns the IDs of the ready nodes in scheduling order.
+	// With WithCriticalPathPriority, nodes with the longest downstream chain come first. Otherwise, or for equal
+	// priorities, the nodes are ordered by ID.
+	PopReadyNodesOrdered() []string
+	// PopReadyNodeObjects works like PopReadyNodesOrdered, but returns the ready nodes themselves along with their
+	// statuses, ready reasons, and resolved dependencies captured at pop time, so that callers don't need to look
+	// up each node after popping it, when it may already have changed.
+	PopReadyNodeObjects() []ReadyNode[NodeType]
+	// PopNReadyNodes works like PopReadyNodes, but pops at most the specified number of ready nodes and leaves the
+	// rest in the ready set, so that executors with a limited number of workers don't need to buffer them. The
+	// nodes are picked in the order PopReadyNodesOrdered returns them. If the count is 0 or less, no node is
+	// popped.
+	PopNReadyNodes(count int) map[string]ResolutionStatus
+	// PopReadyNodesWhere works like PopReadyNodes, but only pops the ready nodes for which the predicate returns
+	// true and leaves the others in the ready set, so that workers handling different kinds of nodes can share a
+	// graph. The predicate is called without the graph lock held.
+	PopReadyNodesWhere(predicate func(Node[NodeType]) bool) map[string]ResolutionStatus
+	// PopReadyNodesInGroup works like PopReadyNodesWhere, but pops the ready nodes that are members of the
+	// specified group. If the group does not exist, an ErrGroupNotFound is returned.
+	PopReadyNodesInGroup(name string) (map[string]ResolutionStatus, error)
+	// ResourcesInUse returns the number of tokens of each resource held by ready nodes that have not been resolved
+	// yet. See WithResources.
+	ResourcesInUse() map[string]int
 	// HasReadyNodes checks to see if there are any ready nodes without clearing them.
 	HasReadyNodes() bool
+	// SetGate opens or closes the readiness gate of the graph. While the gate is closed, nodes still become ready
+	// as their dependencies resolve, but HasReadyNodes() and PopReadyNodes() don't report any of them. Opening the
+	// gate releases all nodes that became ready in the meantime at once. The gate is open by default.
+	SetGate(open bool)
+	// IsGateOpen returns true if the readiness gate is open.
+	IsGateOpen() bool
+	// Drain closes the readiness gate, so that no new ready nodes are reported, and blocks until all nodes that
+	// have been popped are resolved, which allows an engine to shut down gracefully. Nodes that are still Waiting
+	// are left untouched, so execution can be resumed by opening the gate again, or persisted with SaveState. If
+	// the context is done first, its error is returned and the gate stays closed.
+	Drain(ctx context.Context) error
+	// Cancel sets the status of the node with the specified ID and of all nodes that depend on it directly or
+	// transitively to Cancelled, and adds them to the ready set with the ReadyCancelled reason, so that the
+	// executor can clean up after them, including the nodes it is already processing. Nodes that are no longer
+	// Waiting are left unchanged. Cancellations are added to the resolution order and reported to the
+	// OnNodeResolved listeners. Cancelled nodes can't be resolved afterwards, no longer wait for their
+	// dependencies, and nodes connected to them later keep waiting for them. If the node does not exist, an
+	// ErrNodeNotFound is returned.
+	Cancel(nodeID string) error
+	// CancelAll works like Cancel for all nodes of the graph that are still Waiting.
+	CancelAll()
+	// Subscribe returns a channel that delivers nodes as they become ready, as an alternative to polling
+	// PopReadyNodes. Each delivered node is removed from the ready set, so when there are several subscribers or
+	// pollers, every ready node is delivered to only one of them. Nodes are delivered in the same order as
+	// PopReadyNodesOrdered returns them, and the readiness gate is respected. The channel is closed when the
+	// context is cancelled or the deadline of the graph is exceeded.
+	Subscribe(ctx context.Context) <-chan Node[NodeType]
+	// OnNodeAdded registers a listener that is called after a node is added to the graph.
+	//
+	// All listeners are called in the order of their events once the graph lock is released, so they may call
+	// back into the graph, for example to resolve the node they are notified of. Listener calls are queued and
+	// made one at a time: the events caused by a listener are delivered after the listener returns, not
+	// recursively, and if listeners are already being called by another goroutine, the events are delivered by
+	// that goroutine, after the function that caused them has returned. Listeners are not copied by Clone.
+	OnNodeAdded(listener func(node Node[NodeType]))
+	// OnNodeReady registers a listener that is called after a node becomes ready, including nodes that are held
+	// back by WithMaxReadyNodes or not reported while the readiness gate is closed.
+	OnNodeReady(listener func(node Node[NodeType]))
+	// OnNodeResolved registers a listener that is called after the resolution status of a node is set to Resolved,
+	// Unresolvable, or Cancelled, including nodes that become unresolvable because of their dependencies.
+	OnNodeResolved(listener func(node Node[NodeType], status ResolutionStatus))
+	// OnConnect registers a listener that is called after a connection is added between two nodes.
+	OnConnect(listener func(from Node[NodeType], to Node[NodeType], dependencyType DependencyType))
+	// OnResolved registers a listener called for every node resolved as Resolved. The terminal status listeners
+	// are called after all other listeners of the same lock release, once the resolution has been propagated.
+	OnResolved(listener func(node Node[NodeType]))
+	// OnUnresolvable registers a listener called for every node that becomes Unresolvable, except for the nodes
+	// reported by OnSkipped. The cause is the UnresolvableCause of the node, which is nil if the caller resolved it.
+	OnUnresolvable(listener func(node Node[NodeType], cause error))
+	// OnSkipped registers a listener called for every node that becomes Unresolvable because of its dependencies,
+	// with an ErrDependencyUnresolvable or ErrNodeObviated cause. This is useful for cleaning up after nodes
+	// that never ran.
+	OnSkipped(listener func(node Node[NodeType], cause error))
+	// MarkOutput designates the node with the specified ID as an output of the graph. If the node does not exist, an
+	// ErrNodeNotFound is returned.
+	MarkOutput(nodeID string) error
+	// Outputs returns the current resolution status and result of every output node.
+	Outputs() map[string]OutputResult
+	// WaitOutputs waits until all output nodes are resolved and returns the same results as Outputs. If the context
+	// is cancelled first, its error is returned.
+	WaitOutputs(ctx context.Context) (map[string]OutputResult, error)
+	// SetDeadline sets the time, according to the clock of the graph, after which all nodes that are still Waiting
+	// are resolved as Unresolvable with an ErrDeadlineExceeded cause, and removed from the ready set. The deadline
+	// is checked by a timer and on every ready node and resolution call, so resolutions that race with the deadline
+	// see a consistent state. Setting a new deadline replaces the previous one, and a zero time clears it.
+	// Once the deadline has been exceeded, further calls have no effect.
+	SetDeadline(deadline time.Time)
+	// Done returns a channel that is closed once the deadline set by SetDeadline is exceeded. Reset and
+	// ResetExecution start a new run with a new channel after an exceeded deadline, so the channel should be
+	// requested again after them.
+	Done() <-chan struct{}
 	// PushStartingNodes initializes the list which is retrieved using `PopReadyNodes()`.
 	// Recommended to be called only once following construction of the DAG.
 	PushStartingNodes() error
+	// ListStale returns the nodes that have been ready without being popped, or that have been blocked on their
+	// dependencies since the graph was started, for longer than the threshold. The longest stuck nodes come first.
+	ListStale(threshold time.Duration) []StaleNode
+	// DurationStatistics aggregates the queue times (see NodeTimings.QueueTime) of all nodes, grouped by the tag
+	// returned by the tag function. If the tag function is nil, all nodes are aggregated under the empty tag.
+	DurationStatistics(tag func(node Node[NodeType]) string) map[string]DurationStatistics
+	// Stats returns the number of dependencies of each dependency type that were satisfied, obviated, failed, or
+	// ignored during the run, which helps to detect suspicious plans, such as one where almost everything is
+	// obviated.
+	Stats() Stats
+	// Health returns the in-flight nodes, the depth of the ready queue, the resource utilization, and the time of
+	// the last progress, which services can expose as readiness and liveness probes.
+	Health() Health
+	// Ping returns an ErrNoProgress if the graph has been started and has unfinished nodes, but no node has been
+	// popped or resolved for longer than maxIdle. Otherwise, it returns nil.
+	Ping(maxIdle time.Duration) error
+	// ResolutionOrder returns the IDs of all nodes that have been resolved, in the order in which they were
+	// resolved. Nodes that became unresolvable due to a failed dependency are included at the time they were
+	// marked. Removed nodes remain in the list.
+	ResolutionOrder() []string
+	// Partition creates a new graph with the specified nodes and the connections between them, for example to
+	// execute a large graph across several processes. Every dependency on a node outside the partition is kept
+	// as a connection from an external stub node with the same ID, which is never ready, and is resolved with
+	// ResolveExternal once the other process resolves the node. Connections to nodes outside the partition are
+	// dropped. Resolution states are not copied, so partitions should be created before the graph is started.
+	// Connections keep the dependency types they were declared with, even if they were obviated since. The new
+	// graph has the same options as this graph.
+	Partition(nodeIDs []string) (DirectedGraph[NodeType], error)
+	// ResolveExternal resolves an external stub node created by Partition with the resolution reported by another
+	// process. If the node is not external, an ErrNodeNotExternal is returned.
+	ResolveExternal(nodeID string, status ResolutionStatus) error
+	// ApplyResolutions replays the resolutions of a previous run in order, for example to recover after a crash.
+	// The graph is started first if needed. Each record is validated against the state left by the previous
+	// records: the node must exist and not be resolved yet, and it must be ready to be resolved as Resolved. Nodes
+	// that are already unresolvable due to a failed dependency accept an Unresolvable record. Afterwards, the ready
+	// set contains only the nodes that are ready, but not resolved. If a record is invalid, an
+	// ErrInvalidResolutionRecord is returned, and the graph is left unchanged.
+	ApplyResolutions(history []ResolutionRecord) error
+	// Reconcile recomputes the outstanding dependencies and the ready state of the nodes added, or affected by
+	// connections added, removed, or disconnected since the last call. Dependencies on nodes that are already resolved are
+	// applied immediately, and dependencies that are no longer connected are dropped. Nodes that are left without
+	// outstanding required dependencies are marked ready if PushStartingNodes() has already been called.
+	// For nodes that are already ready or resolved, resolved dependencies are only recorded.
+	Reconcile() error
 
 	// Mermaid outputs the graph as a Mermaid string.
 	Mermaid() string
+	// MermaidFiltered outputs the part of the graph selected by the filter as a Mermaid string.
+	MermaidFiltered(filter ExportFilter) string
+	// MermaidWithOptions outputs the graph as a Mermaid string, customized with the specified options. The graph is
+	// copied while the lock is held and formatted afterwards, so the option callbacks can use the node functions.
+	MermaidWithOptions(options MermaidOptions[NodeType]) string
+	// DOT outputs the graph in the Graphviz DOT format. Nodes are filled according to their resolution status, and
+	// connection labels are rendered. Like the Mermaid functions, it copies the graph while holding the lock and
+	// formats it afterwards, so the output is consistent even while the graph is being resolved.
+	DOT() string
+	// DOTFiltered outputs the part of the graph selected by the filter in the Graphviz DOT format.
+	DOTFiltered(filter ExportFilter) string
 }
 
 // Node is a single point in a DirectedGraph.
@@ -70,6 +476,24 @@ type Node[NodeType any] interface {
 	ID() string
 	// Item returns the underlying item for the node.
 	Item() NodeType
+	// ResolutionStatus returns the current resolution status of the node.
+	ResolutionStatus() ResolutionStatus
+	// UnresolvableCause returns the reason why the graph resolved the node as Unresolvable on its own, for example
+	// an ErrDeadlineExceeded or an ErrDependencyUnresolvable. It returns nil if the node was not resolved by the
+	// graph.
+	UnresolvableCause() error
+	// ReadyReason returns why the node became ready, or an empty string if it is not ready.
+	ReadyReason() ReadyReason
+	// IsReady returns true if the node has been marked ready, either because its required dependencies are
+	// resolved, or because it became unresolvable.
+	IsReady() bool
+	// Timings returns the timestamps recorded for the node.
+	Timings() NodeTimings
+	// SetExpectedDuration sets a hint of how long processing the node takes. The hint is the cost of the node for
+	// WithCriticalPathPriority and CriticalPath unless a weight function is set, and EstimateMakespan adds it up.
+	SetExpectedDuration(duration time.Duration) error
+	// ExpectedDuration returns the hint set with SetExpectedDuration, or 0 if there is none.
+	ExpectedDuration() time.Duration
 	// Connect creates a new connection from the current node to the specified node.
 	// If the specified node does not exist, ErrNodeNotFound is returned. If fromNodeID is equal to the node's ID,
 	// ErrCannotConnectToSelf is returned.
@@ -77,24 +501,130 @@ type Node[NodeType any] interface {
 	// ConnectDependency creates a new connection from the specified node to the current node.
 	// The dependency type is set to determine when the node becomes finalized.
 	// If the specified node does not exist, ErrNodeNotFound is returned. If fromNodeID is equal to the node's ID,
-	// ErrCannotConnectToSelf is returned.
+	// ErrCannotConnectToSelf is returned, unless the graph permits self-loops with WithSelfLoops.
 	ConnectDependency(fromNodeID string, dependencyType DependencyType) error
+	// ConnectWithMetadata works like Connect, but also attaches key/value metadata to the new connection. Use
+	// LabelMetadataKey to set the label of the connection, which exporters render.
+	ConnectWithMetadata(toNodeID string, metadata map[string]string) error
+	// ConnectDependencyEdge works like ConnectDependency, but returns a handle to the new connection.
+	ConnectDependencyEdge(fromNodeID string, dependencyType DependencyType) (Edge, error)
+	// ConnectDependencyIf creates a conditional dependency, which is a CompletionAndDependency whose condition
+	// decides whether the resolution of the dependency satisfies it. If the condition returns false, the node
+	// becomes unresolvable with an ErrDependencyConditionNotMet cause. This models condition steps without extra
+	// nodes. The condition is called while the graph is locked, when the dependency is resolved and by
+	// ReadinessCondition, so it must not call any function of the graph or of its nodes, which would deadlock. It
+	// should only look at its arguments. It is dropped if the dependency type is changed, and it is not carried by
+	// ExportJSON or SaveState. If the condition is nil, an ErrNilDependencyCondition is returned.
+	ConnectDependencyIf(fromNodeID string, condition DependencyCondition[NodeType]) error
+	// ConnectExpression connects the dependencies of a boolean expression built with And, Or, and Dependency, for
+	// example dgraph.And(dgraph.Or("a", "b"), "c"), as ExpressionDependency. The node becomes ready once the
+	// expression and all other dependencies are satisfied, and unresolvable with an ErrExpressionNotSatisfied cause
+	// once the expression can no longer be satisfied. This replaces artificial join nodes. If the node already has
+	// an expression, both have to be satisfied, and they may share dependencies. Returns an ErrInvalidExpression if
+	// the expression is malformed. Disconnecting or obviating a dependency removes it from the expression.
+	ConnectExpression(expression Expression) error
+	// DependencyExpression returns the expression connected with ConnectExpression, and false if there is none.
+	DependencyExpression() (Expression, bool)
+	// ConnectGroupDependency makes the current node depend on all current and future members of the specified
+	// group, either on all of them or on any one of them, depending on the mode. If the group does not exist,
+	// ErrGroupNotFound is returned, and if the node already depends on the group, ErrGroupDependencyAlreadyExists is
+	// returned. If any of the connections can't be made, the error is returned and the graph is left unchanged.
+	ConnectGroupDependency(groupName string, mode GroupDependencyMode) error
 	// DisconnectInbound removes an incoming connection from the specified node. If the connection does not exist, an
 	// ErrConnectionDoesNotExist is returned.
 	DisconnectInbound(fromNodeID string) error
+	// ObviateDependency marks an outstanding dependency as no longer required, for example because the user
+	// disabled a step while the graph is running. The dependency is changed to ObviatedDependency, and unlike
+	// Edge.Obviate, the readiness of the node is re-evaluated right away: the node becomes ready if no other hard
+	// dependency is outstanding, and unresolvable if it was the last outstanding OR dependency and the others are
+	// unresolvable. A dependency of an expression is removed from the expression. If the dependency has already
+	// been resolved, an ErrDependencyAlreadyResolved is returned. Obviating an obviated dependency has no effect.
+	ObviateDependency(fromNodeID string) error
 	// DisconnectOutbound removes an outgoing connection to the specified node. If the connection does not exist, an
 	// ErrConnectionDoesNotExist is returned.
 	DisconnectOutbound(toNodeID string) error
 	// Remove removes the current node and all connections from the DirectedGraph.
 	Remove() error
+	// RequireResources sets the resource tokens the node needs before it is exposed as ready, replacing previous
+	// requirements. Each token has the "name" or "name:count" format, for example "gpu:2" or "lock:db". If the
+	// graph has fewer tokens of a resource than required, an ErrResourceUnavailable is returned. If the node
+	// currently holds the tokens of its previous requirements, an ErrResourcesHeld is returned. See WithResources.
+	RequireResources(tokens ...string) error
+	// RequiredResources returns the number of tokens of each resource the node needs.
+	RequiredResources() map[string]int
+	// IsExternal returns true if the node is a boundary stub for a node in another partition. See Partition.
+	IsExternal() bool
+	// HasSelfLoop returns true if the node is connected to itself. See WithSelfLoops.
+	HasSelfLoop() bool
+	// CompleteIteration marks the current iteration of a node with a self-loop as complete, and makes the node
+	// ready to run again. The node must have been popped, but not resolved. To finish the last iteration, resolve
+	// the node instead. If the node has no self-loop, an ErrNoSelfLoop is returned.
+	CompleteIteration() error
+	// Iterations returns the number of iterations completed with CompleteIteration.
+	Iterations() int
+	// Annotate attaches free-text annotations to the node, for example "retries 3x" or "requires approval". The
+	// Mermaid and DOT renderers show them as notes and tooltips.
+	Annotate(annotations ...string) error
+	// Annotations returns the annotations of the node in the order they were added.
+	Annotations() []string
+	// SetDescription sets the documentation string of the node, which ExportJSON carries and the DOT renderer shows
+	// as a tooltip, so that exported graphs describe themselves. Edges are described with the
+	// DescriptionMetadataKey connection metadata.
+	SetDescription(description string) error
+	// Description returns the documentation string of the node, or an empty string if it has none.
+	Description() string
+	// SetAttr sets a key/value attribute of the node, which is kept separate from the item, so that exporters and
+	// schedulers can use it without changing the item type. Use LabelMetadataKey to set the label the DOT and
+	// Mermaid renderers show instead of the ID.
+	SetAttr(key string, value string) error
+	// Attr returns the value of the attribute of the node with the specified key, and false if it is not set.
+	Attr(key string) (string, bool)
+	// Attrs returns a copy of all attributes of the node.
+	Attrs() map[string]string
+	// InDegree returns the number of inbound connections to this node without listing them.
+	InDegree() (int, error)
+	// OutDegree returns the number of outbound connections from this node without listing them.
+	OutDegree() (int, error)
 	// ListInboundConnections lists all inbound connections to this node.
 	ListInboundConnections() (map[string]Node[NodeType], error)
 	// ListOutboundConnections lists all outbound connections from this node.
 	ListOutboundConnections() (map[string]Node[NodeType], error)
+	// ListInboundConnectionsOrdered lists the source nodes of all inbound connections to this node. If the graph
+	// was created with WithConnectionOrder, they are listed in the order the connections were added, otherwise
+	// they are sorted by ID.
+	ListInboundConnectionsOrdered() ([]Node[NodeType], error)
+	// ListOutboundConnectionsOrdered lists the destination nodes of all outbound connections from this node, in
+	// the same order as ListInboundConnectionsOrdered.
+	ListOutboundConnectionsOrdered() ([]Node[NodeType], error)
+	// Rename changes the ID of the node, keeping its item, connections, and resolution state, and updates the
+	// dependencies of its dependents, the groups, and the ready set to the new ID. Returns an ErrNodeAlreadyExists
+	// if a node with the new ID exists, or an ErrInvalidNodeID if the ID doesn't match the pattern of the graph.
+	Rename(newID string) error
 	// ResolveNode sets the resolution status of the node, and updates the nodes that follow it in the graph.
 	// The resolution must happen only one time, or else a ErrNodeResolutionAlreadySet is returned.
 	// This transitions the resolution status from the existing state (typically Waiting) to the given state.
 	ResolveNode(status ResolutionStatus) error
+	// ResolveNodeWithResult works like ResolveNode, but also attaches a result payload to the node, for example the
+	// output data of a step. The result is only stored if the resolution changes the status of the node.
+	ResolveNodeWithResult(status ResolutionStatus, result any) error
+	// ResetResolution returns an Unresolvable node to Waiting, so that a failed step can be retried without
+	// rebuilding the graph. If the node was ready, it is added to the ready set again. The node becomes an
+	// outstanding dependency of its dependents again: dependents that became unresolvable because of it are reset
+	// as well, and dependents that became ready because of its failure are removed from the ready set. Returns an
+	// ErrNodeNotRetryable if the node is not Unresolvable, was made unresolvable by its dependencies or the
+	// deadline, or if a dependent has already been popped because of its failure.
+	ResetResolution() error
+	// ResolveNodeWithReason works like ResolveNode, but also attaches the reason why the node is unresolvable, for
+	// example the error of a failed step. The reason is only stored if the node becomes Unresolvable, and it is
+	// propagated to the causes of the dependents the graph makes unresolvable because of it.
+	ResolveNodeWithReason(status ResolutionStatus, reason error) error
+	// UnresolvableReason returns the reason passed to ResolveNodeWithReason. For nodes the graph resolved as
+	// Unresolvable on its own, it returns the UnresolvableCause, which wraps the reasons of the dependencies that
+	// made the node unresolvable, so errors.Is and errors.As find the reason of the node that failed first. It
+	// returns nil if the node is not unresolvable or no reason is known.
+	UnresolvableReason() error
+	// Result returns the payload passed to ResolveNodeWithResult, or nil.
+	Result() any
 	// OutstandingDependencies returns a map of the dependency node ID to the DependencyType of all dependencies
 	// that have not been resolved yet.
 	OutstandingDependencies() map[string]DependencyType
@@ -102,4 +632,21 @@ type Node[NodeType any] interface {
 	// have been marked resolvable. The first OR resolved, if present, will retain its OR dependency type, but all
 	// following OR resolutions will be marked as Obviated.
 	ResolvedDependencies() map[string]DependencyType
+	// ResolvedDependencyHistory returns the same dependencies as ResolvedDependencies, but as a list ordered by
+	// the time of resolution, including the timestamp of each resolution.
+	ResolvedDependencyHistory() []ResolvedDependency
+	// SatisfyingOrDependency returns the ID of the OR dependency that satisfied the OR group of this node. The
+	// second return value is false if no OR dependency has been resolved yet.
+	SatisfyingOrDependency() (string, bool)
+	// ReadinessCondition returns the condition under which the node becomes ready as a boolean expression tree:
+	// all AND, completion-AND, and on-unresolvable dependencies, and any of the OR dependencies, each with the
+	// current status of the dependency. Optional dependencies don't affect readiness and are left out. The
+	// expression of the node, if any, is included as a subexpression.
+	ReadinessCondition() ReadinessCondition
+	// SatisfactionTrace returns the ordered list of dependency resolution events that led to the current
+	// readiness state of the node.
+	SatisfactionTrace() []DependencyEvent
+	// DependencyReport returns the resolved, outstanding, and obviated dependencies of the node along with the
+	// current resolution status of each dependency node, all gathered at the same point in time.
+	DependencyReport() DependencyReport
 }
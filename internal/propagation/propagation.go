@@ -0,0 +1,226 @@
+// Package propagation checks dependency propagation exhaustively. It enumerates every combination of dependency
+// types and dependency resolutions for a node with a small number of dependencies, resolves the dependencies in
+// every order, and compares the state of the node after each step with a brute-force reference model.
+package propagation
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.arcalot.io/dgraph"
+)
+
+// DependencyTypes are the dependency types a node can declare. ObviatedDependency is not included, because it is
+// only ever set by the graph.
+var DependencyTypes = []dgraph.DependencyType{
+	dgraph.AndDependency,
+	dgraph.OrDependency,
+	dgraph.CompletionAndDependency,
+	dgraph.OnUnresolvableDependency,
+	dgraph.OptionalDependency,
+}
+
+// State is the state of the dependent node as seen by the caller of the graph.
+type State string
+
+const (
+	// StateWaiting means the node is neither ready nor unresolvable.
+	StateWaiting State = "waiting"
+	// StateReady means the node is ready and still Waiting.
+	StateReady State = "ready"
+	// StateUnresolvable means the graph resolved the node as Unresolvable.
+	StateUnresolvable State = "unresolvable"
+)
+
+// Case is a single combination in the matrix. Dependency i of the node has the type Dependencies[i] and is resolved
+// with Resolutions[i]. Order lists the indexes of the dependencies in the order they are resolved.
+type Case struct {
+	Dependencies []dgraph.DependencyType
+	Resolutions  []dgraph.ResolutionStatus
+	Order        []int
+}
+
+func (c Case) String() string {
+	result := ""
+	for step, i := range c.Order {
+		if step > 0 {
+			result += ", "
+		}
+		result += fmt.Sprintf("%s %s=%s", c.Dependencies[i], dependencyID(i), c.Resolutions[i])
+	}
+	return result
+}
+
+// Mismatch is a step of a case after which the graph disagrees with the reference model. Step 0 is the state
+// after PushStartingNodes, before any dependency is resolved.
+type Mismatch struct {
+	Case     Case
+	Step     int
+	Expected State
+	Actual   State
+	// Err is set if the graph returned an error instead of reaching a state.
+	Err error
+}
+
+func (m Mismatch) String() string {
+	if m.Err != nil {
+		return fmt.Sprintf("[%s] step %d: %v", m.Case, m.Step, m.Err)
+	}
+	return fmt.Sprintf("[%s] step %d: expected %s, got %s", m.Case, m.Step, m.Expected, m.Actual)
+}
+
+// Cases enumerates all cases for a node with the specified number of dependencies of the specified types.
+func Cases(dependencies int, types []dgraph.DependencyType) func(yield func(Case) bool) {
+	return func(yield func(Case) bool) {
+		orders := permutations(dependencies)
+		for _, dependencyTypes := range product(types, dependencies) {
+			for _, resolutions := range product(
+				[]dgraph.ResolutionStatus{dgraph.Resolved, dgraph.Unresolvable}, dependencies,
+			) {
+				for _, order := range orders {
+					if !yield(Case{dependencyTypes, resolutions, order}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Run checks all cases for a node with up to the specified number of dependencies of all DependencyTypes, and
+// returns the mismatches it finds.
+func Run(maxDependencies int) []Mismatch {
+	var result []Mismatch
+	for dependencies := 0; dependencies <= maxDependencies; dependencies++ {
+		Cases(dependencies, DependencyTypes)(func(c Case) bool {
+			result = append(result, Check(c)...)
+			return true
+		})
+	}
+	return result
+}
+
+// Check builds the graph of a case, resolves the dependencies in order, and compares the state of the node after
+// each step with Expected. Checking stops at the first mismatch.
+func Check(c Case) []Mismatch {
+	d := dgraph.New[string]()
+	n, err := d.AddNode("node", "node")
+	if err != nil {
+		return []Mismatch{{Case: c, Err: err}}
+	}
+	dependencies := make([]dgraph.Node[string], len(c.Dependencies))
+	for i, dependencyType := range c.Dependencies {
+		if dependencies[i], err = d.AddNode(dependencyID(i), dependencyID(i)); err != nil {
+			return []Mismatch{{Case: c, Err: err}}
+		}
+		if err := n.ConnectDependency(dependencyID(i), dependencyType); err != nil {
+			return []Mismatch{{Case: c, Err: err}}
+		}
+	}
+	if err := d.PushStartingNodes(); err != nil {
+		return []Mismatch{{Case: c, Err: err}}
+	}
+	for step := 0; step <= len(c.Order); step++ {
+		if step > 0 {
+			i := c.Order[step-1]
+			if err := dependencies[i].ResolveNode(c.Resolutions[i]); err != nil {
+				return []Mismatch{{Case: c, Step: step, Err: err}}
+			}
+		}
+		expected := Expected(c, step)
+		if actual := state(n); actual != expected {
+			return []Mismatch{{Case: c, Step: step, Expected: expected, Actual: actual}}
+		}
+	}
+	return nil
+}
+
+// Expected is the reference model. It returns the state of the node after the first steps dependencies of the
+// case have been resolved, evaluated from the resolved dependencies alone.
+func Expected(c Case, steps int) State {
+	resolved := make(map[int]bool, steps)
+	for _, i := range c.Order[:steps] {
+		resolved[i] = true
+	}
+	satisfied := true
+	hasOr := false
+	orSatisfied := false
+	orOutstanding := false
+	for i, dependencyType := range c.Dependencies {
+		done := resolved[i]
+		ok := c.Resolutions[i] == dgraph.Resolved
+		switch dependencyType {
+		case dgraph.AndDependency:
+			if done && !ok {
+				return StateUnresolvable
+			}
+			satisfied = satisfied && done
+		case dgraph.OnUnresolvableDependency:
+			if done && ok {
+				return StateUnresolvable
+			}
+			satisfied = satisfied && done
+		case dgraph.CompletionAndDependency:
+			satisfied = satisfied && done
+		case dgraph.OrDependency:
+			hasOr = true
+			orSatisfied = orSatisfied || done && ok
+			orOutstanding = orOutstanding || !done
+		}
+	}
+	if hasOr && !orSatisfied && !orOutstanding {
+		return StateUnresolvable
+	}
+	if satisfied && (!hasOr || orSatisfied) {
+		return StateReady
+	}
+	return StateWaiting
+}
+
+func state(n dgraph.Node[string]) State {
+	switch {
+	case n.ResolutionStatus() == dgraph.Unresolvable:
+		return StateUnresolvable
+	case n.IsReady():
+		return StateReady
+	default:
+		return StateWaiting
+	}
+}
+
+func dependencyID(i int) string {
+	return "dependency" + strconv.Itoa(i)
+}
+
+// product returns all sequences of the specified length of the values.
+func product[T any](values []T, length int) [][]T {
+	result := [][]T{{}}
+	for i := 0; i < length; i++ {
+		var next [][]T
+		for _, prefix := range result {
+			for _, value := range values {
+				next = append(next, append(append([]T{}, prefix...), value))
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// permutations returns all orders of the indexes 0 to n-1.
+func permutations(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var result [][]int
+	for _, order := range permutations(n - 1) {
+		for position := 0; position <= len(order); position++ {
+			next := make([]int, 0, n)
+			next = append(next, order[:position]...)
+			next = append(next, n-1)
+			next = append(next, order[position:]...)
+			result = append(result, next)
+		}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package propagation_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+	"go.arcalot.io/dgraph/internal/propagation"
+)
+
+func TestRun(t *testing.T) {
+	for _, mismatch := range propagation.Run(3) {
+		t.Errorf("%s", mismatch)
+	}
+}
+
+func TestCases(t *testing.T) {
+	count := 0
+	propagation.Cases(2, propagation.DependencyTypes)(func(propagation.Case) bool {
+		count++
+		return true
+	})
+	// 5^2 type combinations, 2^2 resolution combinations, and 2 orders.
+	assert.Equals(t, count, 200)
+}
+
+func TestExpected(t *testing.T) {
+	c := propagation.Case{
+		Dependencies: []dgraph.DependencyType{dgraph.OrDependency, dgraph.OrDependency},
+		Resolutions:  []dgraph.ResolutionStatus{dgraph.Unresolvable, dgraph.Unresolvable},
+		Order:        []int{1, 0},
+	}
+	assert.Equals(t, propagation.Expected(c, 0), propagation.StateWaiting)
+	assert.Equals(t, propagation.Expected(c, 1), propagation.StateWaiting)
+	assert.Equals(t, propagation.Expected(c, 2), propagation.StateUnresolvable)
+}
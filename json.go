@@ -0,0 +1,208 @@
+package dgraph
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// jsonGraph is the serialized form of a graph used by ExportJSON and ImportJSON.
+type jsonGraph struct {
+	Started     bool             `json:"started,omitempty"`
+	Nodes       []jsonNode       `json:"nodes"`
+	Connections []jsonConnection `json:"connections"`
+	// ReadyNodes lists the nodes that are ready, but have not been popped yet.
+	ReadyNodes []string `json:"ready_nodes,omitempty"`
+	// Generation is the generation of the graph when it was exported.
+	Generation uint64 `json:"generation,omitempty"`
+}
+
+type jsonNode struct {
+	ID                     string            `json:"id"`
+	Item                   json.RawMessage   `json:"item"`
+	Status                 ResolutionStatus  `json:"status"`
+	Ready                  bool              `json:"ready,omitempty"`
+	ReadyReason            ReadyReason       `json:"ready_reason,omitempty"`
+	Output                 bool              `json:"output,omitempty"`
+	SatisfyingOrDependency string            `json:"satisfying_or_dependency,omitempty"`
+	External               bool              `json:"external,omitempty"`
+	Description            string            `json:"description,omitempty"`
+	Attributes             map[string]string `json:"attributes,omitempty"`
+	Expression             *Expression       `json:"expression,omitempty"`
+}
+
+type jsonConnection struct {
+	From           string            `json:"from"`
+	To             string            `json:"to"`
+	DependencyType DependencyType    `json:"dependency_type"`
+	Resolved       bool              `json:"resolved,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	// Weight is only set if the connection doesn't have the default weight.
+	Weight *float64 `json:"weight,omitempty"`
+	// ObviatedType is the original type of an obviated dependency.
+	ObviatedType DependencyType `json:"obviated_type,omitempty"`
+}
+
+func (d *directedGraph[NodeType]) ExportJSON() ([]byte, error) {
+	return d.ExportJSONFiltered(ExportFilter{})
+}
+
+func (d *directedGraph[NodeType]) ExportJSONFiltered(filter ExportFilter) ([]byte, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	result := jsonGraph{
+		Started:     d.started,
+		Nodes:       make([]jsonNode, 0, len(d.nodes)),
+		Connections: []jsonConnection{},
+		Generation:  d.generation,
+	}
+	nodeIDs := make([]string, 0, len(d.nodes))
+	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		n := d.nodes[nodeID]
+		if !filter.includesStatus(n.status) {
+			continue
+		}
+		item, err := json.Marshal(n.item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the item of node %q (%w)", nodeID, err)
+		}
+		result.Nodes = append(result.Nodes, jsonNode{
+			ID:                     nodeID,
+			Item:                   item,
+			Status:                 n.status,
+			Ready:                  n.ready,
+			ReadyReason:            n.readyReason,
+			Output:                 n.output,
+			SatisfyingOrDependency: n.satisfyingOrDependency,
+			External:               n.external,
+			Description:            n.description,
+			Attributes:             n.attributes,
+			Expression:             n.expression,
+		})
+		if d.isPendingReady(nodeID) {
+			result.ReadyNodes = append(result.ReadyNodes, nodeID)
+		}
+	}
+	connections := d.listConnections()
+	if !d.config.preserveConnectionOrder {
+		// Sort by destination first, so that the dependencies of each node are listed together.
+		slices.SortStableFunc(connections, func(a, b Connection) int {
+			return cmp.Compare(a.DestinationNodeID, b.DestinationNodeID)
+		})
+	}
+	for _, connection := range connections {
+		if !filter.includesStatus(d.nodes[connection.SourceNodeID].status) ||
+			!filter.includesStatus(d.nodes[connection.DestinationNodeID].status) ||
+			!filter.includesDependencyType(connection.DependencyType) {
+			continue
+		}
+		c := jsonConnection{
+			From:           connection.SourceNodeID,
+			To:             connection.DestinationNodeID,
+			DependencyType: connection.DependencyType,
+			Resolved:       connection.Resolved,
+			Metadata:       connection.Metadata,
+		}
+		pair := [2]string{connection.SourceNodeID, connection.DestinationNodeID}
+		if weight, ok := d.connectionWeights[pair]; ok {
+			c.Weight = &weight
+		}
+		c.ObviatedType = d.nodes[connection.DestinationNodeID].obviatedTypes[connection.SourceNodeID]
+		result.Connections = append(result.Connections, c)
+	}
+	return json.Marshal(result)
+}
+
+// ImportJSON reconstructs a graph from the output of DirectedGraph.ExportJSON. The nodes, connections, dependency
+// types, expressions, resolution statuses, and the ready nodes that have not been popped are restored. Timings and resolution
+// histories are not persisted. Items are unmarshalled into NodeType, using json.Unmarshaler if NodeType implements
+// it. The options are applied to the new graph as with New, and node IDs are normalized accordingly.
+func ImportJSON[NodeType any](data []byte, options ...Option) (DirectedGraph[NodeType], error) {
+	var input jsonGraph
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal graph (%w)", err)
+	}
+	d := newDirectedGraph[NodeType](newConfig(options))
+	d.started = input.Started
+	if d.started {
+		d.startedAt = d.config.clock()
+	}
+	for _, inputNode := range input.Nodes {
+		if _, err := d.AddNode(inputNode.ID, *new(NodeType)); err != nil {
+			return nil, err
+		}
+		n := d.nodes[d.config.normalizeID(inputNode.ID)]
+		if err := json.Unmarshal(inputNode.Item, &n.item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the item of node %q (%w)", inputNode.ID, err)
+		}
+		n.status = inputNode.Status
+		n.ready = inputNode.Ready
+		n.readyReason = inputNode.ReadyReason
+		n.output = inputNode.Output
+		n.satisfyingOrDependency = d.config.normalizeID(inputNode.SatisfyingOrDependency)
+		n.external = inputNode.External
+		n.description = inputNode.Description
+		n.attributes = inputNode.Attributes
+		if inputNode.Expression != nil {
+			expression, err := inputNode.Expression.validate(d.config.normalizeID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import the expression of node %q (%w)", inputNode.ID, err)
+			}
+			n.expression = &expression
+		}
+	}
+	for _, connection := range input.Connections {
+		fromID := d.config.normalizeID(connection.From)
+		toID := d.config.normalizeID(connection.To)
+		if _, ok := d.nodes[fromID]; !ok {
+			return nil, d.nodeNotFound(fromID)
+		}
+		n, ok := d.nodes[toID]
+		if !ok {
+			return nil, d.nodeNotFound(toID)
+		}
+		if _, ok := d.connectionsFromNode[fromID][toID]; ok {
+			return nil, &ErrConnectionAlreadyExists{fromID, toID}
+		}
+		d.connectionsFromNode[fromID][toID] = struct{}{}
+		d.connectionsToNode[toID][fromID] = struct{}{}
+		d.recordConnection(fromID, toID)
+		if len(connection.Metadata) > 0 {
+			d.connectionMetadata[[2]string{fromID, toID}] = connection.Metadata
+		}
+		if connection.Weight != nil {
+			d.connectionWeights[[2]string{fromID, toID}] = *connection.Weight
+		}
+		if connection.ObviatedType != "" {
+			if n.obviatedTypes == nil {
+				n.obviatedTypes = map[string]DependencyType{}
+			}
+			n.obviatedTypes[fromID] = connection.ObviatedType
+		}
+		if connection.Resolved && d.nodes[fromID].status == Unresolvable {
+			n.failedDependencies[fromID] = connection.DependencyType
+		} else if connection.Resolved {
+			n.resolvedDependencies[fromID] = connection.DependencyType
+		} else {
+			n.outstandingDependencies[fromID] = connection.DependencyType
+		}
+	}
+	// The restored state is already settled, so there is nothing to reconcile.
+	d.changedNodes = map[string]struct{}{}
+	for _, nodeID := range input.ReadyNodes {
+		n, ok := d.nodes[d.config.normalizeID(nodeID)]
+		if !ok {
+			return nil, d.nodeNotFound(nodeID)
+		}
+		d.pushReady(n)
+	}
+	// The imported graph continues the generations of the exported graph.
+	d.generation = input.Generation
+	return d, nil
+}
@@ -0,0 +1,128 @@
+package dgraph_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// upperItem is stored in lowercase, but serialized in uppercase to test the use of json.Marshaler.
+type upperItem struct {
+	value string
+}
+
+func (u upperItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(u.value))
+}
+
+func (u *upperItem) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	u.value = strings.ToLower(value)
+	return nil
+}
+
+func TestDirectedGraph_ExportJSON(t *testing.T) {
+	d := dgraph.New[upperItem]()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_, err := d.AddNode(id, upperItem{id})
+		assert.NoError(t, err)
+	}
+	c := assert.NoErrorR[dgraph.Node[upperItem]](t)(d.GetNodeByID("c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.OrDependency))
+	dNode := assert.NoErrorR[dgraph.Node[upperItem]](t)(d.GetNodeByID("d"))
+	assert.NoError(t, dNode.ConnectDependency("c", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	a := assert.NoErrorR[dgraph.Node[upperItem]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+
+	data := assert.NoErrorR[[]byte](t)(d.ExportJSON())
+	assert.Contains(t, string(data), `"item":"A"`)
+
+	d2 := assert.NoErrorR[dgraph.DirectedGraph[upperItem]](t)(dgraph.ImportJSON[upperItem](data))
+	assert.Equals(t, assert.NoErrorR[[]byte](t)(d2.ExportJSON()), data)
+
+	c2 := assert.NoErrorR[dgraph.Node[upperItem]](t)(d2.GetNodeByID("c"))
+	assert.Equals(t, c2.Item(), upperItem{"c"})
+	assert.Equals(t, c2.ResolvedDependencies(), map[string]dgraph.DependencyType{"a": dgraph.OrDependency})
+	assert.Equals(t, c2.OutstandingDependencies(), map[string]dgraph.DependencyType{"b": dgraph.ObviatedDependency})
+	readyNodes := d2.PopReadyNodes()
+	assert.Equals(t, len(readyNodes), 1)
+	assert.MapContainsKey(t, "c", readyNodes)
+
+	// Resolving the restored graph continues where the original left off.
+	assert.NoError(t, c2.ResolveNode(dgraph.Resolved))
+	readyNodes = d2.PopReadyNodes()
+	assert.Equals(t, len(readyNodes), 1)
+	assert.MapContainsKey(t, "d", readyNodes)
+}
+
+func TestImportJSON_InvalidConnection(t *testing.T) {
+	_, err := dgraph.ImportJSON[string]([]byte(
+		`{"nodes":[{"id":"a","item":"a","status":"waiting"}],"connections":[{"from":"a","to":"b","dependency_type":"and"}]}`,
+	))
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+}
+
+func TestDirectedGraph_ExportJSONDescriptions(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	_, err := d.AddNode("b", "b")
+	assert.NoError(t, err)
+	assert.NoError(t, a.SetDescription("Builds the image."))
+	assert.NoError(t, a.SetAttr("tag", "ci"))
+	assert.NoError(t, a.ConnectWithMetadata("b", map[string]string{
+		dgraph.DescriptionMetadataKey: "b needs the image",
+	}))
+
+	data := assert.NoErrorR[[]byte](t)(d.ExportJSON())
+	assert.Contains(t, string(data), `"description":"Builds the image."`)
+	d2 := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(dgraph.ImportJSON[string](data))
+	a2 := assert.NoErrorR[dgraph.Node[string]](t)(d2.GetNodeByID("a"))
+	assert.Equals(t, a2.Description(), "Builds the image.")
+	assert.Equals(t, a2.Attrs(), map[string]string{"tag": "ci"})
+	edge := assert.NoErrorR[dgraph.Edge](t)(d2.GetEdge("a", "b"))
+	metadata := assert.NoErrorR[map[string]string](t)(edge.Metadata())
+	assert.Equals(t, metadata[dgraph.DescriptionMetadataKey], "b needs the image")
+}
+
+func TestDirectedGraph_ExportJSONFiltered(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("d", dgraph.CompletionAndDependency))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+
+	assert.Equals(
+		t,
+		assert.NoErrorR[[]byte](t)(d.ExportJSONFiltered(dgraph.ExportFilter{})),
+		assert.NoErrorR[[]byte](t)(d.ExportJSON()),
+	)
+	data := assert.NoErrorR[[]byte](t)(d.ExportJSONFiltered(dgraph.ExportFilter{
+		Statuses:                []dgraph.ResolutionStatus{dgraph.Waiting},
+		ExcludedDependencyTypes: []dgraph.DependencyType{dgraph.CompletionAndDependency},
+	}))
+	// The resolved node a and its connection to b are left out, as is the excluded connection from d to c.
+	filtered := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(dgraph.ImportJSON[string](data))
+	assert.Equals(t, len(filtered.ListNodes()), 3)
+	assert.MapContainsKey(t, "b", filtered.ListNodes())
+	assert.MapContainsKey(t, "c", filtered.ListNodes())
+	assert.MapContainsKey(t, "d", filtered.ListNodes())
+	connections := filtered.ListConnections()
+	assert.Equals(t, len(connections), 1)
+	assert.Equals(t, connections[0].SourceNodeID, "b")
+	assert.Equals(t, connections[0].DestinationNodeID, "c")
+}
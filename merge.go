@@ -0,0 +1,194 @@
+package dgraph
+
+import "fmt"
+
+// MergeConflictPolicy determines what Merge does if a node of the other graph has the same ID as an existing node.
+type MergeConflictPolicy string
+
+const (
+	// MergeConflictError reports every node with a duplicate ID as a conflict. This is the default.
+	MergeConflictError MergeConflictPolicy = "error"
+	// MergeConflictKeepExisting keeps the existing node and its item. The connections of the other node are still
+	// merged, so the fragments are joined at the node.
+	MergeConflictKeepExisting MergeConflictPolicy = "keep-existing"
+	// MergeConflictReplaceItem works like MergeConflictKeepExisting, but replaces the item of the existing node with
+	// the item of the other node.
+	MergeConflictReplaceItem MergeConflictPolicy = "replace-item"
+)
+
+// MergeConflictKind is the kind of a MergeConflict.
+type MergeConflictKind string
+
+const (
+	// MergeConflictDuplicateNode means that a node with the same ID exists in both graphs and the conflict policy
+	// is MergeConflictError.
+	MergeConflictDuplicateNode MergeConflictKind = "duplicate-node"
+	// MergeConflictItemMismatch means that the items of a node that exists in both graphs are different according
+	// to the comparator passed to WithMergeItemComparator.
+	MergeConflictItemMismatch MergeConflictKind = "item-mismatch"
+	// MergeConflictDependencyType means that a connection exists in both graphs with different dependency types.
+	MergeConflictDependencyType MergeConflictKind = "dependency-type"
+)
+
+// MergeConflict is a single problem found by Merge.
+type MergeConflict struct {
+	Kind MergeConflictKind
+	// NodeID is the ID of the conflicting node, after applying the ID prefix. It is empty for connection conflicts.
+	NodeID string
+	// SourceNodeID and DestinationNodeID identify the conflicting connection. They are empty for node conflicts.
+	SourceNodeID      string
+	DestinationNodeID string
+	// ExistingType and IncomingType are the declared dependency types of a conflicting connection in this graph and
+	// in the merged graph.
+	ExistingType DependencyType
+	IncomingType DependencyType
+}
+
+func (c MergeConflict) String() string {
+	switch c.Kind {
+	case MergeConflictDuplicateNode:
+		return fmt.Sprintf("node %q already exists", c.NodeID)
+	case MergeConflictItemMismatch:
+		return fmt.Sprintf("node %q has a different item", c.NodeID)
+	default:
+		return fmt.Sprintf(
+			"connection from node %q to node %q has dependency type %s, but the merged graph has %s",
+			c.SourceNodeID, c.DestinationNodeID, c.ExistingType, c.IncomingType,
+		)
+	}
+}
+
+// MergeOption configures Merge.
+type MergeOption func(c *mergeConfig)
+
+type mergeConfig struct {
+	conflictPolicy MergeConflictPolicy
+	idPrefix       string
+	itemsEqual     func(existing, incoming any) bool
+}
+
+// WithMergeConflictPolicy sets how nodes with duplicate IDs are handled. The default is MergeConflictError.
+func WithMergeConflictPolicy(policy MergeConflictPolicy) MergeOption {
+	return func(c *mergeConfig) {
+		c.conflictPolicy = policy
+	}
+}
+
+// WithMergeIDPrefix prepends the prefix to the IDs of all merged nodes, which allows merging the same fragment
+// several times, for example with the prefix "steps.example.".
+func WithMergeIDPrefix(prefix string) MergeOption {
+	return func(c *mergeConfig) {
+		c.idPrefix = prefix
+	}
+}
+
+// WithMergeItemComparator reports a MergeConflictItemMismatch for every node that exists in both graphs and whose
+// items are not equal according to the comparator. The node type must match the type of the merged graphs.
+func WithMergeItemComparator[NodeType any](equal func(existing, incoming NodeType) bool) MergeOption {
+	return func(c *mergeConfig) {
+		c.itemsEqual = func(existing, incoming any) bool {
+			return equal(existing.(NodeType), incoming.(NodeType))
+		}
+	}
+}
+
+func (d *directedGraph[NodeType]) Merge(other DirectedGraph[NodeType], options ...MergeOption) error {
+	c := mergeConfig{conflictPolicy: MergeConflictError}
+	for _, option := range options {
+		option(&c)
+	}
+	// The other graph is read before taking the lock, since it may be this graph.
+	t := topologyOf(other)
+
+	d.lock.Lock()
+	defer d.unlock()
+	mergedID := func(nodeID string) string {
+		return d.config.normalizeID(c.idPrefix + nodeID)
+	}
+	var conflicts []MergeConflict
+	for _, nodeID := range t.nodeIDs {
+		id := mergedID(nodeID)
+		if d.config.idPattern != nil && !d.config.idPattern.MatchString(id) {
+			return &ErrInvalidNodeID{id, d.config.idPattern.String()}
+		}
+		existing, exists := d.nodes[id]
+		switch {
+		case !exists:
+		case c.conflictPolicy == MergeConflictError:
+			conflicts = append(conflicts, MergeConflict{Kind: MergeConflictDuplicateNode, NodeID: id})
+		case c.itemsEqual != nil && !c.itemsEqual(existing.item, t.items[nodeID]):
+			conflicts = append(conflicts, MergeConflict{Kind: MergeConflictItemMismatch, NodeID: id})
+		}
+	}
+	for _, connection := range t.connections {
+		fromID, toID := mergedID(connection.SourceNodeID), mergedID(connection.DestinationNodeID)
+		if _, exists := d.connectionsFromNode[fromID][toID]; !exists {
+			continue
+		}
+		if existingType := d.nodes[toID].declaredDependencyType(fromID); existingType != connection.DependencyType {
+			conflicts = append(conflicts, MergeConflict{
+				Kind:              MergeConflictDependencyType,
+				SourceNodeID:      fromID,
+				DestinationNodeID: toID,
+				ExistingType:      existingType,
+				IncomingType:      connection.DependencyType,
+			})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ErrMergeConflicts{conflicts}
+	}
+
+	for _, nodeID := range t.nodeIDs {
+		id := mergedID(nodeID)
+		if existing, exists := d.nodes[id]; exists {
+			if c.conflictPolicy == MergeConflictReplaceItem {
+				existing.item = t.items[nodeID]
+			}
+			continue
+		}
+		if _, err := d.addNode(id, t.items[nodeID]); err != nil {
+			return err
+		}
+	}
+	for _, connection := range t.connections {
+		fromID, toID := mergedID(connection.SourceNodeID), mergedID(connection.DestinationNodeID)
+		if _, exists := d.connectionsFromNode[fromID][toID]; exists {
+			continue
+		}
+		if err := d.connect(fromID, toID, connection.DependencyType); err != nil {
+			return err
+		}
+		if len(connection.Metadata) > 0 {
+			d.connectionMetadata[[2]string{fromID, toID}] = connection.Metadata
+		}
+		if connection.Weight != DefaultConnectionWeight {
+			d.connectionWeights[[2]string{fromID, toID}] = connection.Weight
+		}
+		if condition, ok := t.conditions[[2]string{connection.SourceNodeID, connection.DestinationNodeID}]; ok {
+			d.connectionConditions[[2]string{fromID, toID}] = condition
+		}
+	}
+	for _, nodeID := range t.nodeIDs {
+		expression, ok := t.expressions[nodeID]
+		if !ok {
+			continue
+		}
+		expression, err := expression.validate(mergedID)
+		if err != nil {
+			return err
+		}
+		n := d.nodes[mergedID(nodeID)]
+		switch {
+		case n.expression == nil:
+			n.expression = &expression
+		case n.expression.String() == expression.String():
+			// The fragments are joined at a node with the same expression.
+			continue
+		default:
+			n.expression = &Expression{Operator: ConditionAll, Operands: []Expression{*n.expression, expression}}
+		}
+		d.markChanged(n.id)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// fragment builds a graph in which "run" depends on "input" and "outputs" depends on "run".
+func fragment(t *testing.T, item string) dgraph.DirectedGraph[string] {
+	d := dgraph.New[string]()
+	for _, id := range []string{"input", "run", "outputs"} {
+		_, err := d.AddNode(id, item)
+		assert.NoError(t, err)
+	}
+	run := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("run"))
+	outputs := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("outputs"))
+	assert.NoError(t, run.ConnectDependency("input", dgraph.AndDependency))
+	assert.NoError(t, outputs.ConnectDependency("run", dgraph.OrDependency))
+	return d
+}
+
+func TestDirectedGraph_Merge(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoError(t, d.Merge(fragment(t, "a"), dgraph.WithMergeIDPrefix("steps.a.")))
+	assert.NoError(t, d.Merge(fragment(t, "b"), dgraph.WithMergeIDPrefix("steps.b.")))
+	assert.Equals(t, len(d.ListNodes()), 6)
+	outputs := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("steps.b.outputs"))
+	assert.Equals(t, outputs.Item(), "b")
+	assert.Equals(t, outputs.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"steps.b.run": dgraph.OrDependency,
+	})
+
+	// Duplicate IDs are rejected by default without changing the graph.
+	err := d.Merge(fragment(t, "c"), dgraph.WithMergeIDPrefix("steps.a."))
+	var conflicts *dgraph.ErrMergeConflicts
+	assert.Equals(t, errors.As(err, &conflicts), true)
+	assert.Equals(t, len(conflicts.Conflicts), 3)
+	assert.Equals(t, conflicts.Conflicts[0], dgraph.MergeConflict{
+		Kind:   dgraph.MergeConflictDuplicateNode,
+		NodeID: "steps.a.input",
+	})
+	assert.Equals(t, len(d.ListNodes()), 6)
+}
+
+func TestDirectedGraph_MergeConflictReport(t *testing.T) {
+	d := fragment(t, "existing")
+	other := dgraph.New[string]()
+	for id, item := range map[string]string{"input": "existing", "run": "changed", "outputs": "existing"} {
+		_, err := other.AddNode(id, item)
+		assert.NoError(t, err)
+	}
+	run := assert.NoErrorR[dgraph.Node[string]](t)(other.GetNodeByID("run"))
+	outputs := assert.NoErrorR[dgraph.Node[string]](t)(other.GetNodeByID("outputs"))
+	assert.NoError(t, run.ConnectDependency("input", dgraph.AndDependency))
+	assert.NoError(t, outputs.ConnectDependency("run", dgraph.AndDependency))
+
+	err := d.Merge(
+		other,
+		dgraph.WithMergeConflictPolicy(dgraph.MergeConflictKeepExisting),
+		dgraph.WithMergeItemComparator(func(existing, incoming string) bool {
+			return existing == incoming
+		}),
+	)
+	var conflicts *dgraph.ErrMergeConflicts
+	assert.Equals(t, errors.As(err, &conflicts), true)
+	assert.Equals(t, conflicts.Conflicts, []dgraph.MergeConflict{
+		{Kind: dgraph.MergeConflictItemMismatch, NodeID: "run"},
+		{
+			Kind:              dgraph.MergeConflictDependencyType,
+			SourceNodeID:      "run",
+			DestinationNodeID: "outputs",
+			ExistingType:      dgraph.OrDependency,
+			IncomingType:      dgraph.AndDependency,
+		},
+	})
+	assert.Contains(t, err.Error(), "merge failed with 2 conflicts")
+}
+
+func TestDirectedGraph_MergeConflictPolicies(t *testing.T) {
+	for policy, expectedItem := range map[dgraph.MergeConflictPolicy]string{
+		dgraph.MergeConflictKeepExisting: "existing",
+		dgraph.MergeConflictReplaceItem:  "merged",
+	} {
+		t.Run(string(policy), func(t *testing.T) {
+			d := dgraph.New[string]()
+			_, err := d.AddNode("run", "existing")
+			assert.NoError(t, err)
+			assert.NoError(t, d.Merge(fragment(t, "merged"), dgraph.WithMergeConflictPolicy(policy)))
+			run := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("run"))
+			assert.Equals(t, run.Item(), expectedItem)
+			// The existing node is joined with the connections of the merged fragment.
+			assert.Equals(t, run.OutstandingDependencies(), map[string]dgraph.DependencyType{
+				"input": dgraph.AndDependency,
+			})
+			assert.Equals(t, len(d.ListConnections()), 2)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package dgraph
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MermaidShape is the shape of a node in a Mermaid diagram.
+type MermaidShape string
+
+const (
+	// MermaidShapeDefault does not declare the node, which Mermaid renders as a rectangle.
+	MermaidShapeDefault    MermaidShape = ""
+	MermaidShapeRectangle  MermaidShape = "rectangle"
+	MermaidShapeRounded    MermaidShape = "rounded"
+	MermaidShapeStadium    MermaidShape = "stadium"
+	MermaidShapeSubroutine MermaidShape = "subroutine"
+	MermaidShapeRhombus    MermaidShape = "rhombus"
+	MermaidShapeHexagon    MermaidShape = "hexagon"
+	MermaidShapeCircle     MermaidShape = "circle"
+)
+
+// mermaidShapeDelimiters maps the shapes to the opening and closing delimiters of the node declaration.
+var mermaidShapeDelimiters = map[MermaidShape][2]string{
+	MermaidShapeRectangle:  {"[", "]"},
+	MermaidShapeRounded:    {"(", ")"},
+	MermaidShapeStadium:    {"([", "])"},
+	MermaidShapeSubroutine: {"[[", "]]"},
+	MermaidShapeRhombus:    {"{", "}"},
+	MermaidShapeHexagon:    {"{{", "}}"},
+	MermaidShapeCircle:     {"((", "))"},
+}
+
+// MermaidDirection is the direction of a Mermaid flowchart.
+type MermaidDirection string
+
+const (
+	// MermaidDirectionDefault renders the flowchart from left to right.
+	MermaidDirectionDefault   MermaidDirection = ""
+	MermaidDirectionLeftRight MermaidDirection = "LR"
+	MermaidDirectionRightLeft MermaidDirection = "RL"
+	MermaidDirectionTopDown   MermaidDirection = "TD"
+	MermaidDirectionBottomUp  MermaidDirection = "BT"
+)
+
+// MermaidOptions customizes the Mermaid output of a graph. The zero value renders the same output as Mermaid().
+type MermaidOptions[NodeType any] struct {
+	// Filter selects the nodes and connections to render.
+	Filter ExportFilter
+	// Direction sets the direction of the flowchart. The default is MermaidDirectionLeftRight.
+	Direction MermaidDirection
+	// Title, if set, is rendered as the title of the diagram.
+	Title string
+	// ExcludeErrorPath omits the connections on the error path from the output. See WithErrorPathClassifier.
+	ExcludeErrorPath bool
+	// DependencyTypeLabels labels every connection without a label with its dependency type.
+	DependencyTypeLabels bool
+	// NodeShape, if set, chooses the shape of each rendered node, for example based on its item. Nodes with a
+	// shape other than MermaidShapeDefault, and nodes with a LabelMetadataKey attribute, are declared in a separate
+	// section before the connections.
+	NodeShape func(node Node[NodeType]) MermaidShape
+	// NodeStyle, if set, returns the Mermaid style of each rendered node, for example "fill:#f96", which is used to
+	// highlight nodes. Nodes with an empty style are not styled.
+	NodeStyle func(node Node[NodeType]) string
+	// Group, if set, returns the name of the group of each rendered node, for example based on its ID prefix.
+	// Every group is rendered as a Mermaid subgraph containing its nodes. Nodes with an empty group name are not
+	// grouped.
+	Group func(node Node[NodeType]) string
+}
+
+// MermaidGroupByIDPrefix returns a MermaidOptions.Group function that groups nodes by the first segments of their
+// IDs, split by the separator. For example, with the separator "." and 2 segments, the nodes "steps.example.run"
+// and "steps.example.outputs" are grouped as "steps.example". Nodes with no more than the specified number of
+// segments are not grouped.
+func MermaidGroupByIDPrefix[NodeType any](separator string, segments int) func(node Node[NodeType]) string {
+	return func(node Node[NodeType]) string {
+		parts := strings.Split(node.ID(), separator)
+		if len(parts) <= segments {
+			return ""
+		}
+		return strings.Join(parts[:segments], separator)
+	}
+}
+
+func (d *directedGraph[NodeType]) Mermaid() string {
+	return d.MermaidWithOptions(MermaidOptions[NodeType]{})
+}
+
+func (d *directedGraph[NodeType]) MermaidFiltered(filter ExportFilter) string {
+	return d.MermaidWithOptions(MermaidOptions[NodeType]{Filter: filter})
+}
+
+func (d *directedGraph[NodeType]) MermaidWithOptions(options MermaidOptions[NodeType]) string {
+	d.lock.RLock()
+	snapshot := d.renderSnapshot(options.Filter)
+	d.lock.RUnlock()
+
+	// The rendering happens outside the lock, so the callbacks can use the node functions.
+	var result []string
+	if options.Title != "" {
+		result = append(result, "---", fmt.Sprintf("title: %q", options.Title), "---")
+	}
+	result = append(
+		result,
+		"%% Mermaid markdown workflow",
+		"flowchart "+string(cmp.Or(options.Direction, MermaidDirectionLeftRight)),
+	)
+
+	declaredNodes := map[string]struct{}{}
+	var declarations []string
+	for _, n := range snapshot.nodes {
+		shape := MermaidShapeDefault
+		if options.NodeShape != nil {
+			shape = options.NodeShape(n.node)
+		}
+		delimiters, ok := mermaidShapeDelimiters[shape]
+		if !ok {
+			if n.label == "" {
+				continue
+			}
+			// Labeled nodes must be declared, so they get the rectangle Mermaid uses for undeclared nodes.
+			delimiters = mermaidShapeDelimiters[MermaidShapeRectangle]
+		}
+		declaredNodes[n.id] = struct{}{}
+		declarations = append(declarations, fmt.Sprintf(
+			"%s%s\"%s\"%s", mermaidNodeID(n.id), delimiters[0], escapeMermaidLabel(cmp.Or(n.label, n.id)), delimiters[1],
+		))
+	}
+	if len(declarations) > 0 {
+		slices.Sort(declarations)
+		result = append(result, "%% Nodes")
+		result = append(result, declarations...)
+	}
+	nodeRef := func(nodeID string) string {
+		if _, ok := declaredNodes[nodeID]; ok {
+			return mermaidNodeID(nodeID)
+		}
+		return mermaidNodeRef(nodeID)
+	}
+	if options.Group != nil {
+		groups := map[string][]string{}
+		var groupNames []string
+		for _, n := range snapshot.nodes {
+			group := options.Group(n.node)
+			if group == "" {
+				continue
+			}
+			if _, ok := groups[group]; !ok {
+				groupNames = append(groupNames, group)
+			}
+			groups[group] = append(groups[group], n.id)
+		}
+		if len(groupNames) > 0 {
+			slices.Sort(groupNames)
+			result = append(result, "%% Groups")
+			for _, group := range groupNames {
+				// The group ID is always sanitized, so it can't collide with a node ID.
+				result = append(result, fmt.Sprintf(
+					"subgraph %s[\"%s\"]", mermaidNodeID("group "+group), escapeMermaidLabel(group),
+				))
+				nodeIDs := groups[group]
+				slices.Sort(nodeIDs)
+				for _, nodeID := range nodeIDs {
+					result = append(result, "    "+nodeRef(nodeID))
+				}
+				result = append(result, "end")
+			}
+		}
+	}
+
+	result = append(result, "%% Success path")
+	var successPath, errorPath []string
+
+	for _, connection := range snapshot.connections {
+		label := connection.label
+		if label == "" && options.DependencyTypeLabels {
+			label = string(connection.dependencyType)
+		}
+		arrow := "-->"
+		if label != "" {
+			arrow = fmt.Sprintf("-->|\"%s\"|", escapeMermaidLabel(label))
+		}
+		line := fmt.Sprintf("%s%s%s", nodeRef(connection.from), arrow, nodeRef(connection.to))
+		if connection.errorPath {
+			if !options.ExcludeErrorPath {
+				errorPath = append(errorPath, line)
+			}
+		} else {
+			successPath = append(successPath, line)
+		}
+	}
+	if !snapshot.preserveConnectionOrder {
+		slices.Sort(successPath)
+		slices.Sort(errorPath)
+	}
+
+	result = append(result, successPath...)
+	result = append(result, "%% Error path")
+	result = append(result, errorPath...)
+	var notes []string
+	for _, n := range snapshot.nodes {
+		if len(n.annotations) == 0 {
+			continue
+		}
+		lines := make([]string, len(n.annotations))
+		for i, annotation := range n.annotations {
+			lines[i] = escapeMermaidLabel(annotation)
+		}
+		// The note ID is always sanitized, so it can't collide with a node ID.
+		notes = append(notes, fmt.Sprintf(
+			"%s>\"%s\"] -.- %s", mermaidNodeID("note "+n.id), strings.Join(lines, "<br>"), nodeRef(n.id),
+		))
+	}
+	if len(notes) > 0 {
+		result = append(result, "%% Notes")
+		result = append(result, notes...)
+	}
+	if options.NodeStyle != nil {
+		var styles []string
+		for _, n := range snapshot.nodes {
+			if style := options.NodeStyle(n.node); style != "" {
+				styles = append(styles, fmt.Sprintf("style %s %s", mermaidNodeID(n.id), style))
+			}
+		}
+		if len(styles) > 0 {
+			slices.Sort(styles)
+			result = append(result, "%% Styles")
+			result = append(result, styles...)
+		}
+	}
+	result = append(result, "%% Mermaid end")
+	return strings.Join(result, "\n") + "\n"
+}
+
+// MermaidSafeIDPattern matches node IDs that can be used in Mermaid diagrams as-is. Use it with WithIDPattern to
+// reject other IDs when nodes are added.
+var MermaidSafeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_]+(?:[.-][A-Za-z0-9_]+)*$`)
+
+// mermaidReservedIDs are IDs that Mermaid interprets as keywords.
+var mermaidReservedIDs = map[string]struct{}{"end": {}, "subgraph": {}, "graph": {}, "flowchart": {}}
+
+// mermaidNodeRef returns the node reference to use in a Mermaid diagram. Safe IDs are used as-is. Other IDs are
+// replaced with a sanitized ID, which is made unique with a hash of the original, and the original ID is kept as
+// an escaped label.
+func mermaidNodeRef(nodeID string) string {
+	if isMermaidSafeID(nodeID) {
+		return nodeID
+	}
+	return fmt.Sprintf("%s[\"%s\"]", mermaidNodeID(nodeID), escapeMermaidLabel(nodeID))
+}
+
+// mermaidNodeID returns the node ID to use in a Mermaid diagram, without a label.
+func mermaidNodeID(nodeID string) string {
+	if isMermaidSafeID(nodeID) {
+		return nodeID
+	}
+	sanitized := strings.Map(func(r rune) rune {
+		if r < 128 && (r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, nodeID)
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(nodeID))
+	return fmt.Sprintf("%s_%08x", sanitized, hash.Sum32())
+}
+
+func isMermaidSafeID(nodeID string) bool {
+	_, reserved := mermaidReservedIDs[strings.ToLower(nodeID)]
+	return !reserved && MermaidSafeIDPattern.MatchString(nodeID)
+}
+
+// escapeMermaidLabel escapes the characters that would end or break a quoted Mermaid label.
+func escapeMermaidLabel(label string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"\n", " ",
+	).Replace(label)
+}
@@ -0,0 +1,195 @@
+package dgraph_test
+
+import (
+	"regexp"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_MermaidSanitizesIDs(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("step one", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(`say "hi"`, "b"))
+	end := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("end", "end"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, end.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.Equals(t, d.Mermaid(), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+say__hi__4ab80c81["say #quot;hi#quot;"]-->end_6a8e75aa["end"]
+step_one_94543fb7["step one"]-->say__hi__4ab80c81["say #quot;hi#quot;"]
+%% Error path
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_IDPattern(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithIDPattern(dgraph.MermaidSafeIDPattern))
+	_, err := d.AddNode("steps.example-1.outputs", "valid")
+	assert.NoError(t, err)
+	_, err = d.AddNode("step one", "invalid")
+	assert.InstanceOf[*dgraph.ErrInvalidNodeID](t, err)
+	_, err = d.AddNode("trailing-", "invalid")
+	assert.InstanceOf[*dgraph.ErrInvalidNodeID](t, err)
+}
+
+func TestDirectedGraph_MermaidNodeShapes(t *testing.T) {
+	d := dgraph.New[string]()
+	input := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("input", "input"))
+	step := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("steps.example", "step"))
+	output := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("outputs success", "output"))
+	assert.NoError(t, step.ConnectDependency(input.ID(), dgraph.AndDependency))
+	assert.NoError(t, output.ConnectDependency(step.ID(), dgraph.AndDependency))
+
+	shapes := map[string]dgraph.MermaidShape{
+		"input":  dgraph.MermaidShapeStadium,
+		"output": dgraph.MermaidShapeRhombus,
+	}
+	assert.Equals(t, d.MermaidWithOptions(dgraph.MermaidOptions[string]{
+		NodeShape: func(node dgraph.Node[string]) dgraph.MermaidShape {
+			return shapes[node.Item()]
+		},
+	}), `%% Mermaid markdown workflow
+flowchart LR
+%% Nodes
+input(["input"])
+outputs_success_223899f2{"outputs success"}
+%% Success path
+input-->steps.example
+steps.example-->outputs_success_223899f2
+%% Error path
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidCallbacksOutsideLock(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	// The callback locks the graph through the node functions, which would deadlock while rendering under the lock.
+	mermaid := d.MermaidWithOptions(dgraph.MermaidOptions[string]{
+		NodeStyle: func(node dgraph.Node[string]) string {
+			if node.ResolutionStatus() == dgraph.Resolved {
+				return "fill:#9f9"
+			}
+			return ""
+		},
+	})
+	assert.Equals(t, mermaid, `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+a-->b
+%% Error path
+%% Styles
+style a fill:#9f9
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidGroups(t *testing.T) {
+	d := dgraph.New[string]()
+	input := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("input", "input"))
+	run := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("steps.example.run", "run"))
+	outputs := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("steps.example.outputs", "outputs"))
+	assert.NoError(t, run.ConnectDependency(input.ID(), dgraph.AndDependency))
+	assert.NoError(t, outputs.ConnectDependency(run.ID(), dgraph.AndDependency))
+
+	assert.Equals(t, d.MermaidWithOptions(dgraph.MermaidOptions[string]{
+		Group: dgraph.MermaidGroupByIDPrefix[string](".", 2),
+	}), `%% Mermaid markdown workflow
+flowchart LR
+%% Groups
+subgraph group_steps_example_65c747c3["steps.example"]
+    steps.example.outputs
+    steps.example.run
+end
+%% Success path
+input-->steps.example.run
+steps.example.run-->steps.example.outputs
+%% Error path
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidAnnotations(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, b.Annotate("retries 3x", "requires <approval>"))
+
+	assert.Equals(t, d.Mermaid(), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+a-->b
+%% Error path
+%% Notes
+note_b_a62ef91f>"retries 3x<br>requires #lt;approval#gt;"] -.- b
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidFlowchartOptions(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	e := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a.failed", "e"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.OrDependency))
+	assert.NoError(t, e.ConnectDependency(a.ID(), dgraph.AndDependency))
+
+	assert.Equals(t, d.MermaidWithOptions(dgraph.MermaidOptions[string]{
+		Direction:            dgraph.MermaidDirectionTopDown,
+		Title:                "Example workflow",
+		ExcludeErrorPath:     true,
+		DependencyTypeLabels: true,
+	}), `---
+title: "Example workflow"
+---
+%% Mermaid markdown workflow
+flowchart TD
+%% Success path
+a-->|"or"|b
+%% Error path
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidErrorPathClassifier(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithErrorPathClassifier(regexp.MustCompile(`^rollback\.`).MatchString))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a.failed", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("rollback.a", "c"))
+	assert.NoError(t, a.Connect(b.ID()))
+	assert.NoError(t, a.Connect(c.ID()))
+	assert.Equals(t, d.Mermaid(), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+a-->a.failed
+%% Error path
+a-->rollback.a
+%% Mermaid end
+`)
+}
+
+func TestDirectedGraph_MermaidNodeLabels(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, a.SetAttr(dgraph.LabelMetadataKey, `Build "image"`))
+
+	assert.Equals(t, d.Mermaid(), `%% Mermaid markdown workflow
+flowchart LR
+%% Nodes
+a["Build #quot;image#quot;"]
+%% Success path
+a-->b
+%% Error path
+%% Mermaid end
+`)
+}
@@ -0,0 +1,1103 @@
+package dgraph
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (d *directedGraph[NodeType]) Namespace(prefix string) DirectedGraph[NodeType] {
+	return &namespace[NodeType]{d, d, d.config.normalizeID(prefix)}
+}
+
+// namespace is the view returned by Namespace. Functions that change the graph or take part in its execution are
+// translated to the graph, and functions that don't refer to nodes are those of the graph. Analyses, exports, and
+// copies work on a detached copy of the nodes of the view, so that their results only contain relative IDs.
+type namespace[NodeType any] struct {
+	graphWide
+	dg     *directedGraph[NodeType]
+	prefix string
+}
+
+// graphWide are the functions of DirectedGraph that concern the graph as a whole and don't refer to nodes, which a
+// namespace shares with its graph.
+type graphWide interface {
+	Generation() uint64
+	SetGate(open bool)
+	IsGateOpen() bool
+	Drain(ctx context.Context) error
+	SetDeadline(deadline time.Time)
+	Done() <-chan struct{}
+	PushStartingNodes() error
+	Reconcile() error
+	ResourcesInUse() map[string]int
+	Stats() Stats
+	Health() Health
+	Ping(maxIdle time.Duration) error
+}
+
+// absoluteID returns the ID of the node in the graph for an ID relative to the view.
+func (v *namespace[NodeType]) absoluteID(id string) string {
+	return v.dg.config.normalizeID(v.prefix + id)
+}
+
+// relativeID returns the ID relative to the view for an ID of the graph, and false if the node is not part of the
+// view.
+func (v *namespace[NodeType]) relativeID(id string) (string, bool) {
+	return strings.CutPrefix(id, v.prefix)
+}
+
+// absoluteIDs translates the keys of the map to IDs of the graph.
+func absoluteIDs[NodeType any, V any](v *namespace[NodeType], m map[string]V) map[string]V {
+	result := make(map[string]V, len(m))
+	for id, value := range m {
+		result[v.absoluteID(id)] = value
+	}
+	return result
+}
+
+// relativeIDs translates the keys of the map to IDs relative to the view, dropping the nodes outside the view.
+func relativeIDs[NodeType any, V any](v *namespace[NodeType], m map[string]V) map[string]V {
+	result := make(map[string]V, len(m))
+	for id, value := range m {
+		if relativeID, ok := v.relativeID(id); ok {
+			result[relativeID] = value
+		}
+	}
+	return result
+}
+
+// wrapNode returns the node as seen by the view.
+func (v *namespace[NodeType]) wrapNode(n Node[NodeType]) Node[NodeType] {
+	return &namespaceNode[NodeType]{n, v}
+}
+
+// wrapNodes translates the keys of the map and wraps its nodes, dropping the nodes outside the view.
+func (v *namespace[NodeType]) wrapNodes(nodes map[string]Node[NodeType]) map[string]Node[NodeType] {
+	result := make(map[string]Node[NodeType], len(nodes))
+	for nodeID, n := range nodes {
+		if relativeID, ok := v.relativeID(nodeID); ok {
+			result[relativeID] = v.wrapNode(n)
+		}
+	}
+	return result
+}
+
+// wrapNodeList wraps the nodes of the list, dropping the nodes outside the view.
+func (v *namespace[NodeType]) wrapNodeList(nodes []Node[NodeType]) []Node[NodeType] {
+	result := make([]Node[NodeType], 0, len(nodes))
+	for _, n := range nodes {
+		if _, ok := v.relativeID(n.ID()); ok {
+			result = append(result, v.wrapNode(n))
+		}
+	}
+	return result
+}
+
+// liveNodes replaces the nodes of a detached copy by the nodes of the view with the same IDs, dropping the nodes
+// that have been removed from the graph since the copy was made.
+func (v *namespace[NodeType]) liveNodes(nodes []Node[NodeType]) []Node[NodeType] {
+	if nodes == nil {
+		return nil
+	}
+	v.dg.lock.RLock()
+	defer v.dg.lock.RUnlock()
+	result := make([]Node[NodeType], 0, len(nodes))
+	for _, n := range nodes {
+		if liveNode, ok := v.dg.nodes[v.prefix+n.ID()]; ok {
+			result = append(result, v.wrapNode(liveNode))
+		}
+	}
+	return result
+}
+
+// liveNodeMap works like liveNodes for a map keyed by relative IDs.
+func (v *namespace[NodeType]) liveNodeMap(nodes map[string]Node[NodeType]) map[string]Node[NodeType] {
+	v.dg.lock.RLock()
+	defer v.dg.lock.RUnlock()
+	result := make(map[string]Node[NodeType], len(nodes))
+	for nodeID := range nodes {
+		if liveNode, ok := v.dg.nodes[v.prefix+nodeID]; ok {
+			result[nodeID] = v.wrapNode(liveNode)
+		}
+	}
+	return result
+}
+
+// checkNodes returns an ErrNodeNotFound with the absolute ID if any of the nodes with the relative IDs doesn't
+// exist, so that functions working on a detached copy report missing nodes like the rest of the view.
+func (v *namespace[NodeType]) checkNodes(ids ...string) error {
+	v.dg.lock.RLock()
+	defer v.dg.lock.RUnlock()
+	for _, id := range ids {
+		if nodeID := v.absoluteID(id); v.dg.nodes[nodeID] == nil {
+			return v.dg.nodeNotFound(nodeID)
+		}
+	}
+	return nil
+}
+
+// detached returns a copy of the nodes of the view and the connections between them, in which the IDs are
+// relative. Dependencies on nodes outside the view are dropped. Like Clone, the copy has no ready nodes, unless
+// withReady is true, in which case the nodes of the view that are ready or held back are in its ready set.
+func (v *namespace[NodeType]) detached(withReady bool) *directedGraph[NodeType] {
+	v.dg.lock.RLock()
+	// The copy is private to the view, so it neither needs a ReadySet of the graph nor a deadline timer.
+	c := v.dg.copyState(NewMemoryReadySet())
+	var readyIDs []string
+	if withReady {
+		readyIDs = v.dg.readyForProcessing.List()
+		for _, n := range v.dg.heldReady {
+			readyIDs = append(readyIDs, n.id)
+		}
+	}
+	v.dg.lock.RUnlock()
+
+	for _, nodeID := range sortedKeys(c.nodes) {
+		if _, ok := v.relativeID(nodeID); !ok {
+			c.nodes[nodeID].remove()
+		}
+	}
+	for _, nodeID := range readyIDs {
+		if n, ok := c.nodes[nodeID]; ok {
+			c.readyForProcessing.Add(n.id)
+		}
+	}
+	for _, n := range c.nodes {
+		n.dropDependenciesOutside(v.prefix)
+	}
+	c.resolutionOrder = slices.DeleteFunc(c.resolutionOrder, func(nodeID string) bool {
+		return !strings.HasPrefix(nodeID, v.prefix)
+	})
+	for name, g := range c.groups {
+		relativeName, ok := v.relativeID(name)
+		if !ok {
+			delete(c.groups, name)
+			continue
+		}
+		delete(c.groups, name)
+		g.name = relativeName
+		c.groups[relativeName] = g
+	}
+	// Shorter IDs are renamed first. The relative ID of a node is shorter than its ID, so it can only be taken by
+	// another node with a shorter ID, which has already been renamed by then.
+	nodeIDs := sortedKeys(c.nodes)
+	slices.SortStableFunc(nodeIDs, func(a, b string) int {
+		return len(a) - len(b)
+	})
+	for _, nodeID := range nodeIDs {
+		relativeID, _ := v.relativeID(nodeID)
+		c.renameNode(c.nodes[nodeID], relativeID)
+	}
+	c.changedNodes = map[string]struct{}{}
+	return c
+}
+
+// dropDependenciesOutside removes the records of the dependencies whose IDs don't start with the prefix, after
+// they were removed from a detached copy of a namespace.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) dropDependenciesOutside(prefix string) {
+	outside := func(nodeID string) bool {
+		return !strings.HasPrefix(nodeID, prefix)
+	}
+	for _, dependencies := range []map[string]DependencyType{
+		n.outstandingDependencies, n.resolvedDependencies, n.failedDependencies, n.obviatedTypes,
+	} {
+		maps.DeleteFunc(dependencies, func(nodeID string, _ DependencyType) bool {
+			return outside(nodeID)
+		})
+	}
+	if outside(n.satisfyingOrDependency) {
+		n.satisfyingOrDependency = ""
+	}
+	n.resolutionHistory = slices.DeleteFunc(n.resolutionHistory, func(r ResolvedDependency) bool {
+		return outside(r.NodeID)
+	})
+	n.satisfactionTrace = slices.DeleteFunc(n.satisfactionTrace, func(e DependencyEvent) bool {
+		return outside(e.DependencyID)
+	})
+}
+
+// viewNodeIDs returns the IDs of the nodes of the view in ascending order.
+// Caller should have appropriate mutex locked before calling.
+func (v *namespace[NodeType]) viewNodeIDs() []string {
+	var result []string
+	for nodeID := range v.dg.nodes {
+		if _, ok := v.relativeID(nodeID); ok {
+			result = append(result, nodeID)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func (v *namespace[NodeType]) AddNode(id string, item NodeType) (Node[NodeType], error) {
+	n, err := v.dg.AddNode(v.absoluteID(id), item)
+	if err != nil {
+		return nil, err
+	}
+	return v.wrapNode(n), nil
+}
+
+func (v *namespace[NodeType]) AddNodes(items map[string]NodeType) error {
+	return v.dg.AddNodes(absoluteIDs(v, items))
+}
+
+func (v *namespace[NodeType]) ConnectDependencies(toID string, dependencies map[string]DependencyType) error {
+	return v.dg.ConnectDependencies(v.absoluteID(toID), absoluteIDs(v, dependencies))
+}
+
+func (v *namespace[NodeType]) AddFailureHandlers(
+	handlers map[string]FailureHandler[NodeType],
+) (map[string]Node[NodeType], error) {
+	absoluteHandlers := make(map[string]FailureHandler[NodeType], len(handlers))
+	for primaryID, handler := range handlers {
+		absoluteHandlers[v.absoluteID(primaryID)] = FailureHandler[NodeType]{v.absoluteID(handler.ID), handler.Item}
+	}
+	result, err := v.dg.AddFailureHandlers(absoluteHandlers)
+	if err != nil {
+		return nil, err
+	}
+	return v.wrapNodes(result), nil
+}
+
+func (v *namespace[NodeType]) GetNodeByID(id string) (Node[NodeType], error) {
+	n, err := v.dg.GetNodeByID(v.absoluteID(id))
+	if err != nil {
+		return nil, err
+	}
+	return v.wrapNode(n), nil
+}
+
+func (v *namespace[NodeType]) AddGroup(name string) (Group, error) {
+	g, err := v.dg.AddGroup(v.prefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return &namespaceGroup[NodeType]{g, v}, nil
+}
+
+func (v *namespace[NodeType]) GetGroup(name string) (Group, error) {
+	g, err := v.dg.GetGroup(v.prefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return &namespaceGroup[NodeType]{g, v}, nil
+}
+
+func (v *namespace[NodeType]) ListNodes() map[string]Node[NodeType] {
+	return v.wrapNodes(v.dg.ListNodes())
+}
+
+func (v *namespace[NodeType]) Nodes() func(yield func(string, Node[NodeType]) bool) {
+	nodes := v.ListNodes()
+	return func(yield func(string, Node[NodeType]) bool) {
+		for nodeID, n := range nodes {
+			if !yield(nodeID, n) {
+				return
+			}
+		}
+	}
+}
+
+func (v *namespace[NodeType]) ListNodesWithoutInboundConnections() map[string]Node[NodeType] {
+	v.dg.lock.RLock()
+	defer v.dg.lock.RUnlock()
+	result := map[string]Node[NodeType]{}
+nextNode:
+	for _, nodeID := range v.viewNodeIDs() {
+		for fromNodeID := range v.dg.connectionsToNode[nodeID] {
+			if _, ok := v.relativeID(fromNodeID); ok {
+				continue nextNode
+			}
+		}
+		relativeID, _ := v.relativeID(nodeID)
+		result[relativeID] = v.wrapNode(v.dg.nodes[nodeID])
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) Ancestors(nodeID string) (map[string]Node[NodeType], error) {
+	if err := v.checkNodes(nodeID); err != nil {
+		return nil, err
+	}
+	result, err := v.detached(false).Ancestors(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return v.liveNodeMap(result), nil
+}
+
+func (v *namespace[NodeType]) Descendants(nodeID string) (map[string]Node[NodeType], error) {
+	if err := v.checkNodes(nodeID); err != nil {
+		return nil, err
+	}
+	result, err := v.detached(false).Descendants(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return v.liveNodeMap(result), nil
+}
+
+func (v *namespace[NodeType]) WalkBFS(start string, visit func(node Node[NodeType]) error) error {
+	if err := v.checkNodes(start); err != nil {
+		return err
+	}
+	return v.detached(false).WalkBFS(start, v.liveVisit(visit))
+}
+
+func (v *namespace[NodeType]) WalkDFS(start string, visit func(node Node[NodeType]) error) error {
+	if err := v.checkNodes(start); err != nil {
+		return err
+	}
+	return v.detached(false).WalkDFS(start, v.liveVisit(visit))
+}
+
+// liveVisit wraps the visit function of a walk over a detached copy, so that it receives the nodes of the view.
+// Nodes removed from the graph since the copy was made are skipped.
+func (v *namespace[NodeType]) liveVisit(visit func(node Node[NodeType]) error) func(node Node[NodeType]) error {
+	return func(n Node[NodeType]) error {
+		nodes := v.liveNodes([]Node[NodeType]{n})
+		if len(nodes) == 0 {
+			return nil
+		}
+		return visit(nodes[0])
+	}
+}
+
+func (v *namespace[NodeType]) StatusCounts(prefix string) StatusCounts {
+	return v.dg.StatusCounts(v.prefix + prefix)
+}
+
+func (v *namespace[NodeType]) NodeStates() map[string]NodeState {
+	return relativeIDs(v, v.dg.NodeStates())
+}
+
+func (v *namespace[NodeType]) IsComplete(prefix string) bool {
+	return v.dg.IsComplete(v.prefix + prefix)
+}
+
+func (v *namespace[NodeType]) GetEdge(fromNodeID, toNodeID string) (Edge, error) {
+	e, err := v.dg.GetEdge(v.absoluteID(fromNodeID), v.absoluteID(toNodeID))
+	if err != nil {
+		return nil, err
+	}
+	return &namespaceEdge[NodeType]{e, v}, nil
+}
+
+func (v *namespace[NodeType]) ListConnections() []Connection {
+	var result []Connection
+	for _, connection := range v.dg.ListConnections() {
+		sourceNodeID, sourceOK := v.relativeID(connection.SourceNodeID)
+		destinationNodeID, destinationOK := v.relativeID(connection.DestinationNodeID)
+		if sourceOK && destinationOK {
+			connection.SourceNodeID = sourceNodeID
+			connection.DestinationNodeID = destinationNodeID
+			result = append(result, connection)
+		}
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) Connections() func(yield func(Connection) bool) {
+	return sequence(v.ListConnections())
+}
+
+func (v *namespace[NodeType]) ListEdges() []Edge {
+	var result []Edge
+	for _, e := range v.dg.ListEdges() {
+		_, sourceOK := v.relativeID(e.SourceNodeID())
+		_, destinationOK := v.relativeID(e.DestinationNodeID())
+		if sourceOK && destinationOK {
+			result = append(result, &namespaceEdge[NodeType]{e, v})
+		}
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) Edges() func(yield func(Edge) bool) {
+	return sequence(v.ListEdges())
+}
+
+func (v *namespace[NodeType]) AdjacencyMatrix() ([][]bool, []string) {
+	return v.detached(false).AdjacencyMatrix()
+}
+
+func (v *namespace[NodeType]) EdgeSet() *EdgeSet {
+	return v.detached(false).EdgeSet()
+}
+
+func (v *namespace[NodeType]) UndirectedView() UndirectedView[NodeType] {
+	return &namespaceUndirectedView[NodeType]{v}
+}
+
+func (v *namespace[NodeType]) Namespace(prefix string) DirectedGraph[NodeType] {
+	return &namespace[NodeType]{v.dg, v.dg, v.absoluteID(prefix)}
+}
+
+func (v *namespace[NodeType]) NotifyDataReady(fromNodeID, toNodeID string) error {
+	return v.dg.NotifyDataReady(v.absoluteID(fromNodeID), v.absoluteID(toNodeID))
+}
+
+func (v *namespace[NodeType]) Diff(other DirectedGraph[NodeType]) GraphDiff {
+	return v.detached(false).Diff(other)
+}
+
+func (v *namespace[NodeType]) Merge(other DirectedGraph[NodeType], options ...MergeOption) error {
+	var c mergeConfig
+	for _, option := range options {
+		option(&c)
+	}
+	return v.dg.Merge(other, append(slices.Clone(options), WithMergeIDPrefix(v.prefix+c.idPrefix))...)
+}
+
+func (v *namespace[NodeType]) Degrees() map[string]Degree {
+	return v.detached(false).Degrees()
+}
+
+func (v *namespace[NodeType]) ValidateBoundary(prefix string, contract BoundaryContract) error {
+	outputs := make(map[string]string, len(contract.Outputs))
+	for output, parentNodeID := range contract.Outputs {
+		outputs[output] = v.prefix + parentNodeID
+	}
+	return v.dg.ValidateBoundary(v.prefix+prefix, BoundaryContract{contract.Inputs, outputs})
+}
+
+func (v *namespace[NodeType]) Reset() {
+	v.dg.lock.Lock()
+	defer v.dg.unlock()
+	for _, nodeID := range v.viewNodeIDs() {
+		v.dg.nodes[nodeID].remove()
+	}
+	for name := range v.dg.groups {
+		if _, ok := v.relativeID(name); ok {
+			delete(v.dg.groups, name)
+		}
+	}
+	v.dg.advanceGeneration()
+}
+
+func (v *namespace[NodeType]) ResetExecution() {
+	v.dg.lock.Lock()
+	defer v.dg.unlock()
+	nodeIDs := v.viewNodeIDs()
+	for _, nodeID := range nodeIDs {
+		n := v.dg.nodes[nodeID]
+		v.dg.removeReady(nodeID)
+		v.dg.releaseResources(n)
+		n.resetExecution()
+		for fromNodeID := range v.dg.connectionsToNode[nodeID] {
+			delete(v.dg.pendingData, [2]string{fromNodeID, nodeID})
+		}
+		// Dependencies outside the view that have already been resolved are applied again by Reconcile.
+		v.dg.markChanged(nodeID)
+	}
+	v.dg.resolutionOrder = slices.DeleteFunc(v.dg.resolutionOrder, func(nodeID string) bool {
+		_, ok := v.relativeID(nodeID)
+		return ok
+	})
+	v.dg.releaseHeldReady()
+	v.dg.advanceGeneration()
+	v.dg.notifyInFlightChanged()
+}
+
+func (v *namespace[NodeType]) SaveState() ([]byte, error) {
+	return v.detached(true).SaveState()
+}
+
+func (v *namespace[NodeType]) RestoreState([]byte) error {
+	return &ErrNotSupportedByNamespace{v.prefix, "RestoreState"}
+}
+
+func (v *namespace[NodeType]) Clone() DirectedGraph[NodeType] {
+	// Unlike the detached copies used internally, the clone is used like any other graph.
+	c := v.detached(false)
+	c.readyForProcessing = c.config.newReadySet()
+	c.armDeadlineTimer()
+	return c
+}
+
+func (v *namespace[NodeType]) CloneChecked() (DirectedGraph[NodeType], error) {
+	return v.detached(false).CloneChecked()
+}
+
+func (v *namespace[NodeType]) ExportJSON() ([]byte, error) {
+	return v.detached(true).ExportJSON()
+}
+
+func (v *namespace[NodeType]) ExportJSONFiltered(filter ExportFilter) ([]byte, error) {
+	return v.detached(true).ExportJSONFiltered(filter)
+}
+
+func (v *namespace[NodeType]) Compile() (*ExecutionPlan[NodeType], error) {
+	return v.detached(false).Compile()
+}
+
+func (v *namespace[NodeType]) TopologicalSort() ([]Node[NodeType], error) {
+	nodes, err := v.detached(false).TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	return v.liveNodes(nodes), nil
+}
+
+func (v *namespace[NodeType]) HasCycles() bool {
+	return v.detached(false).HasCycles()
+}
+
+func (v *namespace[NodeType]) FindCycles() [][]string {
+	return v.detached(false).FindCycles()
+}
+
+func (v *namespace[NodeType]) Condense() (DirectedGraph[[]string], map[string]string) {
+	return v.detached(false).Condense()
+}
+
+func (v *namespace[NodeType]) SuggestCycleBreaks() []CycleBreak {
+	return v.detached(false).SuggestCycleBreaks()
+}
+
+func (v *namespace[NodeType]) ShortestPath(fromNodeID, toNodeID string) ([]Node[NodeType], float64, error) {
+	if err := v.checkNodes(fromNodeID, toNodeID); err != nil {
+		return nil, 0, err
+	}
+	nodes, weight, err := v.detached(false).ShortestPath(fromNodeID, toNodeID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return v.liveNodes(nodes), weight, nil
+}
+
+func (v *namespace[NodeType]) LongestPath(fromNodeID, toNodeID string) ([]Node[NodeType], float64, error) {
+	if err := v.checkNodes(fromNodeID, toNodeID); err != nil {
+		return nil, 0, err
+	}
+	nodes, weight, err := v.detached(false).LongestPath(fromNodeID, toNodeID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return v.liveNodes(nodes), weight, nil
+}
+
+func (v *namespace[NodeType]) CriticalPath() []Node[NodeType] {
+	return v.liveNodes(v.detached(false).CriticalPath())
+}
+
+func (v *namespace[NodeType]) EstimateMakespan() (time.Duration, error) {
+	return v.detached(false).EstimateMakespan()
+}
+
+// popReady pops up to limit ready nodes of the view in priority order, or all of them if limit is negative.
+func (v *namespace[NodeType]) popReady(limit int) []ReadyNode[NodeType] {
+	v.dg.lock.Lock()
+	defer v.dg.unlock()
+	popped := v.dg.popReady(limit, v.prefix)
+	result := make([]ReadyNode[NodeType], 0, len(popped))
+	for _, n := range popped {
+		result = append(result, ReadyNode[NodeType]{
+			v.wrapNode(n), n.status, n.readyReason, relativeIDs(v, n.resolvedDependencies),
+		})
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) PopReadyNodes() map[string]ResolutionStatus {
+	result := map[string]ResolutionStatus{}
+	for _, readyNode := range v.popReady(-1) {
+		result[readyNode.Node.ID()] = readyNode.Status
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) PopReadyNodesWithReasons() map[string]ReadyNodeInfo {
+	result := map[string]ReadyNodeInfo{}
+	for _, readyNode := range v.popReady(-1) {
+		result[readyNode.Node.ID()] = ReadyNodeInfo{readyNode.Status, readyNode.Reason}
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) PopReadyNodesOrdered() []string {
+	result := []string{}
+	for _, readyNode := range v.popReady(-1) {
+		result = append(result, readyNode.Node.ID())
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) PopReadyNodeObjects() []ReadyNode[NodeType] {
+	return v.popReady(-1)
+}
+
+func (v *namespace[NodeType]) PopNReadyNodes(count int) map[string]ResolutionStatus {
+	result := map[string]ResolutionStatus{}
+	// A count of zero or less pops nothing, while popReady pops all nodes for a negative limit.
+	for _, readyNode := range v.popReady(max(count, 0)) {
+		result[readyNode.Node.ID()] = readyNode.Status
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) PopReadyNodesWhere(predicate func(Node[NodeType]) bool) map[string]ResolutionStatus {
+	return relativeIDs(v, v.dg.PopReadyNodesWhere(func(n Node[NodeType]) bool {
+		_, ok := v.relativeID(n.ID())
+		return ok && predicate(v.wrapNode(n))
+	}))
+}
+
+func (v *namespace[NodeType]) PopReadyNodesInGroup(name string) (map[string]ResolutionStatus, error) {
+	result, err := v.dg.PopReadyNodesInGroup(v.prefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return relativeIDs(v, result), nil
+}
+
+func (v *namespace[NodeType]) HasReadyNodes() bool {
+	v.dg.lock.Lock()
+	defer v.dg.unlock()
+	v.dg.checkDeadline()
+	if v.dg.gateClosed {
+		return false
+	}
+	for _, nodeID := range v.dg.readyForProcessing.List() {
+		if _, ok := v.relativeID(nodeID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *namespace[NodeType]) Cancel(nodeID string) error {
+	return v.dg.Cancel(v.absoluteID(nodeID))
+}
+
+func (v *namespace[NodeType]) CancelAll() {
+	v.dg.lock.Lock()
+	defer v.dg.unlock()
+	v.dg.cancel(v.viewNodeIDs())
+}
+
+func (v *namespace[NodeType]) Subscribe(ctx context.Context) <-chan Node[NodeType] {
+	return v.dg.subscribe(ctx, v.prefix, func(n *node[NodeType]) Node[NodeType] {
+		return v.wrapNode(n)
+	})
+}
+
+// viewListener wraps a listener, so that it is only called for the nodes of the view.
+func viewListener[NodeType any](v *namespace[NodeType], listener func(node Node[NodeType])) func(Node[NodeType]) {
+	return func(n Node[NodeType]) {
+		if _, ok := v.relativeID(n.ID()); ok {
+			listener(v.wrapNode(n))
+		}
+	}
+}
+
+// viewListenerWith is viewListener for the listeners that receive a value along with the node.
+func viewListenerWith[NodeType any, V any](
+	v *namespace[NodeType],
+	listener func(node Node[NodeType], value V),
+) func(Node[NodeType], V) {
+	return func(n Node[NodeType], value V) {
+		if _, ok := v.relativeID(n.ID()); ok {
+			listener(v.wrapNode(n), value)
+		}
+	}
+}
+
+func (v *namespace[NodeType]) OnNodeAdded(listener func(node Node[NodeType])) {
+	v.dg.OnNodeAdded(viewListener(v, listener))
+}
+
+func (v *namespace[NodeType]) OnNodeReady(listener func(node Node[NodeType])) {
+	v.dg.OnNodeReady(viewListener(v, listener))
+}
+
+func (v *namespace[NodeType]) OnNodeResolved(listener func(node Node[NodeType], status ResolutionStatus)) {
+	v.dg.OnNodeResolved(viewListenerWith(v, listener))
+}
+
+func (v *namespace[NodeType]) OnConnect(
+	listener func(from Node[NodeType], to Node[NodeType], dependencyType DependencyType),
+) {
+	v.dg.OnConnect(func(from Node[NodeType], to Node[NodeType], dependencyType DependencyType) {
+		_, fromOK := v.relativeID(from.ID())
+		_, toOK := v.relativeID(to.ID())
+		if fromOK && toOK {
+			listener(v.wrapNode(from), v.wrapNode(to), dependencyType)
+		}
+	})
+}
+
+func (v *namespace[NodeType]) OnResolved(listener func(node Node[NodeType])) {
+	v.dg.OnResolved(viewListener(v, listener))
+}
+
+func (v *namespace[NodeType]) OnUnresolvable(listener func(node Node[NodeType], cause error)) {
+	v.dg.OnUnresolvable(viewListenerWith(v, listener))
+}
+
+func (v *namespace[NodeType]) OnSkipped(listener func(node Node[NodeType], cause error)) {
+	v.dg.OnSkipped(viewListenerWith(v, listener))
+}
+
+func (v *namespace[NodeType]) MarkOutput(nodeID string) error {
+	return v.dg.MarkOutput(v.absoluteID(nodeID))
+}
+
+func (v *namespace[NodeType]) Outputs() map[string]OutputResult {
+	v.dg.lock.Lock()
+	defer v.dg.unlock()
+	v.dg.checkDeadline()
+	result, _ := v.dg.outputs(v.prefix)
+	return relativeIDs(v, result)
+}
+
+func (v *namespace[NodeType]) WaitOutputs(ctx context.Context) (map[string]OutputResult, error) {
+	result, err := v.dg.waitOutputs(ctx, v.prefix)
+	if err != nil {
+		return nil, err
+	}
+	return relativeIDs(v, result), nil
+}
+
+func (v *namespace[NodeType]) ListStale(threshold time.Duration) []StaleNode {
+	var result []StaleNode
+	for _, stale := range v.dg.ListStale(threshold) {
+		if relativeID, ok := v.relativeID(stale.NodeID); ok {
+			stale.NodeID = relativeID
+			result = append(result, stale)
+		}
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) DurationStatistics(
+	tag func(node Node[NodeType]) string,
+) map[string]DurationStatistics {
+	return v.dg.durationStatistics(v.prefix, func(n *node[NodeType]) Node[NodeType] {
+		return v.wrapNode(n)
+	}, tag)
+}
+
+func (v *namespace[NodeType]) ResolutionOrder() []string {
+	var result []string
+	for _, nodeID := range v.dg.ResolutionOrder() {
+		if relativeID, ok := v.relativeID(nodeID); ok {
+			result = append(result, relativeID)
+		}
+	}
+	return result
+}
+
+func (v *namespace[NodeType]) Partition(nodeIDs []string) (DirectedGraph[NodeType], error) {
+	if err := v.checkNodes(nodeIDs...); err != nil {
+		return nil, err
+	}
+	return v.detached(false).Partition(nodeIDs)
+}
+
+func (v *namespace[NodeType]) ResolveExternal(nodeID string, status ResolutionStatus) error {
+	return v.dg.ResolveExternal(v.absoluteID(nodeID), status)
+}
+
+func (v *namespace[NodeType]) ApplyResolutions(history []ResolutionRecord) error {
+	absoluteHistory := make([]ResolutionRecord, len(history))
+	for i, record := range history {
+		absoluteHistory[i] = ResolutionRecord{v.absoluteID(record.NodeID), record.Status}
+	}
+	return v.dg.ApplyResolutions(absoluteHistory)
+}
+
+func (v *namespace[NodeType]) Mermaid() string {
+	return v.detached(true).Mermaid()
+}
+
+func (v *namespace[NodeType]) MermaidFiltered(filter ExportFilter) string {
+	return v.detached(true).MermaidFiltered(filter)
+}
+
+func (v *namespace[NodeType]) MermaidWithOptions(options MermaidOptions[NodeType]) string {
+	return v.detached(true).MermaidWithOptions(options)
+}
+
+func (v *namespace[NodeType]) DOT() string {
+	return v.detached(true).DOT()
+}
+
+func (v *namespace[NodeType]) DOTFiltered(filter ExportFilter) string {
+	return v.detached(true).DOTFiltered(filter)
+}
+
+// namespaceNode is a node as seen by a namespace. IDs passed to and returned by it are relative to the namespace,
+// and dependencies on nodes outside the namespace are left out.
+type namespaceNode[NodeType any] struct {
+	Node[NodeType]
+	v *namespace[NodeType]
+}
+
+func (n *namespaceNode[NodeType]) ID() string {
+	relativeID, _ := n.v.relativeID(n.Node.ID())
+	return relativeID
+}
+
+func (n *namespaceNode[NodeType]) Connect(toNodeID string) error {
+	return n.Node.Connect(n.v.absoluteID(toNodeID))
+}
+
+func (n *namespaceNode[NodeType]) ConnectDependency(fromNodeID string, dependencyType DependencyType) error {
+	return n.Node.ConnectDependency(n.v.absoluteID(fromNodeID), dependencyType)
+}
+
+func (n *namespaceNode[NodeType]) ConnectWithMetadata(toNodeID string, metadata map[string]string) error {
+	return n.Node.ConnectWithMetadata(n.v.absoluteID(toNodeID), metadata)
+}
+
+func (n *namespaceNode[NodeType]) ConnectDependencyEdge(fromNodeID string, dependencyType DependencyType) (Edge, error) {
+	e, err := n.Node.ConnectDependencyEdge(n.v.absoluteID(fromNodeID), dependencyType)
+	if err != nil {
+		return nil, err
+	}
+	return &namespaceEdge[NodeType]{e, n.v}, nil
+}
+
+func (n *namespaceNode[NodeType]) ConnectDependencyIf(fromNodeID string, condition DependencyCondition[NodeType]) error {
+	return n.Node.ConnectDependencyIf(n.v.absoluteID(fromNodeID), condition)
+}
+
+func (n *namespaceNode[NodeType]) ConnectExpression(expression Expression) error {
+	expression, err := expression.validate(n.v.absoluteID)
+	if err != nil {
+		return err
+	}
+	return n.Node.ConnectExpression(expression)
+}
+
+func (n *namespaceNode[NodeType]) DependencyExpression() (Expression, bool) {
+	expression, ok := n.Node.DependencyExpression()
+	if !ok {
+		return Expression{}, false
+	}
+	for _, dependencyID := range expression.dependencyIDs() {
+		relativeID, inside := n.v.relativeID(dependencyID)
+		if !inside {
+			if expression, ok = expression.without(dependencyID); !ok {
+				return Expression{}, false
+			}
+			continue
+		}
+		expression = expression.renamed(dependencyID, relativeID)
+	}
+	return expression, true
+}
+
+func (n *namespaceNode[NodeType]) ConnectGroupDependency(groupName string, mode GroupDependencyMode) error {
+	return n.Node.ConnectGroupDependency(n.v.prefix+groupName, mode)
+}
+
+func (n *namespaceNode[NodeType]) DisconnectInbound(fromNodeID string) error {
+	return n.Node.DisconnectInbound(n.v.absoluteID(fromNodeID))
+}
+
+func (n *namespaceNode[NodeType]) ObviateDependency(fromNodeID string) error {
+	return n.Node.ObviateDependency(n.v.absoluteID(fromNodeID))
+}
+
+func (n *namespaceNode[NodeType]) DisconnectOutbound(toNodeID string) error {
+	return n.Node.DisconnectOutbound(n.v.absoluteID(toNodeID))
+}
+
+func (n *namespaceNode[NodeType]) InDegree() (int, error) {
+	inbound, err := n.ListInboundConnections()
+	return len(inbound), err
+}
+
+func (n *namespaceNode[NodeType]) OutDegree() (int, error) {
+	outbound, err := n.ListOutboundConnections()
+	return len(outbound), err
+}
+
+func (n *namespaceNode[NodeType]) ListInboundConnections() (map[string]Node[NodeType], error) {
+	nodes, err := n.Node.ListInboundConnections()
+	if err != nil {
+		return nil, err
+	}
+	return n.v.wrapNodes(nodes), nil
+}
+
+func (n *namespaceNode[NodeType]) ListOutboundConnections() (map[string]Node[NodeType], error) {
+	nodes, err := n.Node.ListOutboundConnections()
+	if err != nil {
+		return nil, err
+	}
+	return n.v.wrapNodes(nodes), nil
+}
+
+func (n *namespaceNode[NodeType]) ListInboundConnectionsOrdered() ([]Node[NodeType], error) {
+	nodes, err := n.Node.ListInboundConnectionsOrdered()
+	if err != nil {
+		return nil, err
+	}
+	return n.v.wrapNodeList(nodes), nil
+}
+
+func (n *namespaceNode[NodeType]) ListOutboundConnectionsOrdered() ([]Node[NodeType], error) {
+	nodes, err := n.Node.ListOutboundConnectionsOrdered()
+	if err != nil {
+		return nil, err
+	}
+	return n.v.wrapNodeList(nodes), nil
+}
+
+func (n *namespaceNode[NodeType]) Rename(newID string) error {
+	return n.Node.Rename(n.v.absoluteID(newID))
+}
+
+func (n *namespaceNode[NodeType]) OutstandingDependencies() map[string]DependencyType {
+	return relativeIDs(n.v, n.Node.OutstandingDependencies())
+}
+
+func (n *namespaceNode[NodeType]) ResolvedDependencies() map[string]DependencyType {
+	return relativeIDs(n.v, n.Node.ResolvedDependencies())
+}
+
+func (n *namespaceNode[NodeType]) ResolvedDependencyHistory() []ResolvedDependency {
+	var result []ResolvedDependency
+	for _, resolved := range n.Node.ResolvedDependencyHistory() {
+		if relativeID, ok := n.v.relativeID(resolved.NodeID); ok {
+			resolved.NodeID = relativeID
+			result = append(result, resolved)
+		}
+	}
+	return result
+}
+
+func (n *namespaceNode[NodeType]) SatisfyingOrDependency() (string, bool) {
+	dependencyID, ok := n.Node.SatisfyingOrDependency()
+	if !ok {
+		return "", false
+	}
+	return n.v.relativeID(dependencyID)
+}
+
+func (n *namespaceNode[NodeType]) ReadinessCondition() ReadinessCondition {
+	condition, _ := n.v.relativeCondition(n.Node.ReadinessCondition(), true)
+	return condition
+}
+
+// relativeCondition translates the dependencies of the condition to relative IDs and leaves out the dependencies
+// outside the view, as well as the subexpressions left without operands. The Satisfied fields are kept, since
+// the dependencies outside the view still affect the readiness of the node.
+func (v *namespace[NodeType]) relativeCondition(c ReadinessCondition, root bool) (ReadinessCondition, bool) {
+	if c.Operator == ConditionDependency {
+		relativeID, ok := v.relativeID(c.DependencyID)
+		c.DependencyID = relativeID
+		return c, ok
+	}
+	operands := c.Operands
+	c.Operands = nil
+	for _, operand := range operands {
+		if operand, ok := v.relativeCondition(operand, false); ok {
+			c.Operands = append(c.Operands, operand)
+		}
+	}
+	return c, root || len(c.Operands) > 0
+}
+
+func (n *namespaceNode[NodeType]) SatisfactionTrace() []DependencyEvent {
+	var result []DependencyEvent
+	for _, event := range n.Node.SatisfactionTrace() {
+		if relativeID, ok := n.v.relativeID(event.DependencyID); ok {
+			event.DependencyID = relativeID
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func (n *namespaceNode[NodeType]) DependencyReport() DependencyReport {
+	report := n.Node.DependencyReport()
+	return DependencyReport{
+		Resolved:    relativeIDs(n.v, report.Resolved),
+		Outstanding: relativeIDs(n.v, report.Outstanding),
+		Obviated:    relativeIDs(n.v, report.Obviated),
+	}
+}
+
+// namespaceEdge is an edge as seen by a namespace.
+type namespaceEdge[NodeType any] struct {
+	Edge
+	v *namespace[NodeType]
+}
+
+func (e *namespaceEdge[NodeType]) SourceNodeID() string {
+	relativeID, _ := e.v.relativeID(e.Edge.SourceNodeID())
+	return relativeID
+}
+
+func (e *namespaceEdge[NodeType]) DestinationNodeID() string {
+	relativeID, _ := e.v.relativeID(e.Edge.DestinationNodeID())
+	return relativeID
+}
+
+// namespaceGroup is a group as seen by a namespace. Its name and the IDs of its members are relative.
+type namespaceGroup[NodeType any] struct {
+	Group
+	v *namespace[NodeType]
+}
+
+func (g *namespaceGroup[NodeType]) Name() string {
+	name, _ := g.v.relativeID(g.Group.Name())
+	return name
+}
+
+func (g *namespaceGroup[NodeType]) AddMember(nodeID string) error {
+	return g.Group.AddMember(g.v.absoluteID(nodeID))
+}
+
+func (g *namespaceGroup[NodeType]) RemoveMember(nodeID string) error {
+	return g.Group.RemoveMember(g.v.absoluteID(nodeID))
+}
+
+func (g *namespaceGroup[NodeType]) ListMembers() []string {
+	var result []string
+	for _, nodeID := range g.Group.ListMembers() {
+		if relativeID, ok := g.v.relativeID(nodeID); ok {
+			result = append(result, relativeID)
+		}
+	}
+	return result
+}
+
+// namespaceUndirectedView is the UndirectedView of a namespace, which works on a detached copy of the namespace.
+type namespaceUndirectedView[NodeType any] struct {
+	v *namespace[NodeType]
+}
+
+func (u *namespaceUndirectedView[NodeType]) Neighbors(nodeID string) (map[string]Node[NodeType], error) {
+	if err := u.v.checkNodes(nodeID); err != nil {
+		return nil, err
+	}
+	neighbors, err := u.v.detached(false).UndirectedView().Neighbors(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return u.v.liveNodeMap(neighbors), nil
+}
+
+func (u *namespaceUndirectedView[NodeType]) Degree(nodeID string) (int, error) {
+	if err := u.v.checkNodes(nodeID); err != nil {
+		return 0, err
+	}
+	return u.v.detached(false).UndirectedView().Degree(nodeID)
+}
+
+func (u *namespaceUndirectedView[NodeType]) Adjacent(nodeID1, nodeID2 string) bool {
+	return u.v.detached(false).UndirectedView().Adjacent(nodeID1, nodeID2)
+}
+
+func (u *namespaceUndirectedView[NodeType]) ConnectedComponents() [][]string {
+	return u.v.detached(false).UndirectedView().ConnectedComponents()
+}
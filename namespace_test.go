@@ -0,0 +1,120 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Namespace(t *testing.T) {
+	d := dgraph.New[string]()
+	_, err := d.AddNode("start", "start")
+	assert.NoError(t, err)
+	steps := d.Namespace("steps.")
+	child := steps.Namespace("child.")
+
+	// Changes through the view use relative IDs and are applied to the graph.
+	assert.NoError(t, child.AddNodes(map[string]string{"input": "input", "run": "run"}))
+	run := assert.NoErrorR[dgraph.Node[string]](t)(child.GetNodeByID("run"))
+	assert.Equals(t, run.ID(), "run")
+	assert.NoError(t, run.ConnectDependency("input", dgraph.AndDependency))
+	assert.Equals(t, run.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"input": dgraph.AndDependency,
+	})
+	absoluteRun := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("steps.child.run"))
+	assert.Equals(t, absoluteRun.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"steps.child.input": dgraph.AndDependency,
+	})
+	assert.Equals(t, len(child.ListNodes()), 2)
+	assert.Equals(t, len(steps.ListNodes()), 2)
+	_, err = child.GetNodeByID("start")
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+
+	// Only the ready nodes of the view are popped.
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, child.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"input": dgraph.Waiting})
+	input := assert.NoErrorR[dgraph.Node[string]](t)(child.GetNodeByID("input"))
+	assert.NoError(t, input.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, child.PopReadyNodesOrdered(), []string{"run"})
+	assert.Equals(t, child.IsComplete(""), false)
+	assert.NoError(t, run.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, child.IsComplete(""), true)
+	assert.Equals(t, child.ResolutionOrder(), []string{"input", "run"})
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"start"})
+}
+
+func TestDirectedGraph_Namespace_OutsideDependencies(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoError(t, d.AddNodes(map[string]string{"start": "start", "sub.a": "a", "sub.b": "b"}))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("sub.a"))
+	assert.NoError(t, a.ConnectDependency("start", dgraph.AndDependency))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("sub.b"))
+	assert.NoError(t, b.ConnectDependency("sub.a", dgraph.AndDependency))
+	sub := d.Namespace("sub.")
+
+	// The connection from outside the view is hidden, but still holds back the node.
+	connections := sub.ListConnections()
+	assert.Equals(t, len(connections), 1)
+	assert.Equals(t, connections[0].SourceNodeID, "a")
+	assert.Equals(t, connections[0].DestinationNodeID, "b")
+	assert.Equals(t, len(sub.ListNodesWithoutInboundConnections()), 1)
+	subA := assert.NoErrorR[dgraph.Node[string]](t)(sub.GetNodeByID("a"))
+	assert.Equals(t, subA.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, sub.HasReadyNodes(), false)
+	start := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("start"))
+	assert.NoError(t, start.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, sub.PopReadyNodesOrdered(), []string{"a"})
+
+	// Copies and analyses only contain the view, with relative IDs.
+	clone := sub.Clone()
+	assert.Equals(t, len(clone.ListNodes()), 2)
+	cloneB := assert.NoErrorR[dgraph.Node[string]](t)(clone.GetNodeByID("b"))
+	assert.Equals(t, cloneB.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.AndDependency,
+	})
+	sorted := assert.NoErrorR[[]dgraph.Node[string]](t)(sub.TopologicalSort())
+	assert.Equals(t, len(sorted), 2)
+	assert.Equals(t, sorted[0].ID(), "a")
+	assert.Equals(t, sorted[1].ID(), "b")
+	_, err := sub.Ancestors("start")
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+	assert.Equals(t, err.(*dgraph.ErrNodeNotFound).NodeID, "sub.start")
+
+	// Merging into the view adds the prefix.
+	other := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(other.AddNode("c", "c"))
+	assert.NoError(t, sub.Merge(other))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("sub.c"))
+
+	assert.InstanceOf[*dgraph.ErrNotSupportedByNamespace](t, sub.RestoreState(nil))
+}
+
+func TestDirectedGraph_Namespace_ReadySet(t *testing.T) {
+	readySets := 0
+	d := dgraph.New[string](dgraph.WithReadySet(func() dgraph.ReadySet {
+		readySets++
+		return dgraph.NewMemoryReadySet()
+	}))
+	assert.NoError(t, d.AddNodes(map[string]string{"sub.a": "a", "sub.b": "b"}))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("sub.b"))
+	assert.NoError(t, b.ConnectDependency("sub.a", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	sub := d.Namespace("sub.")
+
+	// Analyses and exports of the view don't create ready sets.
+	assert.Equals(t, sub.HasCycles(), false)
+	assert.Equals(t, len(assert.NoErrorR[[]dgraph.Node[string]](t)(sub.TopologicalSort())), 2)
+	assert.Contains(t, string(assert.NoErrorR[[]byte](t)(sub.ExportJSON())), `"ready_nodes":["a"]`)
+	assert.Contains(t, sub.Mermaid(), "a")
+	assert.Equals(t, readySets, 1)
+	// A clone is an independent graph, so it gets its own.
+	clone := sub.Clone()
+	assert.Equals(t, readySets, 2)
+	assert.NoError(t, clone.PushStartingNodes())
+	assert.Equals(t, clone.PopReadyNodesOrdered(), []string{"a"})
+
+	assert.Equals(t, len(sub.PopNReadyNodes(-1)), 0)
+	assert.Equals(t, sub.PopNReadyNodes(1), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting})
+}
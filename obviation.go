@@ -0,0 +1,111 @@
+package dgraph
+
+import "slices"
+
+// WithOptionalObviation makes the graph resolve nodes that can no longer be affected by any of their dependencies.
+// When a node becomes Unresolvable, each of its dependents that is still Waiting, has not been popped, and only
+// has OptionalDependency connections from Unresolvable nodes is resolved as Unresolvable as well, with an
+// ErrNodeObviated cause, and removed from the ready set. This cascades through the dependents of the obviated
+// nodes, so no node of an optional subtree hanging off a failed node is left Waiting forever.
+func WithOptionalObviation() Option {
+	return func(c *config) {
+		c.optionalObviation = true
+	}
+}
+
+// obviateOptionalDependents resolves the dependents of the unresolvable node that can no longer be affected by any
+// of their dependencies.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) obviateOptionalDependents() error {
+	var obviated []string
+	for toNodeID := range n.dg.connectionsFromNode[n.id] {
+		if n.dg.isObviated(toNodeID) {
+			obviated = append(obviated, toNodeID)
+		}
+	}
+	slices.Sort(obviated)
+	for _, toNodeID := range obviated {
+		toNode := n.dg.nodes[toNodeID]
+		// An earlier obviation may have already resolved the node.
+		if toNode.status != Waiting {
+			continue
+		}
+		toNode.unresolvableCause = &ErrNodeObviated{toNodeID}
+		n.dg.removeReady(toNodeID)
+		if err := toNode.resolveNode(Unresolvable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isObviated returns true if the node is waiting, has not been popped, and only has optional dependencies that are
+// unresolvable.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) isObviated(nodeID string) bool {
+	n := d.nodes[nodeID]
+	if n.status != Waiting || !n.poppedAt.IsZero() || len(d.connectionsToNode[nodeID]) == 0 {
+		return false
+	}
+	for fromNodeID := range d.connectionsToNode[nodeID] {
+		// Optional dependencies become obviated once the node is ready.
+		dependencyType := n.dependencyType(fromNodeID)
+		if dependencyType != OptionalDependency && dependencyType != ObviatedDependency {
+			return false
+		}
+		if d.nodes[fromNodeID].status != Unresolvable {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node[NodeType]) ObviateDependency(fromNodeID string) error {
+	fromNodeID = n.dg.config.normalizeID(fromNodeID)
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	if _, ok := n.dg.nodes[fromNodeID]; !ok {
+		return n.dg.nodeNotFound(fromNodeID)
+	}
+	if _, ok := n.dg.connectionsToNode[n.id][fromNodeID]; !ok {
+		return &ErrConnectionDoesNotExist{fromNodeID, n.id}
+	}
+	dependencyType, outstanding := n.outstandingDependencies[fromNodeID]
+	if !outstanding {
+		return &ErrDependencyAlreadyResolved{n.id, fromNodeID}
+	}
+	if dependencyType == ObviatedDependency {
+		return nil
+	}
+	n.outstandingDependencies[fromNodeID] = ObviatedDependency
+	if dependencyType == ExpressionDependency {
+		// The dependency is removed from the expression, so ResetExecution keeps it obviated.
+		n.removeFromExpression(fromNodeID)
+	} else {
+		if n.obviatedTypes == nil {
+			n.obviatedTypes = map[string]DependencyType{}
+		}
+		n.obviatedTypes[fromNodeID] = dependencyType
+	}
+	n.dg.countDependency(dependencyType, DependencyObviated)
+	n.dg.advanceGeneration()
+	return n.dependencyObviated(dependencyType)
+}
+
+// dependencyObviated re-evaluates the readiness of the node after a dependency of the specified type was obviated.
+// If it was the last outstanding OR dependency and all others are unresolvable, the node becomes unresolvable.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) dependencyObviated(dependencyType DependencyType) error {
+	if n.ready || n.status != Waiting {
+		return nil
+	}
+	if dependencyType == ExpressionDependency {
+		if err := n.reconcileExpression(); err != nil {
+			return err
+		}
+	}
+	return n.markReadyIfSatisfied()
+}
@@ -0,0 +1,105 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestWithOptionalObviation(t *testing.T) {
+	for _, obviate := range []bool{false, true} {
+		var options []dgraph.Option
+		if obviate {
+			options = append(options, dgraph.WithOptionalObviation())
+		}
+		d := dgraph.New[string](options...)
+		nodes := map[string]dgraph.Node[string]{}
+		for _, id := range []string{"failing", "optional", "follower", "mixed", "other"} {
+			nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+		}
+		assert.NoError(t, nodes["optional"].ConnectDependency("failing", dgraph.OptionalDependency))
+		assert.NoError(t, nodes["follower"].ConnectDependency("optional", dgraph.AndDependency))
+		assert.NoError(t, nodes["mixed"].ConnectDependency("failing", dgraph.OptionalDependency))
+		assert.NoError(t, nodes["mixed"].ConnectDependency("other", dgraph.OptionalDependency))
+		// Optional-only nodes are ready on start, but are not obviated once they are popped.
+		d.SetGate(false)
+		assert.NoError(t, d.PushStartingNodes())
+
+		assert.NoError(t, nodes["failing"].ResolveNode(dgraph.Unresolvable))
+		if !obviate {
+			assert.Equals(t, nodes["optional"].ResolutionStatus(), dgraph.Waiting)
+			assert.Equals(t, nodes["follower"].ResolutionStatus(), dgraph.Waiting)
+			continue
+		}
+		assert.Equals(t, nodes["optional"].ResolutionStatus(), dgraph.Unresolvable)
+		var obviatedErr *dgraph.ErrNodeObviated
+		assert.Equals(t, errors.As(nodes["optional"].UnresolvableCause(), &obviatedErr), true)
+		assert.Equals(t, nodes["follower"].ResolutionStatus(), dgraph.Unresolvable)
+		assert.Equals(t, nodes["mixed"].ResolutionStatus(), dgraph.Waiting)
+
+		d.SetGate(true)
+		ready := d.PopReadyNodes()
+		assert.MapContainsKey(t, "mixed", ready)
+		assert.Equals(t, len(ready), 4)
+		assert.Equals(t, ready["optional"], "")
+		assert.Equals(t, ready["follower"], dgraph.Unresolvable)
+		assert.NoError(t, nodes["other"].ResolveNode(dgraph.Unresolvable))
+		// Popped nodes are being processed, so they are left to the caller.
+		assert.Equals(t, nodes["mixed"].ResolutionStatus(), dgraph.Waiting)
+	}
+}
+
+func TestNode_ObviateDependency(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "disabled", "x"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, d.ConnectDependencies("x", map[string]dgraph.DependencyType{
+		"a":        dgraph.AndDependency,
+		"disabled": dgraph.AndDependency,
+	}))
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("x"))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, x.IsReady(), false)
+
+	// The node becomes ready without calling Reconcile.
+	assert.NoError(t, x.ObviateDependency("disabled"))
+	assert.Equals(t, x.IsReady(), true)
+	assert.Equals(t, x.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"disabled": dgraph.ObviatedDependency,
+	})
+	assert.NoError(t, x.ObviateDependency("disabled"))
+	assert.InstanceOf[*dgraph.ErrDependencyAlreadyResolved](t, x.ObviateDependency("a"))
+	assert.InstanceOf[*dgraph.ErrConnectionDoesNotExist](t, a.ObviateDependency("x"))
+
+	// ResetExecution restores the original type.
+	d.ResetExecution()
+	assert.Equals(t, x.OutstandingDependencies()["disabled"], dgraph.AndDependency)
+}
+
+func TestNode_ObviateDependency_LastOr(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "x"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, d.ConnectDependencies("x", map[string]dgraph.DependencyType{
+		"a": dgraph.OrDependency,
+		"b": dgraph.OrDependency,
+	}))
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("x"))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, x.ResolutionStatus(), dgraph.Waiting)
+
+	// No OR dependency is left that could satisfy the node.
+	assert.NoError(t, x.ObviateDependency("b"))
+	assert.Equals(t, x.ResolutionStatus(), dgraph.Unresolvable)
+	assert.InstanceOf[*dgraph.ErrDependencyUnresolvable](t, x.UnresolvableCause())
+}
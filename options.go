@@ -0,0 +1,102 @@
+package dgraph
+
+import (
+	"regexp"
+	"time"
+)
+
+// Option configures a DirectedGraph on creation. Options are passed to New.
+type Option func(c *config)
+
+type config struct {
+	readyNodeConnectionPolicy ReadyNodeConnectionPolicy
+	idNormalizer              func(id string) string
+	idPattern                 *regexp.Regexp
+	maxReadyNodes             int
+	criticalPathPriority      bool
+	nodeWeight                func(nodeID string) float64
+	clock                     func() time.Time
+	preserveConnectionOrder   bool
+	optionalObviation         bool
+	resourceCapacities        map[string]int
+	seeded                    bool
+	seed                      uint64
+	dataReadyCheck            func(fromNodeID, toNodeID string) bool
+	selfLoops                 bool
+	errorPathClassifier       func(nodeID string) bool
+	readySetFactory           func() ReadySet
+}
+
+func newConfig(options []Option) config {
+	c := config{
+		readyNodeConnectionPolicy: ReadyNodeConnectionAllow,
+		clock:                     time.Now,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+// ReadyNodeConnectionPolicy determines what happens when a required dependency is connected to a node that is
+// in the ready set, but has not been popped yet.
+type ReadyNodeConnectionPolicy string
+
+const (
+	// ReadyNodeConnectionAllow adds the dependency and leaves the node in the ready set. This is the default.
+	ReadyNodeConnectionAllow ReadyNodeConnectionPolicy = "allow"
+	// ReadyNodeConnectionRollback adds the dependency and removes the node from the ready set until the new
+	// dependency is resolved.
+	ReadyNodeConnectionRollback ReadyNodeConnectionPolicy = "rollback"
+	// ReadyNodeConnectionReject refuses the connection with an ErrNodeAlreadyReady.
+	ReadyNodeConnectionReject ReadyNodeConnectionPolicy = "reject"
+)
+
+// WithReadyNodeConnectionPolicy sets the policy applied when a required dependency is connected to a node that is
+// ready, but has not been popped yet.
+func WithReadyNodeConnectionPolicy(policy ReadyNodeConnectionPolicy) Option {
+	return func(c *config) {
+		c.readyNodeConnectionPolicy = policy
+	}
+}
+
+// WithIDNormalizer installs a function that is applied to every node ID passed to the graph, both when nodes are
+// added and when they are looked up or connected. This makes lookups consistent regardless of how the ID was
+// formatted, for example by lowercasing and trimming the ID.
+func WithIDNormalizer(normalizer func(id string) string) Option {
+	return func(c *config) {
+		c.idNormalizer = normalizer
+	}
+}
+
+// WithIDPattern enforces that the IDs of all added nodes match the pattern. Nodes with other IDs are rejected with
+// an ErrInvalidNodeID. The pattern is applied after ID normalization. MermaidSafeIDPattern can be used to ensure
+// that IDs are rendered as-is in Mermaid diagrams.
+func WithIDPattern(pattern *regexp.Regexp) Option {
+	return func(c *config) {
+		c.idPattern = pattern
+	}
+}
+
+// WithClock replaces the clock used for all timestamps recorded by the graph. This is mainly useful for testing.
+func WithClock(clock func() time.Time) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+func (c config) normalizeID(id string) string {
+	if c.idNormalizer == nil {
+		return id
+	}
+	return c.idNormalizer(id)
+}
+
+// WithMaxReadyNodes caps the number of nodes exposed as ready at once. Nodes that become ready while the cap is
+// reached are held back, and are released in the order in which they became ready when PopReadyNodes() frees
+// slots. Starting nodes are held back in the order of their IDs. A value of 0 or less means no limit.
+func WithMaxReadyNodes(maxReadyNodes int) Option {
+	return func(c *config) {
+		c.maxReadyNodes = maxReadyNodes
+	}
+}
@@ -0,0 +1,93 @@
+package dgraph
+
+import (
+	"cmp"
+	"slices"
+)
+
+// WithConnectionOrder preserves the order in which connections are added. The ordered connection lists of nodes,
+// the Mermaid output, and the JSON export then list connections in insertion order instead of sorting them by ID.
+// Reconnecting a removed connection moves it to the end.
+func WithConnectionOrder() Option {
+	return func(c *config) {
+		c.preserveConnectionOrder = true
+	}
+}
+
+// recordConnection remembers the position of a new connection if connection order is preserved.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) recordConnection(fromID, toID string) {
+	if !d.config.preserveConnectionOrder {
+		return
+	}
+	d.connectionSequence[[2]string{fromID, toID}] = d.nextConnectionSequence
+	d.nextConnectionSequence++
+}
+
+// forgetConnection removes the position, metadata, weight, condition, and pending data of a removed connection,
+// and removes the source from the expression of the destination.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) forgetConnection(fromID, toID string) {
+	if toNode, ok := d.nodes[toID]; ok {
+		toNode.removeFromExpression(fromID)
+	}
+	delete(d.connectionSequence, [2]string{fromID, toID})
+	delete(d.connectionMetadata, [2]string{fromID, toID})
+	delete(d.connectionWeights, [2]string{fromID, toID})
+	delete(d.connectionConditions, [2]string{fromID, toID})
+	delete(d.pendingData, [2]string{fromID, toID})
+}
+
+// sortConnections sorts the connections, given as source and destination ID pairs, in insertion order if connection
+// order is preserved, and by source and destination ID otherwise.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) sortConnections(connections [][2]string) {
+	if d.config.preserveConnectionOrder {
+		slices.SortFunc(connections, func(a, b [2]string) int {
+			return cmp.Compare(d.connectionSequence[a], d.connectionSequence[b])
+		})
+		return
+	}
+	slices.SortFunc(connections, func(a, b [2]string) int {
+		if c := cmp.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a[1], b[1])
+	})
+}
+
+func (n *node[NodeType]) ListInboundConnectionsOrdered() ([]Node[NodeType], error) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	if n.deleted {
+		return nil, &ErrNodeDeleted{n.id}
+	}
+	connections := make([][2]string, 0, len(n.dg.connectionsToNode[n.id]))
+	for fromNodeID := range n.dg.connectionsToNode[n.id] {
+		connections = append(connections, [2]string{fromNodeID, n.id})
+	}
+	n.dg.sortConnections(connections)
+	result := make([]Node[NodeType], len(connections))
+	for i, connection := range connections {
+		result[i] = n.dg.nodes[connection[0]]
+	}
+	return result, nil
+}
+
+func (n *node[NodeType]) ListOutboundConnectionsOrdered() ([]Node[NodeType], error) {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	if n.deleted {
+		return nil, &ErrNodeDeleted{n.id}
+	}
+	connections := make([][2]string, 0, len(n.dg.connectionsFromNode[n.id]))
+	for toNodeID := range n.dg.connectionsFromNode[n.id] {
+		connections = append(connections, [2]string{n.id, toNodeID})
+	}
+	n.dg.sortConnections(connections)
+	result := make([]Node[NodeType], len(connections))
+	for i, connection := range connections {
+		result[i] = n.dg.nodes[connection[1]]
+	}
+	return result, nil
+}
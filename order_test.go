@@ -0,0 +1,64 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func nodeIDs[NodeType any](nodes []dgraph.Node[NodeType]) []string {
+	result := make([]string, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.ID()
+	}
+	return result
+}
+
+func TestWithConnectionOrder(t *testing.T) {
+	for _, preserve := range []bool{false, true} {
+		var options []dgraph.Option
+		if preserve {
+			options = append(options, dgraph.WithConnectionOrder())
+		}
+		d := dgraph.New[string](options...)
+		for _, id := range []string{"target", "c", "a", "b"} {
+			_, err := d.AddNode(id, id)
+			assert.NoError(t, err)
+		}
+		target := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("target"))
+		for _, id := range []string{"c", "a", "b"} {
+			assert.NoError(t, target.ConnectDependency(id, dgraph.AndDependency))
+		}
+		// Reconnecting moves the connection to the end.
+		assert.NoError(t, target.DisconnectInbound("a"))
+		assert.NoError(t, target.ConnectDependency("a", dgraph.AndDependency))
+
+		inbound := assert.NoErrorR[[]dgraph.Node[string]](t)(target.ListInboundConnectionsOrdered())
+		a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+		outbound := assert.NoErrorR[[]dgraph.Node[string]](t)(a.ListOutboundConnectionsOrdered())
+		assert.Equals(t, nodeIDs(outbound), []string{"target"})
+		if preserve {
+			assert.Equals(t, nodeIDs(inbound), []string{"c", "b", "a"})
+			assert.Equals(t, d.Mermaid(), `%% Mermaid markdown workflow
+flowchart LR
+%% Success path
+c-->target
+b-->target
+a-->target
+%% Error path
+%% Mermaid end
+`)
+		} else {
+			assert.Equals(t, nodeIDs(inbound), []string{"a", "b", "c"})
+		}
+
+		// The order survives a JSON round trip.
+		d2 := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(
+			dgraph.ImportJSON[string](assert.NoErrorR[[]byte](t)(d.ExportJSON()), options...),
+		)
+		target2 := assert.NoErrorR[dgraph.Node[string]](t)(d2.GetNodeByID("target"))
+		inbound2 := assert.NoErrorR[[]dgraph.Node[string]](t)(target2.ListInboundConnectionsOrdered())
+		assert.Equals(t, nodeIDs(inbound2), nodeIDs(inbound))
+	}
+}
@@ -0,0 +1,111 @@
+package dgraph
+
+import (
+	"context"
+	"strings"
+)
+
+// OutputResult is the state of an output node returned by Outputs and WaitOutputs.
+type OutputResult struct {
+	Status ResolutionStatus
+	// Result is the payload passed to ResolveNodeWithResult, if any.
+	Result any
+}
+
+func (d *directedGraph[NodeType]) MarkOutput(nodeID string) error {
+	nodeID = d.config.normalizeID(nodeID)
+	d.lock.Lock()
+	defer d.unlock()
+	n, ok := d.nodes[nodeID]
+	if !ok {
+		return d.nodeNotFound(nodeID)
+	}
+	n.output = true
+	d.advanceGeneration()
+	return nil
+}
+
+func (d *directedGraph[NodeType]) Outputs() map[string]OutputResult {
+	d.lock.Lock()
+	defer d.unlock()
+	d.checkDeadline()
+	result, _ := d.outputs("")
+	return result
+}
+
+func (d *directedGraph[NodeType]) WaitOutputs(ctx context.Context) (map[string]OutputResult, error) {
+	return d.waitOutputs(ctx, "")
+}
+
+// waitOutputs works like WaitOutputs, but only waits for the output nodes whose IDs start with the prefix.
+func (d *directedGraph[NodeType]) waitOutputs(ctx context.Context, prefix string) (map[string]OutputResult, error) {
+	for {
+		d.lock.Lock()
+		d.checkDeadline()
+		result, done := d.outputs(prefix)
+		if done {
+			d.unlock()
+			return result, nil
+		}
+		if d.outputResolved == nil {
+			d.outputResolved = make(chan struct{})
+		}
+		outputResolved := d.outputResolved
+		runDone := d.done
+		d.unlock()
+		select {
+		case <-outputResolved:
+		case <-runDone:
+			// The deadline resolves all outputs, so the next iteration returns.
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// outputs returns the state of the output nodes whose IDs start with the prefix, and whether all of them are
+// resolved.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) outputs(prefix string) (map[string]OutputResult, bool) {
+	result := map[string]OutputResult{}
+	done := true
+	for nodeID, n := range d.nodes {
+		if !n.output || !strings.HasPrefix(nodeID, prefix) {
+			continue
+		}
+		result[nodeID] = OutputResult{n.status, n.result}
+		if n.status == Waiting {
+			done = false
+		}
+	}
+	return result, done
+}
+
+// notifyOutputResolved wakes up the callers of WaitOutputs.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) notifyOutputResolved() {
+	if d.outputResolved != nil {
+		close(d.outputResolved)
+		d.outputResolved = nil
+	}
+}
+
+func (n *node[NodeType]) ResolveNodeWithResult(status ResolutionStatus, result any) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	n.dg.checkDeadline()
+	wasWaiting := n.status == Waiting
+	if err := n.resolveNode(status); err != nil {
+		return err
+	}
+	if wasWaiting {
+		n.result = result
+	}
+	return nil
+}
+
+func (n *node[NodeType]) Result() any {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.result
+}
@@ -0,0 +1,83 @@
+package dgraph_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Outputs(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.MarkOutput("a"))
+	assert.NoError(t, d.MarkOutput("c"))
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, d.MarkOutput("d"))
+
+	assert.Equals(t, d.Outputs(), map[string]dgraph.OutputResult{
+		"a": {Status: dgraph.Waiting},
+		"c": {Status: dgraph.Waiting},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	type waitResult struct {
+		outputs map[string]dgraph.OutputResult
+		err     error
+	}
+	waitResults := make(chan waitResult)
+	go func() {
+		outputs, err := d.WaitOutputs(ctx)
+		waitResults <- waitResult{outputs, err}
+	}()
+
+	assert.NoError(t, a.ResolveNodeWithResult(dgraph.Resolved, 42))
+	assert.NoError(t, b.ResolveNode(dgraph.Unresolvable))
+	result := <-waitResults
+	assert.NoError(t, result.err)
+	assert.Equals(t, result.outputs, map[string]dgraph.OutputResult{
+		"a": {Status: dgraph.Resolved, Result: 42},
+		"c": {Status: dgraph.Unresolvable},
+	})
+}
+
+func TestDirectedGraph_WaitOutputs_Cancel(t *testing.T) {
+	d := dgraph.New[string]()
+	_, err := d.AddNode("a", "a")
+	assert.NoError(t, err)
+	assert.NoError(t, d.MarkOutput("a"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = d.WaitOutputs(ctx)
+	assert.Equals(t, err, context.Canceled)
+}
+
+func TestDirectedGraph_WaitOutputs_Remove(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, d.MarkOutput("a"))
+	assert.NoError(t, d.MarkOutput("b"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var outputs map[string]dgraph.OutputResult
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		outputs, err = d.WaitOutputs(ctx)
+	}()
+	// Removing the only waiting output leaves only final outputs, which wakes up WaitOutputs.
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, b.Remove())
+	<-done
+	assert.NoError(t, err)
+	assert.Equals(t, outputs, map[string]dgraph.OutputResult{"a": {Status: dgraph.Resolved}})
+}
@@ -0,0 +1,92 @@
+package dgraph
+
+import (
+	"maps"
+	"slices"
+)
+
+func (d *directedGraph[NodeType]) Partition(nodeIDs []string) (DirectedGraph[NodeType], error) {
+	d.lock.Lock()
+	defer d.unlock()
+	members := make(map[string]struct{}, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		nodeID = d.config.normalizeID(nodeID)
+		if _, ok := d.nodes[nodeID]; !ok {
+			return nil, d.nodeNotFound(nodeID)
+		}
+		members[nodeID] = struct{}{}
+	}
+	partition := newDirectedGraph[NodeType](d.config)
+	sortedMembers := make([]string, 0, len(members))
+	for nodeID := range members {
+		sortedMembers = append(sortedMembers, nodeID)
+	}
+	slices.Sort(sortedMembers)
+	for _, nodeID := range sortedMembers {
+		if _, err := partition.addNode(nodeID, d.nodes[nodeID].item); err != nil {
+			return nil, err
+		}
+	}
+	// Dependencies from outside the partition are replaced by stubs with the same IDs. Connections to nodes
+	// outside the partition are dropped, since the partition containing them has a stub for this side.
+	for _, nodeID := range sortedMembers {
+		n := d.nodes[nodeID]
+		for _, fromNodeID := range d.sortedInbound(nodeID) {
+			if _, ok := members[fromNodeID]; !ok {
+				if _, ok := partition.nodes[fromNodeID]; !ok {
+					stub, err := partition.addNode(fromNodeID, *new(NodeType))
+					if err != nil {
+						return nil, err
+					}
+					stub.external = true
+				}
+			}
+			if err := partition.connect(fromNodeID, nodeID, n.declaredDependencyType(fromNodeID)); err != nil {
+				return nil, err
+			}
+			pair := [2]string{fromNodeID, nodeID}
+			if metadata, ok := d.connectionMetadata[pair]; ok {
+				partition.connectionMetadata[pair] = maps.Clone(metadata)
+			}
+			if weight, ok := d.connectionWeights[pair]; ok {
+				partition.connectionWeights[pair] = weight
+			}
+			if condition, ok := d.connectionConditions[pair]; ok {
+				partition.connectionConditions[pair] = condition
+			}
+		}
+		partition.nodes[nodeID].expression = n.cloneExpression()
+	}
+	return partition, nil
+}
+
+func (d *directedGraph[NodeType]) ResolveExternal(nodeID string, status ResolutionStatus) error {
+	nodeID = d.config.normalizeID(nodeID)
+	d.lock.Lock()
+	defer d.unlock()
+	n, ok := d.nodes[nodeID]
+	if !ok {
+		return d.nodeNotFound(nodeID)
+	}
+	if !n.external {
+		return &ErrNodeNotExternal{nodeID}
+	}
+	return n.resolveNode(status)
+}
+
+func (n *node[NodeType]) IsExternal() bool {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.external
+}
+
+// sortedInbound returns the IDs of the source nodes of the inbound connections of the node, sorted by ID.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) sortedInbound(nodeID string) []string {
+	result := make([]string, 0, len(d.connectionsToNode[nodeID]))
+	for fromNodeID := range d.connectionsToNode[nodeID] {
+		result = append(result, fromNodeID)
+	}
+	slices.Sort(result)
+	return result
+}
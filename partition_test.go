@@ -0,0 +1,63 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Partition(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	dNode := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("d"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.AndDependency))
+	assert.NoError(t, dNode.ConnectDependency("c", dgraph.OrDependency))
+
+	// The partition with c and d depends on b from the other partition.
+	partition := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(d.Partition([]string{"c", "d"}))
+	assert.Equals(t, len(partition.ListNodes()), 3)
+	stub := assert.NoErrorR[dgraph.Node[string]](t)(partition.GetNodeByID("b"))
+	assert.Equals(t, stub.IsExternal(), true)
+	assert.Equals(t, len(partition.ListConnections()), 2)
+
+	// Transfer the partition to another process.
+	data := assert.NoErrorR[[]byte](t)(partition.ExportJSON())
+	remote := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(dgraph.ImportJSON[string](data))
+	assert.NoError(t, remote.PushStartingNodes())
+	assert.Equals(t, remote.HasReadyNodes(), false)
+	assert.InstanceOf[*dgraph.ErrNodeNotExternal](t, remote.ResolveExternal("c", dgraph.Resolved))
+	assert.NoError(t, remote.ResolveExternal("b", dgraph.Resolved))
+	assert.Equals(t, remote.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"c": dgraph.Waiting})
+
+	_, err := d.Partition([]string{"e"})
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+}
+
+func TestDirectedGraph_Partition_Started(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{"b": dgraph.ObviatedDependency})
+
+	// The partition has the declared types, not the ones the connections have after the resolution.
+	partition := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(d.Partition([]string{"c"}))
+	partitionC := assert.NoErrorR[dgraph.Node[string]](t)(partition.GetNodeByID("c"))
+	assert.Equals(t, partitionC.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.OrDependency,
+		"b": dgraph.OrDependency,
+	})
+}
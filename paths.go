@@ -0,0 +1,83 @@
+package dgraph
+
+import (
+	"slices"
+)
+
+// DefaultConnectionWeight is the weight of connections that were not given a weight with Edge.SetWeight.
+const DefaultConnectionWeight = 1.0
+
+func (d *directedGraph[NodeType]) ShortestPath(fromNodeID, toNodeID string) ([]Node[NodeType], float64, error) {
+	return d.weightedPath(fromNodeID, toNodeID, func(candidate, current float64) bool {
+		return candidate < current
+	})
+}
+
+func (d *directedGraph[NodeType]) LongestPath(fromNodeID, toNodeID string) ([]Node[NodeType], float64, error) {
+	return d.weightedPath(fromNodeID, toNodeID, func(candidate, current float64) bool {
+		return candidate > current
+	})
+}
+
+// weightedPath finds the path between the two nodes whose total connection weight is preferred by the better
+// function. Since the graph must be acyclic, the distances are relaxed in topological order, which works for
+// both the shortest and the longest path, as well as for negative weights.
+func (d *directedGraph[NodeType]) weightedPath(
+	fromNodeID, toNodeID string,
+	better func(candidate, current float64) bool,
+) ([]Node[NodeType], float64, error) {
+	fromNodeID = d.config.normalizeID(fromNodeID)
+	toNodeID = d.config.normalizeID(toNodeID)
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if _, ok := d.nodes[fromNodeID]; !ok {
+		return nil, 0, d.nodeNotFound(fromNodeID)
+	}
+	if _, ok := d.nodes[toNodeID]; !ok {
+		return nil, 0, d.nodeNotFound(toNodeID)
+	}
+	order, cycle := d.topologicalOrder()
+	if cycle != nil {
+		return nil, 0, &ErrGraphHasCycles{Cycle: cycle}
+	}
+	distances := map[string]float64{fromNodeID: 0}
+	previous := map[string]string{}
+	for _, nodeID := range order[slices.Index(order, fromNodeID):] {
+		distance, reached := distances[nodeID]
+		if !reached {
+			continue
+		}
+		if nodeID == toNodeID {
+			break
+		}
+		for nextNodeID := range d.connectionsFromNode[nodeID] {
+			candidate := distance + d.connectionWeight(nodeID, nextNodeID)
+			if current, ok := distances[nextNodeID]; !ok || better(candidate, current) {
+				distances[nextNodeID] = candidate
+				previous[nextNodeID] = nodeID
+			}
+		}
+	}
+	distance, reached := distances[toNodeID]
+	if !reached {
+		return nil, 0, &ErrNoPath{fromNodeID, toNodeID}
+	}
+	var path []Node[NodeType]
+	for nodeID := toNodeID; ; nodeID = previous[nodeID] {
+		path = append(path, d.nodes[nodeID])
+		if nodeID == fromNodeID {
+			break
+		}
+	}
+	slices.Reverse(path)
+	return path, distance, nil
+}
+
+// connectionWeight returns the weight of the connection between the two nodes.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) connectionWeight(fromNodeID, toNodeID string) float64 {
+	if weight, ok := d.connectionWeights[[2]string{fromNodeID, toNodeID}]; ok {
+		return weight
+	}
+	return DefaultConnectionWeight
+}
@@ -0,0 +1,83 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_WeightedPaths(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"start", "fast", "slow", "end", "unrelated"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	weights := map[[2]string]float64{
+		{"start", "fast"}: 1,
+		{"fast", "end"}:   2,
+		{"start", "slow"}: 5,
+		{"slow", "end"}:   4,
+	}
+	for connection, weight := range weights {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(connection[1]))
+		e := assert.NoErrorR[dgraph.Edge](t)(n.ConnectDependencyEdge(connection[0], dgraph.AndDependency))
+		assert.NoError(t, e.SetWeight(weight))
+	}
+	// A direct connection with the default weight.
+	end := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("end"))
+	assert.NoError(t, end.ConnectDependency("start", dgraph.AndDependency))
+	e := assert.NoErrorR[dgraph.Edge](t)(d.GetEdge("start", "end"))
+	assert.Equals(t, assert.NoErrorR[float64](t)(e.Weight()), dgraph.DefaultConnectionWeight)
+
+	path, weight, err := d.ShortestPath("start", "end")
+	assert.NoError(t, err)
+	assert.Equals(t, nodeIDs(path), []string{"start", "end"})
+	assert.Equals(t, weight, 1.0)
+
+	path, weight, err = d.LongestPath("start", "end")
+	assert.NoError(t, err)
+	assert.Equals(t, nodeIDs(path), []string{"start", "slow", "end"})
+	assert.Equals(t, weight, 9.0)
+
+	path, weight, err = d.ShortestPath("fast", "fast")
+	assert.NoError(t, err)
+	assert.Equals(t, nodeIDs(path), []string{"fast"})
+	assert.Equals(t, weight, 0.0)
+
+	_, _, err = d.ShortestPath("end", "start")
+	var noPath *dgraph.ErrNoPath
+	assert.Equals(t, errors.As(err, &noPath), true)
+	assert.Equals(t, *noPath, dgraph.ErrNoPath{SourceNodeID: "end", DestinationNodeID: "start"})
+
+	// A cycle anywhere in the graph prevents path queries.
+	unrelated := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("unrelated"))
+	other := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("other", "other"))
+	assert.NoError(t, unrelated.Connect(other.ID()))
+	assert.NoError(t, other.Connect(unrelated.ID()))
+	_, _, err = d.LongestPath("start", "end")
+	assert.InstanceOf[*dgraph.ErrGraphHasCycles](t, err)
+}
+
+func TestDirectedGraph_WeightedPathsExportJSON(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	e := assert.NoErrorR[dgraph.Edge](t)(b.ConnectDependencyEdge(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, e.SetWeight(2.5))
+
+	data := assert.NoErrorR[[]byte](t)(d.ExportJSON())
+	assert.Contains(t, string(data), `"weight":2.5`)
+	d2 := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(dgraph.ImportJSON[string](data))
+	_, weight, err := d2.LongestPath("a", "b")
+	assert.NoError(t, err)
+	assert.Equals(t, weight, 2.5)
+
+	// Disconnecting forgets the weight.
+	assert.NoError(t, e.Disconnect())
+	assert.NoError(t, a.Connect(b.ID()))
+	_, weight, err = d.LongestPath("a", "b")
+	assert.NoError(t, err)
+	assert.Equals(t, weight, dgraph.DefaultConnectionWeight)
+}
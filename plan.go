@@ -0,0 +1,267 @@
+package dgraph
+
+import (
+	"slices"
+	"sync"
+)
+
+// ExecutionPlan is an immutable, compiled form of a DirectedGraph. It stores the topology using dense indices and
+// precomputed dependency counters, so that many independent runs of the same topology can be started cheaply with
+// NewRun(), without cloning the graph.
+type ExecutionPlan[NodeType any] struct {
+	ids        []string
+	index      map[string]int
+	items      []NodeType
+	outbound   [][]planDependency
+	hardCount  []int
+	orCount    []int
+	startNodes []int
+	// Expressions of the nodes, or nil. Each expression counts as a single hard dependency.
+	expressions []*Expression
+}
+
+// planDependency is a connection in an ExecutionPlan, seen from the source node.
+type planDependency struct {
+	to             int
+	dependencyType DependencyType
+}
+
+func (d *directedGraph[NodeType]) Compile() (*ExecutionPlan[NodeType], error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, cycle := d.topologicalOrder(); cycle != nil {
+		return nil, &ErrGraphHasCycles{Cycle: cycle}
+	}
+
+	plan := &ExecutionPlan[NodeType]{
+		ids:         make([]string, 0, len(d.nodes)),
+		index:       make(map[string]int, len(d.nodes)),
+		items:       make([]NodeType, 0, len(d.nodes)),
+		outbound:    make([][]planDependency, len(d.nodes)),
+		hardCount:   make([]int, len(d.nodes)),
+		orCount:     make([]int, len(d.nodes)),
+		expressions: make([]*Expression, len(d.nodes)),
+	}
+	for nodeID := range d.nodes {
+		plan.ids = append(plan.ids, nodeID)
+	}
+	slices.Sort(plan.ids)
+	for i, nodeID := range plan.ids {
+		plan.index[nodeID] = i
+		plan.items = append(plan.items, d.nodes[nodeID].item)
+	}
+	for i, nodeID := range plan.ids {
+		n := d.nodes[nodeID]
+		for fromNodeID := range d.connectionsToNode[nodeID] {
+			// The plan starts from scratch, so dependencies obviated by the execution of the graph keep their type.
+			dependencyType := n.declaredDependencyType(fromNodeID)
+			switch dependencyType {
+			case AndDependency, CompletionAndDependency, OnUnresolvableDependency:
+				plan.hardCount[i]++
+			case OrDependency:
+				plan.orCount[i]++
+			case ExpressionDependency:
+				if n.expression == nil {
+					plan.hardCount[i]++
+				}
+			}
+			from := plan.index[fromNodeID]
+			plan.outbound[from] = append(plan.outbound[from], planDependency{i, dependencyType})
+		}
+		if n.expression != nil {
+			plan.expressions[i] = n.cloneExpression()
+			plan.hardCount[i]++
+		}
+		if plan.hardCount[i] == 0 && plan.orCount[i] == 0 {
+			plan.startNodes = append(plan.startNodes, i)
+		}
+	}
+	return plan, nil
+}
+
+// NodeIDs returns the IDs of all nodes in the plan, sorted.
+func (p *ExecutionPlan[NodeType]) NodeIDs() []string {
+	return slices.Clone(p.ids)
+}
+
+// Item returns the item of the node with the specified ID. If the node does not exist, an ErrNodeNotFound is
+// returned.
+func (p *ExecutionPlan[NodeType]) Item(nodeID string) (NodeType, error) {
+	i, ok := p.index[nodeID]
+	if !ok {
+		var defaultValue NodeType
+		return defaultValue, &ErrNodeNotFound{nodeID, p.ids}
+	}
+	return p.items[i], nil
+}
+
+// NewRun creates a new, independent execution of the plan. The starting nodes of the run are already ready.
+func (p *ExecutionPlan[NodeType]) NewRun() *Run[NodeType] {
+	r := &Run[NodeType]{
+		plan:         p,
+		status:       make([]ResolutionStatus, len(p.ids)),
+		ready:        make([]bool, len(p.ids)),
+		orSatisfied:  make([]bool, len(p.ids)),
+		exprDecided:  make([]bool, len(p.ids)),
+		remainingAnd: slices.Clone(p.hardCount),
+		remainingOr:  slices.Clone(p.orCount),
+	}
+	for i := range r.status {
+		r.status[i] = Waiting
+	}
+	for _, i := range p.startNodes {
+		r.markReady(i)
+	}
+	return r
+}
+
+// Run is a single execution of an ExecutionPlan. It only carries the mutable state of the execution and follows
+// the same resolution rules as a DirectedGraph.
+type Run[NodeType any] struct {
+	plan         *ExecutionPlan[NodeType]
+	lock         sync.Mutex
+	status       []ResolutionStatus
+	ready        []bool
+	orSatisfied  []bool
+	exprDecided  []bool
+	remainingAnd []int
+	remainingOr  []int
+	readyQueue   []int
+}
+
+// Plan returns the plan the run was created from.
+func (r *Run[NodeType]) Plan() *ExecutionPlan[NodeType] {
+	return r.plan
+}
+
+// Status returns the resolution status of the node with the specified ID. If the node does not exist, an
+// ErrNodeNotFound is returned.
+func (r *Run[NodeType]) Status(nodeID string) (ResolutionStatus, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	i, ok := r.plan.index[nodeID]
+	if !ok {
+		return "", &ErrNodeNotFound{nodeID, r.plan.ids}
+	}
+	return r.status[i], nil
+}
+
+// HasReadyNodes checks to see if there are any ready nodes without clearing them.
+func (r *Run[NodeType]) HasReadyNodes() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.readyQueue) != 0
+}
+
+// PopReadyNodes returns all nodes that became ready since the last call, and clears the list.
+func (r *Run[NodeType]) PopReadyNodes() map[string]ResolutionStatus {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := make(map[string]ResolutionStatus, len(r.readyQueue))
+	for _, i := range r.readyQueue {
+		result[r.plan.ids[i]] = r.status[i]
+	}
+	r.readyQueue = r.readyQueue[:0]
+	return result
+}
+
+// ResolveNode sets the resolution status of the node and updates the nodes that follow it, following the same
+// rules as Node.ResolveNode().
+func (r *Run[NodeType]) ResolveNode(nodeID string, status ResolutionStatus) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	i, ok := r.plan.index[nodeID]
+	if !ok {
+		return &ErrNodeNotFound{nodeID, r.plan.ids}
+	}
+	return r.resolve(i, status)
+}
+
+// Caller should have the lock held.
+func (r *Run[NodeType]) resolve(i int, newStatus ResolutionStatus) error {
+	switch r.status[i] {
+	case Waiting:
+	case Unresolvable:
+		if newStatus == Unresolvable {
+			return nil
+		}
+		return ErrNodeResolutionAlreadySet{r.plan.ids[i], r.status[i], newStatus}
+	default:
+		return ErrNodeResolutionAlreadySet{r.plan.ids[i], r.status[i], newStatus}
+	}
+	r.status[i] = newStatus
+	if newStatus == Waiting {
+		return nil
+	}
+	for _, dependency := range r.plan.outbound[i] {
+		if err := r.dependencyResolved(dependency, newStatus); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Caller should have the lock held.
+func (r *Run[NodeType]) dependencyResolved(dependency planDependency, dependencyResolution ResolutionStatus) error {
+	i := dependency.to
+	if r.ready[i] || !isHardDependency(dependency.dependencyType) {
+		return nil
+	}
+	if dependency.dependencyType == OrDependency && r.orSatisfied[i] {
+		return nil // Obviated.
+	}
+	if dependency.dependencyType == ExpressionDependency && r.plan.expressions[i] != nil {
+		return r.expressionDependencyResolved(i)
+	}
+	if isDependencyFailed(dependency.dependencyType, dependencyResolution) {
+		if dependency.dependencyType == OrDependency {
+			r.remainingOr[i]--
+		}
+		if dependency.dependencyType != OrDependency || r.remainingOr[i] == 0 {
+			r.markReady(i)
+			return r.resolve(i, Unresolvable)
+		}
+		return nil
+	}
+	if dependency.dependencyType == OrDependency {
+		r.orSatisfied[i] = true
+		r.remainingOr[i] = 0
+	} else {
+		r.remainingAnd[i]--
+	}
+	if r.remainingAnd[i] == 0 && r.remainingOr[i] == 0 {
+		r.markReady(i)
+	}
+	return nil
+}
+
+// expressionDependencyResolved re-evaluates the expression of the node after one of its dependencies was resolved.
+// Caller should have the lock held.
+func (r *Run[NodeType]) expressionDependencyResolved(i int) error {
+	if r.exprDecided[i] {
+		return nil // Obviated.
+	}
+	satisfied, known := r.plan.expressions[i].evaluate(func(leaf Expression) (bool, bool) {
+		status := r.status[r.plan.index[leaf.DependencyID]]
+		return conditionSatisfied(leaf.DependencyType, status), status != Waiting
+	})
+	if !known {
+		return nil
+	}
+	r.exprDecided[i] = true
+	if !satisfied {
+		r.markReady(i)
+		return r.resolve(i, Unresolvable)
+	}
+	r.remainingAnd[i]--
+	if r.remainingAnd[i] == 0 && r.remainingOr[i] == 0 {
+		r.markReady(i)
+	}
+	return nil
+}
+
+// Caller should have the lock held.
+func (r *Run[NodeType]) markReady(i int) {
+	r.ready[i] = true
+	r.readyQueue = append(r.readyQueue, i)
+}
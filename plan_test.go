@@ -0,0 +1,119 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestExecutionPlan_Runs(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "or_target", "and_target", "completion"} {
+		_, err := d.AddNode(id, "item-"+id)
+		assert.NoError(t, err)
+	}
+	orTarget := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("or_target"))
+	andTarget := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("and_target"))
+	completion := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("completion"))
+	assert.NoError(t, orTarget.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, orTarget.ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, andTarget.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, andTarget.ConnectDependency("c", dgraph.OptionalDependency))
+	assert.NoError(t, completion.ConnectDependency("and_target", dgraph.CompletionAndDependency))
+
+	plan, err := d.Compile()
+	assert.NoError(t, err)
+	assert.Equals(t, plan.NodeIDs(), []string{"a", "and_target", "b", "c", "completion", "or_target"})
+	item, err := plan.Item("b")
+	assert.NoError(t, err)
+	assert.Equals(t, item, "item-b")
+
+	first := plan.NewRun()
+	second := plan.NewRun()
+	for _, run := range []*dgraph.Run[string]{first, second} {
+		assert.Equals(t, run.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+			"a": dgraph.Waiting,
+			"b": dgraph.Waiting,
+			"c": dgraph.Waiting,
+		})
+	}
+
+	// The first run fails a, which fails the AND target, but the OR target can still be satisfied by b.
+	assert.NoError(t, first.ResolveNode("a", dgraph.Unresolvable))
+	assert.Equals(t, first.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"and_target": dgraph.Unresolvable,
+		"completion": dgraph.Waiting,
+	})
+	assert.NoError(t, first.ResolveNode("b", dgraph.Resolved))
+	assert.Equals(t, first.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"or_target": dgraph.Waiting})
+
+	// The second run is not affected by the first one.
+	status, err := second.Status("and_target")
+	assert.NoError(t, err)
+	assert.Equals(t, status, dgraph.Waiting)
+	assert.NoError(t, second.ResolveNode("a", dgraph.Resolved))
+	assert.Equals(t, second.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"or_target":  dgraph.Waiting,
+		"and_target": dgraph.Waiting,
+	})
+	assert.InstanceOf[dgraph.ErrNodeResolutionAlreadySet](t, second.ResolveNode("a", dgraph.Resolved))
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, second.ResolveNode("missing", dgraph.Resolved))
+}
+
+func TestExecutionPlan_Cycles(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, a.Connect(b.ID()))
+	assert.NoError(t, b.Connect(a.ID()))
+	_, err := d.Compile()
+	assert.InstanceOf[*dgraph.ErrGraphHasCycles](t, err)
+}
+
+func TestExecutionPlan_OnUnresolvableDependency(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "handler"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	handler := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("handler"))
+	assert.NoError(t, handler.ConnectDependency("a", dgraph.OnUnresolvableDependency))
+	plan := assert.NoErrorR[*dgraph.ExecutionPlan[string]](t)(d.Compile())
+
+	failing := plan.NewRun()
+	assert.Equals(t, failing.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting})
+	assert.NoError(t, failing.ResolveNode("a", dgraph.Unresolvable))
+	assert.Equals(t, failing.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"handler": dgraph.Waiting})
+
+	succeeding := plan.NewRun()
+	succeeding.PopReadyNodes()
+	assert.NoError(t, succeeding.ResolveNode("a", dgraph.Resolved))
+	assert.Equals(t, succeeding.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"handler": dgraph.Unresolvable})
+}
+
+func TestDirectedGraph_Compile_Started(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "or_target"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	orTarget := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("or_target"))
+	assert.NoError(t, orTarget.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, orTarget.ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	// Resolving a obviates the dependency on b.
+	assert.Equals(t, orTarget.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"b": dgraph.ObviatedDependency,
+	})
+
+	// The plan uses the declared types, so b still satisfies the OR dependency in a new run.
+	run := assert.NoErrorR[*dgraph.ExecutionPlan[string]](t)(d.Compile()).NewRun()
+	run.PopReadyNodes()
+	assert.NoError(t, run.ResolveNode("a", dgraph.Unresolvable))
+	assert.Equals(t, run.HasReadyNodes(), false)
+	assert.NoError(t, run.ResolveNode("b", dgraph.Resolved))
+	assert.Equals(t, run.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"or_target": dgraph.Waiting})
+}
@@ -0,0 +1,55 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Reset(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	_, err := d.AddGroup("group")
+	assert.NoError(t, err)
+	added := 0
+	d.OnNodeAdded(func(_ dgraph.Node[string]) {
+		added++
+	})
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+
+	d.Reset()
+	assert.Equals(t, len(d.ListNodes()), 0)
+	assert.Equals(t, len(d.ListConnections()), 0)
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.Equals(t, len(d.ResolutionOrder()), 0)
+	assert.InstanceOf[*dgraph.ErrNodeDeleted](t, a.Remove())
+	_, err = d.GetGroup("group")
+	assert.InstanceOf[*dgraph.ErrGroupNotFound](t, err)
+
+	// The graph can be rebuilt with the same IDs, and it has to be started again.
+	a = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.Equals(t, added, 0)
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting})
+}
+
+func TestPool(t *testing.T) {
+	pool := dgraph.NewPool[string](dgraph.WithMaxReadyNodes(1))
+	for i := 0; i < 3; i++ {
+		d := pool.Get()
+		assert.Equals(t, len(d.ListNodes()), 0)
+		for _, id := range []string{"a", "b"} {
+			_, err := d.AddNode(id, id)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, d.PushStartingNodes())
+		// The options of the pool apply to every graph.
+		assert.Equals(t, len(d.PopReadyNodes()), 1)
+		pool.Put(d)
+	}
+}
@@ -0,0 +1,64 @@
+package dgraph
+
+// DependencyCondition decides whether the resolution of a conditional dependency satisfies the dependency. It
+// receives the status and the item of the dependency node. See Node.ConnectDependencyIf.
+type DependencyCondition[NodeType any] func(status ResolutionStatus, item NodeType) bool
+
+func (n *node[NodeType]) ConnectDependencyIf(fromNodeID string, condition DependencyCondition[NodeType]) error {
+	fromNodeID = n.dg.config.normalizeID(fromNodeID)
+	if condition == nil {
+		return &ErrNilDependencyCondition{n.id, fromNodeID}
+	}
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if err := n.dg.connect(fromNodeID, n.id, CompletionAndDependency); err != nil {
+		return err
+	}
+	n.dg.connectionConditions[[2]string{fromNodeID, n.id}] = condition
+	return nil
+}
+
+// isDependencyFailed works like the isDependencyFailed function, but lets the condition of a conditional
+// dependency decide whether the resolution of the dependency satisfies it.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) isDependencyFailed(
+	dependencyNodeID string,
+	dependencyType DependencyType,
+	dependencyResolution ResolutionStatus,
+) bool {
+	condition, ok := n.dg.connectionConditions[[2]string{dependencyNodeID, n.id}]
+	if !ok || dependencyType != CompletionAndDependency {
+		return isDependencyFailed(dependencyType, dependencyResolution)
+	}
+	return !condition(dependencyResolution, n.dg.nodes[dependencyNodeID].item)
+}
+
+// dependencySatisfied works like conditionSatisfied, but checks the condition of a conditional dependency.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) dependencySatisfied(
+	dependencyNodeID string,
+	dependencyType DependencyType,
+	status ResolutionStatus,
+) bool {
+	condition, ok := n.dg.connectionConditions[[2]string{dependencyNodeID, n.id}]
+	if !ok || dependencyType != CompletionAndDependency {
+		return conditionSatisfied(dependencyType, status)
+	}
+	return condition(status, n.dg.nodes[dependencyNodeID].item)
+}
+
+// dependencyFailureCause returns the unresolvable cause of a node whose dependency failed.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) dependencyFailureCause(
+	dependencyNodeID string,
+	dependencyType DependencyType,
+	dependencyResolution ResolutionStatus,
+) error {
+	_, conditional := n.dg.connectionConditions[[2]string{dependencyNodeID, n.id}]
+	if conditional && dependencyType == CompletionAndDependency {
+		return &ErrDependencyConditionNotMet{n.id, dependencyNodeID, dependencyResolution}
+	}
+	return &ErrDependencyUnresolvable{
+		n.id, dependencyNodeID, dependencyType, n.dg.nodes[dependencyNodeID].unresolvableReason(),
+	}
+}
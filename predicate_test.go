@@ -0,0 +1,104 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestNode_ConnectDependencyIf(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "approve"))
+	then := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("then", "then"))
+	otherwise := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("otherwise", "otherwise"))
+	assert.NoError(t, then.ConnectDependencyIf("a", func(status dgraph.ResolutionStatus, item string) bool {
+		return status == dgraph.Resolved && item == "approve"
+	}))
+	assert.NoError(t, otherwise.ConnectDependencyIf("a", func(status dgraph.ResolutionStatus, item string) bool {
+		return status == dgraph.Unresolvable || item != "approve"
+	}))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, then.IsReady(), true)
+	assert.Equals(t, then.ResolutionStatus(), dgraph.Waiting)
+	assert.Equals(t, otherwise.ResolutionStatus(), dgraph.Unresolvable)
+	assert.InstanceOf[*dgraph.ErrDependencyConditionNotMet](t, otherwise.UnresolvableCause())
+	assert.Equals(t, then.ReadinessCondition().Satisfied, true)
+	assert.Equals(t, otherwise.ReadinessCondition().Satisfied, false)
+}
+
+func TestNode_ConnectDependencyIf_ChangeType(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependencyIf("a", func(dgraph.ResolutionStatus, string) bool {
+		return false
+	}))
+	edge := assert.NoErrorR[dgraph.Edge](t)(d.GetEdge("a", "b"))
+	// Changing the type drops the condition, also when the type is changed back.
+	assert.NoError(t, edge.ChangeType(dgraph.AndDependency))
+	assert.NoError(t, edge.ChangeType(dgraph.CompletionAndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, b.IsReady(), true)
+}
+
+func TestNode_ConnectDependencyIf_Nil(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.InstanceOf[*dgraph.ErrNilDependencyCondition](t, b.ConnectDependencyIf("a", nil))
+	assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+}
+
+// newNeverGraph creates a graph in which b has a conditional dependency on a that is never satisfied.
+func newNeverGraph(t *testing.T) dgraph.DirectedGraph[string] {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependencyIf("a", func(dgraph.ResolutionStatus, string) bool {
+		return false
+	}))
+	return d
+}
+
+func TestNode_ConnectDependencyIf_Copies(t *testing.T) {
+	merged := dgraph.New[string]()
+	assert.NoError(t, merged.Merge(newNeverGraph(t), dgraph.WithMergeIDPrefix("x.")))
+	sequenced := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(
+		dgraph.Sequence(newNeverGraph(t), dgraph.New[string](), dgraph.AndDependency),
+	)
+	partition := assert.NoErrorR[dgraph.DirectedGraph[string]](t)(newNeverGraph(t).Partition([]string{"a", "b"}))
+	for name, tc := range map[string]struct {
+		d      dgraph.DirectedGraph[string]
+		prefix string
+	}{
+		"merge":     {merged, "x."},
+		"sequence":  {sequenced, ""},
+		"partition": {partition, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, tc.d.PushStartingNodes())
+			a := assert.NoErrorR[dgraph.Node[string]](t)(tc.d.GetNodeByID(tc.prefix + "a"))
+			b := assert.NoErrorR[dgraph.Node[string]](t)(tc.d.GetNodeByID(tc.prefix + "b"))
+			assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+			assert.Equals(t, b.ResolutionStatus(), dgraph.Unresolvable)
+			assert.InstanceOf[*dgraph.ErrDependencyConditionNotMet](t, b.UnresolvableCause())
+		})
+	}
+}
+
+func TestNode_ConnectDependencyIf_Reset(t *testing.T) {
+	d := newNeverGraph(t)
+	d.Reset()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.CompletionAndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	// The condition of the connection before the reset is gone.
+	assert.Equals(t, b.IsReady(), true)
+}
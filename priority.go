@@ -0,0 +1,110 @@
+package dgraph
+
+import (
+	"cmp"
+	"slices"
+)
+
+// WithCriticalPathPriority enables critical-path-aware scheduling. Ready nodes are prioritized by the cost of the
+// longest chain of nodes downstream of them, including the node itself. The weight function returns the cost of a
+// single node; if it is nil, the cost of a node is its expected duration in seconds set with
+// Node.SetExpectedDuration, or 1 if it has none. The costs are computed lazily and cached until the topology of the
+// graph or an expected duration changes.
+//
+// With this option, PopReadyNodesOrdered() returns the nodes with the highest cost first, and nodes held back by
+// WithMaxReadyNodes are released by cost instead of in the order in which they became ready.
+func WithCriticalPathPriority(weight func(nodeID string) float64) Option {
+	return func(c *config) {
+		c.criticalPathPriority = true
+		c.nodeWeight = weight
+	}
+}
+
+func (d *directedGraph[NodeType]) PopReadyNodesOrdered() []string {
+	d.lock.Lock()
+	defer d.unlock()
+	d.checkDeadline()
+	if d.gateClosed {
+		return []string{}
+	}
+	result := d.readyForProcessing.List()
+	for _, nodeID := range result {
+		d.nodes[nodeID].poppedAt = d.config.clock()
+	}
+	slices.SortFunc(result, d.compareReadyPriority)
+	if len(result) > 0 {
+		d.advanceGeneration()
+	}
+	d.readyForProcessing.Clear()
+	d.releaseHeldReady()
+	return result
+}
+
+func (d *directedGraph[NodeType]) CriticalPath() []Node[NodeType] {
+	d.lock.Lock()
+	defer d.unlock()
+	if _, cycle := d.topologicalOrder(); cycle != nil {
+		return nil
+	}
+	var candidates []string
+	for nodeID := range d.nodes {
+		if len(d.connectionsToNode[nodeID]) == 0 {
+			candidates = append(candidates, nodeID)
+		}
+	}
+	var result []Node[NodeType]
+	for len(candidates) > 0 {
+		nodeID := slices.MinFunc(candidates, func(a, b string) int {
+			if c := cmp.Compare(d.downstreamCost(b), d.downstreamCost(a)); c != 0 {
+				return c
+			}
+			return cmp.Compare(a, b)
+		})
+		result = append(result, d.nodes[nodeID])
+		candidates = candidates[:0]
+		for toNodeID := range d.connectionsFromNode[nodeID] {
+			candidates = append(candidates, toNodeID)
+		}
+	}
+	return result
+}
+
+// compareReadyPriority orders node IDs by descending downstream cost if critical path priority is enabled, then by
+// ID.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) compareReadyPriority(a, b string) int {
+	if d.config.criticalPathPriority {
+		if c := cmp.Compare(d.downstreamCost(b), d.downstreamCost(a)); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(a, b)
+}
+
+// downstreamCost returns the cost of the longest chain of nodes starting at the specified node.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) downstreamCost(nodeID string) float64 {
+	if d.downstreamCosts == nil {
+		d.downstreamCosts = make(map[string]float64, len(d.nodes))
+	}
+	return d.computeDownstreamCost(nodeID, map[string]struct{}{})
+}
+
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) computeDownstreamCost(nodeID string, visiting map[string]struct{}) float64 {
+	if cost, ok := d.downstreamCosts[nodeID]; ok {
+		return cost
+	}
+	if _, ok := visiting[nodeID]; ok {
+		// Cycle; don't follow it any further.
+		return 0
+	}
+	visiting[nodeID] = struct{}{}
+	var longestChain float64
+	for toNodeID := range d.connectionsFromNode[nodeID] {
+		longestChain = max(longestChain, d.computeDownstreamCost(toNodeID, visiting))
+	}
+	delete(visiting, nodeID)
+	d.downstreamCosts[nodeID] = d.nodeCost(nodeID) + longestChain
+	return d.downstreamCosts[nodeID]
+}
@@ -0,0 +1,290 @@
+package dgraph
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ReadyReason describes why a node became ready.
+type ReadyReason string
+
+const (
+	// ReadyNoDependencies means the node had no required dependencies when the graph was started or when it was
+	// reconciled.
+	ReadyNoDependencies ReadyReason = "no-dependencies"
+	// ReadyDependenciesResolved means the last required AND or completion-AND dependency was resolved.
+	ReadyDependenciesResolved ReadyReason = "dependencies-resolved"
+	// ReadyOrSatisfied means the resolution of an OR dependency satisfied the last requirement of the node.
+	ReadyOrSatisfied ReadyReason = "or-satisfied"
+	// ReadyUnresolvableDependency means the node became ready because it is unresolvable due to a failed dependency.
+	ReadyUnresolvableDependency ReadyReason = "unresolvable-dependency"
+	// ReadyIterationCompleted means the node has a self-loop and completed its previous iteration. See
+	// WithSelfLoops.
+	ReadyIterationCompleted ReadyReason = "iteration-completed"
+	// ReadyCancelled means the node was cancelled, and is delivered so that the executor can clean up after it.
+	ReadyCancelled ReadyReason = "cancelled"
+)
+
+// ReadyNodeInfo is the state of a node returned by PopReadyNodesWithReasons.
+type ReadyNodeInfo struct {
+	Status ResolutionStatus
+	Reason ReadyReason
+}
+
+// ReadyNode is a node returned by PopReadyNodeObjects, along with its state captured at pop time.
+type ReadyNode[NodeType any] struct {
+	Node   Node[NodeType]
+	Status ResolutionStatus
+	Reason ReadyReason
+	// ResolvedDependencies are the dependencies of the node that were resolved, with their dependency types.
+	ResolvedDependencies map[string]DependencyType
+}
+
+func (d *directedGraph[NodeType]) PopReadyNodeObjects() []ReadyNode[NodeType] {
+	d.lock.Lock()
+	defer d.unlock()
+	popped := d.popReady(-1, "")
+	result := make([]ReadyNode[NodeType], 0, len(popped))
+	for _, n := range popped {
+		result = append(result, ReadyNode[NodeType]{n, n.status, n.readyReason, maps.Clone(n.resolvedDependencies)})
+	}
+	return result
+}
+
+func (d *directedGraph[NodeType]) PopNReadyNodes(count int) map[string]ResolutionStatus {
+	d.lock.Lock()
+	defer d.unlock()
+	result := make(map[string]ResolutionStatus)
+	// A count of zero or less pops nothing, while popReady pops all nodes for a negative limit.
+	for _, n := range d.popReady(max(count, 0), "") {
+		result[n.id] = n.status
+	}
+	return result
+}
+
+func (d *directedGraph[NodeType]) PopReadyNodesWithReasons() map[string]ReadyNodeInfo {
+	d.lock.Lock()
+	defer d.unlock()
+	result := make(map[string]ReadyNodeInfo)
+	for _, n := range d.popReady(-1, "") {
+		result[n.id] = ReadyNodeInfo{n.status, n.readyReason}
+	}
+	return result
+}
+
+// popReady removes up to limit nodes whose IDs start with the prefix from the ready set in priority order, or all of
+// them if limit is negative, and returns them. Held back nodes are then released into the ready set as far as the
+// ready limit allows. Nothing is popped while the gate is closed.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) popReady(limit int, prefix string) []*node[NodeType] {
+	d.checkDeadline()
+	if d.gateClosed {
+		return nil
+	}
+	nodeIDs := d.readyForProcessing.List()
+	if prefix != "" {
+		nodeIDs = slices.DeleteFunc(nodeIDs, func(nodeID string) bool {
+			return !strings.HasPrefix(nodeID, prefix)
+		})
+	}
+	slices.SortFunc(nodeIDs, d.compareReadyPriority)
+	if limit >= 0 && limit < len(nodeIDs) {
+		nodeIDs = nodeIDs[:limit]
+	}
+	all := len(nodeIDs) == d.readyForProcessing.Len()
+	result := make([]*node[NodeType], 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		n := d.nodes[nodeID]
+		n.poppedAt = d.config.clock()
+		result = append(result, n)
+		if !all {
+			d.readyForProcessing.Remove(nodeID)
+		}
+	}
+	if all {
+		d.readyForProcessing.Clear()
+	}
+	if len(result) > 0 {
+		d.advanceGeneration()
+	}
+	d.releaseHeldReady()
+	return result
+}
+
+func (n *node[NodeType]) ReadyReason() ReadyReason {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.readyReason
+}
+
+func (d *directedGraph[NodeType]) SetGate(open bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.gateClosed == open {
+		d.gateClosed = !open
+		d.advanceGeneration()
+	}
+	if open {
+		d.notifyReadyChanged()
+	}
+}
+
+func (d *directedGraph[NodeType]) IsGateOpen() bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return !d.gateClosed
+}
+
+// pushReady adds the node to the ready set, or holds it back if the ready limit is reached or its resources are not
+// available.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) pushReady(n *node[NodeType]) {
+	if d.isPendingReady(n.id) {
+		return
+	}
+	n.readyAt = d.config.clock()
+	d.emitNodeReady(n)
+	if d.readyLimitReached() || !d.acquireResources(n) {
+		d.holdReady(n)
+		return
+	}
+	d.readyForProcessing.Add(n.id)
+	d.advanceGeneration()
+	d.notifyReadyChanged()
+}
+
+// releaseHeldReady moves held back nodes whose resources are available into the ready set until the ready limit is
+// reached.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) releaseHeldReady() {
+	if d.config.criticalPathPriority {
+		slices.SortStableFunc(d.heldReady, func(a, b *node[NodeType]) int {
+			return cmp.Compare(d.downstreamCost(b.id), d.downstreamCost(a.id))
+		})
+	}
+	var stillHeld []*node[NodeType]
+	for _, n := range d.heldReady {
+		if d.readyLimitReached() || !d.acquireResources(n) {
+			stillHeld = append(stillHeld, n)
+			continue
+		}
+		delete(d.heldReadyIDs, n.id)
+		d.readyForProcessing.Add(n.id)
+		d.advanceGeneration()
+	}
+	released := len(d.heldReady) != len(stillHeld)
+	d.heldReady = stillHeld
+	if released {
+		d.notifyReadyChanged()
+	}
+}
+
+// holdReady holds back the ready node until releaseHeldReady adds it to the ready set.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) holdReady(n *node[NodeType]) {
+	d.heldReady = append(d.heldReady, n)
+	d.heldReadyIDs[n.id] = struct{}{}
+}
+
+// clearHeldReady drops all held back nodes.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) clearHeldReady() {
+	clear(d.heldReady)
+	d.heldReady = d.heldReady[:0]
+	clear(d.heldReadyIDs)
+}
+
+// readyLimitReached returns true if the ready set is limited by WithMaxReadyNodes and full.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) readyLimitReached() bool {
+	return d.config.maxReadyNodes > 0 && d.readyForProcessing.Len() >= d.config.maxReadyNodes
+}
+
+// isPendingReady returns true if the node is in the ready set or held back from it.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) isPendingReady(nodeID string) bool {
+	if d.readyForProcessing.Contains(nodeID) {
+		return true
+	}
+	_, held := d.heldReadyIDs[nodeID]
+	return held
+}
+
+// removeReady removes the node from the ready set or the held back nodes.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) removeReady(nodeID string) {
+	if d.readyForProcessing.Contains(nodeID) {
+		d.readyForProcessing.Remove(nodeID)
+		d.advanceGeneration()
+		d.releaseResources(d.nodes[nodeID])
+		d.releaseHeldReady()
+		return
+	}
+	if _, held := d.heldReadyIDs[nodeID]; !held {
+		return
+	}
+	delete(d.heldReadyIDs, nodeID)
+	for i, n := range d.heldReady {
+		if n.id == nodeID {
+			d.heldReady = append(d.heldReady[:i:i], d.heldReady[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *directedGraph[NodeType]) PopReadyNodesWhere(predicate func(Node[NodeType]) bool) map[string]ResolutionStatus {
+	d.lock.RLock()
+	candidates := make([]*node[NodeType], 0, d.readyForProcessing.Len())
+	for _, nodeID := range d.readyForProcessing.List() {
+		candidates = append(candidates, d.nodes[nodeID])
+	}
+	d.lock.RUnlock()
+
+	// The predicate runs without the lock so that it can query the nodes it receives.
+	var matching []*node[NodeType]
+	for _, n := range candidates {
+		if predicate(n) {
+			matching = append(matching, n)
+		}
+	}
+
+	result := make(map[string]ResolutionStatus)
+	d.lock.Lock()
+	defer d.unlock()
+	d.checkDeadline()
+	if d.gateClosed {
+		return result
+	}
+	for _, n := range matching {
+		// Nodes popped or removed while the predicate was running are skipped.
+		if n.deleted || !d.readyForProcessing.Contains(n.id) {
+			continue
+		}
+		result[n.id] = n.status
+		n.poppedAt = d.config.clock()
+		d.readyForProcessing.Remove(n.id)
+		d.advanceGeneration()
+	}
+	d.releaseHeldReady()
+	return result
+}
+
+func (d *directedGraph[NodeType]) PopReadyNodesInGroup(name string) (map[string]ResolutionStatus, error) {
+	d.lock.RLock()
+	g, ok := d.groups[name]
+	if !ok {
+		d.lock.RUnlock()
+		return nil, &ErrGroupNotFound{name}
+	}
+	members := make(map[string]struct{}, len(g.members))
+	for nodeID := range g.members {
+		members[nodeID] = struct{}{}
+	}
+	d.lock.RUnlock()
+	return d.PopReadyNodesWhere(func(n Node[NodeType]) bool {
+		_, ok := members[n.ID()]
+		return ok
+	}), nil
+}
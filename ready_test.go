@@ -0,0 +1,278 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_MaxReadyNodes(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithMaxReadyNodes(2))
+	for _, id := range []string{"d", "c", "b", "a"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	final := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("final", "final"))
+	assert.NoError(t, final.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Waiting,
+		"b": dgraph.Waiting,
+	})
+	// The node becoming ready now is queued behind the held back starting nodes.
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"c": dgraph.Waiting,
+		"d": dgraph.Waiting,
+	})
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"final": dgraph.Waiting,
+	})
+	assert.Equals(t, d.HasReadyNodes(), false)
+}
+
+func TestDirectedGraph_MaxReadyNodes_RenameHeld(t *testing.T) {
+	d := dgraph.New[string](
+		dgraph.WithMaxReadyNodes(1),
+		dgraph.WithReadyNodeConnectionPolicy(dgraph.ReadyNodeConnectionReject),
+	)
+	assert.NoError(t, d.AddNodes(map[string]string{"a": "a", "b": "b", "x": "x"}))
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("x"))
+	assert.NoError(t, x.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	// b is held back by the limit and still counts as ready after it is renamed.
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.Rename("c"))
+	assert.InstanceOf[*dgraph.ErrNodeAlreadyReady](t, b.ConnectDependency("x", dgraph.AndDependency))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"c"})
+}
+
+func TestDirectedGraph_CriticalPathPriority(t *testing.T) {
+	weights := map[string]float64{"short": 1, "long": 1, "long.1": 1, "long.2": 1, "heavy": 5}
+	d := dgraph.New[string](dgraph.WithCriticalPathPriority(func(nodeID string) float64 {
+		return weights[nodeID]
+	}))
+	for _, id := range []string{"short", "long", "long.1", "long.2", "heavy"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	long1 := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("long.1"))
+	long2 := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("long.2"))
+	assert.NoError(t, long1.ConnectDependency("long", dgraph.AndDependency))
+	assert.NoError(t, long2.ConnectDependency("long.1", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"heavy", "long", "short"})
+}
+
+func TestDirectedGraph_CriticalPath(t *testing.T) {
+	weights := map[string]float64{"a": 1, "b": 1, "c": 1, "heavy": 5, "end": 1}
+	build := func(options ...dgraph.Option) dgraph.DirectedGraph[string] {
+		d := dgraph.New[string](options...)
+		for _, id := range []string{"a", "b", "c", "heavy", "end"} {
+			_, err := d.AddNode(id, id)
+			assert.NoError(t, err)
+		}
+		for _, connection := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "end"}, {"heavy", "end"}} {
+			n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(connection[1]))
+			assert.NoError(t, n.ConnectDependency(connection[0], dgraph.AndDependency))
+		}
+		return d
+	}
+	assert.Equals(t, nodeIDs(build().CriticalPath()), []string{"a", "b", "c", "end"})
+	weighted := build(dgraph.WithCriticalPathPriority(func(nodeID string) float64 {
+		return weights[nodeID]
+	}))
+	assert.Equals(t, nodeIDs(weighted.CriticalPath()), []string{"heavy", "end"})
+
+	d := build()
+	end := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("end"))
+	assert.NoError(t, end.Connect("a"))
+	assert.Nil(t, d.CriticalPath())
+}
+
+func TestDirectedGraph_Resources(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithResources(map[string]int{"gpu": 1, "lock:db": 1}))
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"a", "b", "c"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["a"].RequireResources("gpu:1", "lock:db"))
+	assert.NoError(t, nodes["b"].RequireResources("gpu"))
+	assert.Equals(t, nodes["b"].RequiredResources(), map[string]int{"gpu": 1})
+	assert.InstanceOf[*dgraph.ErrResourceUnavailable](t, nodes["c"].RequireResources("gpu:2"))
+	assert.InstanceOf[*dgraph.ErrInvalidResourceToken](t, nodes["c"].RequireResources("gpu:0"))
+
+	// Only one of the GPU nodes can be exposed at a time.
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting, "c": dgraph.Waiting})
+	assert.Equals(t, d.ResourcesInUse(), map[string]int{"gpu": 1, "lock:db": 1})
+	assert.Equals(t, d.HasReadyNodes(), false)
+	// The requirements of a node cannot change while it holds its tokens.
+	assert.InstanceOf[*dgraph.ErrResourcesHeld](t, nodes["a"].RequireResources("gpu"))
+	assert.Equals(t, nodes["a"].RequiredResources(), map[string]int{"gpu": 1, "lock:db": 1})
+
+	// Resolving the node releases its tokens, which exposes the other one.
+	assert.NoError(t, nodes["a"].ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"b": dgraph.Waiting})
+	assert.NoError(t, nodes["b"].ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.ResourcesInUse(), map[string]int{})
+}
+
+func TestDirectedGraph_Gate(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.Equals(t, d.IsGateOpen(), true)
+	d.SetGate(false)
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{})
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.HasReadyNodes(), false)
+	d.SetGate(true)
+	assert.Equals(t, d.IsGateOpen(), true)
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Resolved,
+		"b": dgraph.Waiting,
+	})
+}
+
+func TestDirectedGraph_PopReadyNodesWithReasons(t *testing.T) {
+	d := dgraph.New[string]()
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"a", "b", "and", "or", "failed"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["and"].ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, nodes["or"].ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, nodes["or"].ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, nodes["failed"].ConnectDependency("b", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesWithReasons(), map[string]dgraph.ReadyNodeInfo{
+		"a": {Status: dgraph.Waiting, Reason: dgraph.ReadyNoDependencies},
+		"b": {Status: dgraph.Waiting, Reason: dgraph.ReadyNoDependencies},
+	})
+
+	assert.NoError(t, nodes["a"].ResolveNode(dgraph.Resolved))
+	assert.NoError(t, nodes["b"].ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, d.PopReadyNodesWithReasons(), map[string]dgraph.ReadyNodeInfo{
+		"and":    {Status: dgraph.Waiting, Reason: dgraph.ReadyDependenciesResolved},
+		"or":     {Status: dgraph.Waiting, Reason: dgraph.ReadyOrSatisfied},
+		"failed": {Status: dgraph.Unresolvable, Reason: dgraph.ReadyUnresolvableDependency},
+	})
+	assert.Equals(t, nodes["or"].ReadyReason(), dgraph.ReadyOrSatisfied)
+}
+
+// recordingReadySet is a ReadySet that records the IDs it was given, as a durable queue would.
+type recordingReadySet struct {
+	dgraph.ReadySet
+	added []string
+}
+
+func (s *recordingReadySet) Add(nodeID string) {
+	s.added = append(s.added, nodeID)
+	s.ReadySet.Add(nodeID)
+}
+
+func TestDirectedGraph_ReadySet(t *testing.T) {
+	readySet := &recordingReadySet{ReadySet: dgraph.NewMemoryReadySet()}
+	d := dgraph.New[string](dgraph.WithReadySet(func() dgraph.ReadySet {
+		return readySet
+	}))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, readySet.Len(), 2)
+
+	// Removed nodes are taken out of the ready set.
+	assert.NoError(t, c.Remove())
+	assert.Equals(t, readySet.List(), []string{"a"})
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting})
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"b": dgraph.Waiting})
+	assert.Equals(t, readySet.added, []string{"a", "c", "b"})
+}
+
+func TestDirectedGraph_PopReadyNodesWhere(t *testing.T) {
+	d := dgraph.New[string]()
+	for id, item := range map[string]string{"build": "container", "lint": "plugin", "test": "container"} {
+		_, err := d.AddNode(id, item)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesWhere(func(n dgraph.Node[string]) bool {
+		return n.Item() == "container"
+	}), map[string]dgraph.ResolutionStatus{
+		"build": dgraph.Waiting,
+		"test":  dgraph.Waiting,
+	})
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"lint"})
+}
+
+func TestDirectedGraph_PopReadyNodesInGroup(t *testing.T) {
+	d := dgraph.New[string]()
+	plugins := assert.NoErrorR[dgraph.Group](t)(d.AddGroup("plugins"))
+	for _, id := range []string{"a", "b"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, plugins.AddMember("b"))
+	assert.NoError(t, d.PushStartingNodes())
+	_, err := d.PopReadyNodesInGroup("missing")
+	assert.InstanceOf[*dgraph.ErrGroupNotFound](t, err)
+	assert.Equals(t, assert.NoErrorR[map[string]dgraph.ResolutionStatus](t)(d.PopReadyNodesInGroup("plugins")),
+		map[string]dgraph.ResolutionStatus{"b": dgraph.Waiting})
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+}
+
+func TestDirectedGraph_PopReadyNodeObjects(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "item a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "item b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "item c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+
+	ready := d.PopReadyNodeObjects()
+	assert.Equals(t, len(ready), 2)
+	assert.Equals(t, ready[0].Node.Item(), "item a")
+	assert.Equals(t, ready[0].Status, dgraph.Waiting)
+	assert.Equals(t, ready[0].Reason, dgraph.ReadyNoDependencies)
+	assert.Equals(t, ready[1].Node.ID(), "b")
+	assert.Equals(t, len(d.PopReadyNodeObjects()), 0)
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	ready = d.PopReadyNodeObjects()
+	assert.Equals(t, len(ready), 1)
+	assert.Equals(t, ready[0].Node.ID(), "c")
+	assert.Equals(t, ready[0].Reason, dgraph.ReadyDependenciesResolved)
+	assert.Equals(t, ready[0].ResolvedDependencies, map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+}
+
+func TestDirectedGraph_PopNReadyNodes(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithMaxReadyNodes(3))
+	for _, nodeID := range []string{"a", "b", "c", "d"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, len(d.PopNReadyNodes(0)), 0)
+	assert.Equals(t, len(d.PopNReadyNodes(-1)), 0)
+	assert.Equals(t, d.PopNReadyNodes(2), map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Waiting,
+		"b": dgraph.Waiting,
+	})
+	// Popping frees slots for the held back node d.
+	assert.Equals(t, d.PopNReadyNodes(5), map[string]dgraph.ResolutionStatus{
+		"c": dgraph.Waiting,
+		"d": dgraph.Waiting,
+	})
+	assert.Equals(t, d.HasReadyNodes(), false)
+}
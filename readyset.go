@@ -0,0 +1,72 @@
+package dgraph
+
+// ReadySet stores the IDs of the nodes that are ready, but have not been popped yet. Implementations can back it
+// with a durable queue, for example to dispatch ready nodes to remote workers. The methods are called while the
+// lock of the graph is held, so they must not call the graph. Read-only functions of the graph only hold the read
+// lock, so Contains, Len, and List can be called concurrently with each other. See WithReadySet.
+type ReadySet interface {
+	// Add adds the node ID to the set. Adding an ID that is already in the set has no effect.
+	Add(nodeID string)
+	// Remove removes the node ID from the set. Removing an ID that is not in the set has no effect.
+	Remove(nodeID string)
+	// Contains returns true if the node ID is in the set.
+	Contains(nodeID string) bool
+	// Len returns the number of node IDs in the set.
+	Len() int
+	// List returns all node IDs in the set in any order.
+	List() []string
+	// Clear removes all node IDs from the set.
+	Clear()
+}
+
+// WithReadySet replaces the in-memory ready set with the sets created by the factory. The factory is called once
+// for the graph, and again for every clone. The ready set is only used for nodes that exist in the graph, so it
+// must start empty.
+func WithReadySet(factory func() ReadySet) Option {
+	return func(c *config) {
+		c.readySetFactory = factory
+	}
+}
+
+func (c config) newReadySet() ReadySet {
+	if c.readySetFactory == nil {
+		return NewMemoryReadySet()
+	}
+	return c.readySetFactory()
+}
+
+// NewMemoryReadySet creates the in-memory ReadySet used by default. It is not safe for concurrent use on its own.
+func NewMemoryReadySet() ReadySet {
+	return memoryReadySet{}
+}
+
+type memoryReadySet map[string]struct{}
+
+func (s memoryReadySet) Add(nodeID string) {
+	s[nodeID] = struct{}{}
+}
+
+func (s memoryReadySet) Remove(nodeID string) {
+	delete(s, nodeID)
+}
+
+func (s memoryReadySet) Contains(nodeID string) bool {
+	_, ok := s[nodeID]
+	return ok
+}
+
+func (s memoryReadySet) Len() int {
+	return len(s)
+}
+
+func (s memoryReadySet) List() []string {
+	result := make([]string, 0, len(s))
+	for nodeID := range s {
+		result = append(result, nodeID)
+	}
+	return result
+}
+
+func (s memoryReadySet) Clear() {
+	clear(s)
+}
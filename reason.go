@@ -0,0 +1,31 @@
+package dgraph
+
+func (n *node[NodeType]) ResolveNodeWithReason(status ResolutionStatus, reason error) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	n.dg.checkDeadline()
+	// The reason is set before the resolution is propagated, so that the dependents can pick it up.
+	if n.status == Waiting && status == Unresolvable {
+		n.reason = reason
+	}
+	return n.resolveNode(status)
+}
+
+func (n *node[NodeType]) UnresolvableReason() error {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.unresolvableReason()
+}
+
+// unresolvableReason returns the reason passed to ResolveNodeWithReason, or the cause if the graph made the node
+// unresolvable.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) unresolvableReason() error {
+	if n.status != Unresolvable {
+		return nil
+	}
+	if n.reason != nil {
+		return n.reason
+	}
+	return n.unresolvableCause
+}
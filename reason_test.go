@@ -0,0 +1,57 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestNode_ResolveNodeWithReason(t *testing.T) {
+	d := dgraph.New[string]()
+	nodes := map[string]dgraph.Node[string]{}
+	for _, id := range []string{"a", "b", "c"} {
+		nodes[id] = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, nodes["b"].ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, nodes["c"].ConnectDependency("b", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	assert.Nil(t, nodes["a"].UnresolvableReason())
+
+	reason := errors.New("step crashed")
+	assert.NoError(t, nodes["a"].ResolveNodeWithReason(dgraph.Unresolvable, reason))
+	assert.Equals(t, nodes["a"].UnresolvableReason(), reason)
+	// The node failed on its own, so it has no cause and can be retried.
+	assert.Nil(t, nodes["a"].UnresolvableCause())
+	// The reason is propagated through the causes of the dependents.
+	assert.Equals(t, nodes["c"].ResolutionStatus(), dgraph.Unresolvable)
+	assert.Equals(t, errors.Is(nodes["b"].UnresolvableReason(), reason), true)
+	assert.Equals(t, errors.Is(nodes["c"].UnresolvableReason(), reason), true)
+	assert.Contains(t, nodes["b"].UnresolvableCause().Error(), "step crashed")
+
+	assert.NoError(t, nodes["a"].ResetResolution())
+	assert.Nil(t, nodes["a"].UnresolvableReason())
+	assert.Nil(t, nodes["c"].UnresolvableReason())
+}
+
+func TestNode_ResolveNodeWithReason_Expression(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "x"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	x := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("x"))
+	assert.NoError(t, x.ConnectExpression(dgraph.Or("a", "b")))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	reasonA := errors.New("a failed")
+	reasonB := errors.New("b failed")
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	assert.NoError(t, a.ResolveNodeWithReason(dgraph.Unresolvable, reasonA))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	assert.NoError(t, b.ResolveNodeWithReason(dgraph.Unresolvable, reasonB))
+	// The reasons of all unresolvable dependencies are aggregated.
+	assert.Equals(t, errors.Is(x.UnresolvableReason(), reasonA), true)
+	assert.Equals(t, errors.Is(x.UnresolvableReason(), reasonB), true)
+}
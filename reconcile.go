@@ -0,0 +1,110 @@
+package dgraph
+
+// Reconcile recomputes the outstanding dependencies and the readiness of all nodes whose inbound connections
+// changed since the last call.
+func (d *directedGraph[NodeType]) Reconcile() error {
+	d.lock.Lock()
+	defer d.unlock()
+
+	// Nodes are only forgotten once they are reconciled, so that the remaining ones are retried after an error.
+	for _, nodeID := range seededKeys(d.randomSource, d.changedNodes) {
+		if n, ok := d.nodes[nodeID]; ok {
+			if err := n.reconcile(); err != nil {
+				return err
+			}
+		}
+		delete(d.changedNodes, nodeID)
+	}
+	return nil
+}
+
+// reconcile brings the outstanding dependencies of the node in line with its inbound connections. Dependencies
+// that are no longer connected are dropped, and dependencies on nodes that were already resolved are applied as
+// if the resolution had happened after the connection was made. The expression of the node is re-evaluated, since
+// removed dependencies may have decided it. Finally, the node is marked unresolvable if all of its OR dependencies
+// failed, or ready if no hard dependencies remain and the graph has been started.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) reconcile() error {
+	inboundConnections := n.dg.connectionsToNode[n.id]
+	for dependencyNodeID := range n.outstandingDependencies {
+		if _, isConnected := inboundConnections[dependencyNodeID]; !isConnected {
+			delete(n.outstandingDependencies, dependencyNodeID)
+		}
+	}
+	// Readiness of the node has already been reported, so resolved dependencies only need to be recorded.
+	alreadyReported := n.ready || n.status != Waiting
+	for _, dependencyNodeID := range seededKeys(n.dg.randomSource, n.outstandingDependencies) {
+		dependencyType, ok := n.outstandingDependencies[dependencyNodeID]
+		if !ok {
+			continue
+		}
+		dependencyStatus := n.dg.nodes[dependencyNodeID].status
+		// Cancelled dependencies are never resolved, so the node keeps waiting for them.
+		if dependencyStatus == Waiting || dependencyStatus == Cancelled {
+			continue
+		}
+		if _, pending := n.dg.pendingData[[2]string{dependencyNodeID, n.id}]; pending {
+			continue
+		}
+		if alreadyReported {
+			delete(n.outstandingDependencies, dependencyNodeID)
+			if dependencyStatus == Resolved {
+				n.recordResolvedDependency(dependencyNodeID, dependencyType)
+			} else {
+				n.failedDependencies[dependencyNodeID] = dependencyType
+			}
+			n.traceDependency(dependencyNodeID, dependencyType, dependencyStatus, DependencyIgnored)
+		} else if !n.dg.isDataReady(dependencyNodeID, n.id, dependencyStatus) {
+			continue
+		} else if err := n.dependencyResolved(dependencyNodeID, dependencyStatus); err != nil {
+			return err
+		}
+	}
+	if err := n.reconcileExpression(); err != nil {
+		return err
+	}
+	return n.markReadyIfSatisfied()
+}
+
+// markReadyIfSatisfied marks the node unresolvable if all of its OR dependencies failed, and otherwise ready if no
+// hard dependencies remain and the graph has been started.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) markReadyIfSatisfied() error {
+	if n.ready {
+		return nil
+	}
+	if failedNodeID, ok := n.failedOrDependency(); ok && n.status == Waiting {
+		if n.unresolvableCause == nil {
+			n.unresolvableCause = &ErrDependencyUnresolvable{
+				n.id, failedNodeID, OrDependency, n.dg.nodes[failedNodeID].unresolvableReason(),
+			}
+		}
+		n.markReady(ReadyUnresolvableDependency)
+		return n.resolveNode(Unresolvable)
+	}
+	if !n.dg.started || n.external || n.hasOutstandingHardDependency() {
+		return nil
+	}
+	if len(n.resolvedDependencies) > 0 || len(n.failedDependencies) > 0 {
+		n.markReady(ReadyDependenciesResolved)
+	} else {
+		n.markReady(ReadyNoDependencies)
+	}
+	return nil
+}
+
+// failedOrDependency returns the lowest ID of the failed OR dependencies of the node if none of its OR dependencies
+// can satisfy it anymore, because all that are still connected failed.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) failedOrDependency() (string, bool) {
+	if n.satisfyingOrDependency != "" || n.hasOutstandingDependency(OrDependency) {
+		return "", false
+	}
+	for _, dependencyNodeID := range sortedKeys(n.failedDependencies) {
+		_, isConnected := n.dg.connectionsToNode[n.id][dependencyNodeID]
+		if isConnected && n.failedDependencies[dependencyNodeID] == OrDependency {
+			return dependencyNodeID, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Reconcile(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	e := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("e", "e"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"a": dgraph.Waiting,
+		"b": dgraph.Waiting,
+		"e": dgraph.Waiting,
+	})
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, d.HasReadyNodes(), false)
+
+	// Removing the outstanding dependency leaves only the resolved one.
+	assert.NoError(t, c.DisconnectInbound(b.ID()))
+	// A dependency on an already resolved node is applied on reconciliation.
+	f := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("f", "f"))
+	assert.NoError(t, f.ConnectDependency(a.ID(), dgraph.AndDependency))
+	// A dependency on an unresolvable node propagates.
+	g := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("g", "g"))
+	assert.NoError(t, e.ResolveNode(dgraph.Unresolvable))
+	assert.NoError(t, g.ConnectDependency(e.ID(), dgraph.AndDependency))
+	assert.Equals(t, d.HasReadyNodes(), false)
+
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"c": dgraph.Waiting,
+		"f": dgraph.Waiting,
+		"g": dgraph.Unresolvable,
+	})
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+	assert.Equals(t, f.ResolvedDependencies(), map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+}
+
+func TestDirectedGraph_Reconcile_AddedNode(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+
+	// A node added after the start without dependencies becomes ready on reconciliation.
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, b.IsReady(), true)
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"b"})
+}
+
+func TestDirectedGraph_Reconcile_ReadyNode(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, d.PushStartingNodes())
+	// Starting nodes are marked ready.
+	assert.Equals(t, a.IsReady(), true)
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+
+	// A dependency connected to a node that is already ready is recorded, but doesn't report it again.
+	assert.NoError(t, a.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, a.OutstandingDependencies(), map[string]dgraph.DependencyType{})
+	assert.Equals(t, a.ResolvedDependencies(), map[string]dgraph.DependencyType{"b": dgraph.AndDependency})
+	assert.Equals(t, d.HasReadyNodes(), false)
+}
+
+func TestDirectedGraph_Reconcile_FailedOrDependencies(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.OrDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, c.ResolutionStatus(), dgraph.Waiting)
+
+	// The only OR dependency that could still satisfy the node is removed, so every remaining one has failed.
+	assert.NoError(t, c.DisconnectInbound("b"))
+	assert.NoError(t, d.Reconcile())
+	assert.Equals(t, c.ResolutionStatus(), dgraph.Unresolvable)
+	assert.InstanceOf[*dgraph.ErrDependencyUnresolvable](t, c.UnresolvableCause())
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"c": dgraph.Unresolvable})
+}
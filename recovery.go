@@ -0,0 +1,60 @@
+package dgraph
+
+// ResolutionRecord is a past resolution of a node, which ApplyResolutions replays.
+type ResolutionRecord struct {
+	NodeID string
+	Status ResolutionStatus
+}
+
+func (d *directedGraph[NodeType]) ApplyResolutions(history []ResolutionRecord) error {
+	d.lock.Lock()
+	defer d.unlock()
+	// Replay the history on a copy first, so that the graph is left unchanged if any record is invalid. The copy
+	// doesn't use the ReadySet of the graph, which may have effects outside of it.
+	if err := d.copyState(NewMemoryReadySet()).applyResolutions(history); err != nil {
+		return err
+	}
+	return d.applyResolutions(history)
+}
+
+// applyResolutions starts the graph if needed and replays the history.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) applyResolutions(history []ResolutionRecord) error {
+	if !d.started {
+		d.pushStartingNodes()
+	}
+	for i, record := range history {
+		if err := d.applyResolution(record); err != nil {
+			return &ErrInvalidResolutionRecord{i, record, err}
+		}
+	}
+	// The resolved nodes have already been processed before the crash, so only the nodes that were ready, but not
+	// resolved, remain in the ready set.
+	for _, n := range d.nodes {
+		if n.status != Waiting && d.isPendingReady(n.id) {
+			d.removeReady(n.id)
+		}
+	}
+	return nil
+}
+
+// applyResolution validates the record against the current state and resolves the node.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) applyResolution(record ResolutionRecord) error {
+	n, ok := d.nodes[d.config.normalizeID(record.NodeID)]
+	if !ok {
+		return d.nodeNotFound(record.NodeID)
+	}
+	switch {
+	case record.Status != Resolved && record.Status != Unresolvable:
+		return &ErrInvalidResolutionStatus{n.id, record.Status}
+	case record.Status == Unresolvable && n.status == Unresolvable:
+		// The node was already marked unresolvable by the failure of a dependency replayed earlier.
+		return nil
+	case n.status != Waiting:
+		return &ErrNodeResolutionAlreadySet{n.id, n.status, record.Status}
+	case record.Status == Resolved && !n.ready:
+		return &ErrNodeNotReady{n.id}
+	}
+	return n.resolveNode(record.Status)
+}
@@ -0,0 +1,74 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// recoveryGraph builds a graph in which b and c depend on a, d depends on c, and y depends on x.
+func recoveryGraph(t *testing.T, options ...dgraph.Option) dgraph.DirectedGraph[string] {
+	d := dgraph.New[string](options...)
+	for _, id := range []string{"a", "b", "c", "d", "x", "y"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	for _, connection := range [][2]string{{"a", "b"}, {"a", "c"}, {"c", "d"}, {"x", "y"}} {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(connection[1]))
+		assert.NoError(t, n.ConnectDependency(connection[0], dgraph.AndDependency))
+	}
+	return d
+}
+
+func TestDirectedGraph_ApplyResolutions(t *testing.T) {
+	d := recoveryGraph(t)
+	assert.NoError(t, d.ApplyResolutions([]dgraph.ResolutionRecord{
+		{NodeID: "a", Status: dgraph.Resolved},
+		{NodeID: "x", Status: dgraph.Unresolvable},
+		// y was marked unresolvable by the failure of x.
+		{NodeID: "y", Status: dgraph.Unresolvable},
+		{NodeID: "b", Status: dgraph.Resolved},
+	}))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"c": dgraph.Waiting})
+	assert.Equals(t, d.ResolutionOrder(), []string{"a", "x", "y", "b"})
+}
+
+func TestDirectedGraph_ApplyResolutions_ReadySet(t *testing.T) {
+	readySets := 0
+	d := recoveryGraph(t, dgraph.WithReadySet(func() dgraph.ReadySet {
+		readySets++
+		return dgraph.NewMemoryReadySet()
+	}))
+	assert.NoError(t, d.ApplyResolutions([]dgraph.ResolutionRecord{{NodeID: "a", Status: dgraph.Resolved}}))
+	// The history is validated without creating another ready set.
+	assert.Equals(t, readySets, 1)
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{
+		"b": dgraph.Waiting,
+		"c": dgraph.Waiting,
+		"x": dgraph.Waiting,
+	})
+}
+
+func TestDirectedGraph_ApplyResolutionsInvalid(t *testing.T) {
+	for name, history := range map[string][]dgraph.ResolutionRecord{
+		"not ready": {{NodeID: "a", Status: dgraph.Resolved}, {NodeID: "d", Status: dgraph.Resolved}},
+		"duplicate": {{NodeID: "a", Status: dgraph.Resolved}, {NodeID: "a", Status: dgraph.Resolved}},
+		"not found": {{NodeID: "a", Status: dgraph.Resolved}, {NodeID: "e", Status: dgraph.Resolved}},
+		"waiting":   {{NodeID: "a", Status: dgraph.Resolved}, {NodeID: "b", Status: dgraph.Waiting}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d := recoveryGraph(t)
+			err := d.ApplyResolutions(history)
+			var invalid *dgraph.ErrInvalidResolutionRecord
+			assert.Equals(t, errors.As(err, &invalid), true)
+			assert.Equals(t, invalid.Index, 1)
+			assert.Equals(t, invalid.Record, history[1])
+			// The valid record before the invalid one is not applied either.
+			assert.Equals(t, len(d.ResolutionOrder()), 0)
+			a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+			assert.Equals(t, a.ResolutionStatus(), dgraph.Waiting)
+		})
+	}
+}
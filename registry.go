@@ -0,0 +1,105 @@
+package dgraph
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Registry tracks named graphs, so that engines running many graphs at once can inspect all of them in one place.
+// It is safe for concurrent use. Create it with NewRegistry.
+type Registry[NodeType any] struct {
+	lock   sync.RWMutex
+	graphs map[string]DirectedGraph[NodeType]
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry[NodeType any]() *Registry[NodeType] {
+	return &Registry[NodeType]{
+		graphs: map[string]DirectedGraph[NodeType]{},
+	}
+}
+
+// Register adds the graph under the specified name. If a graph is already registered under the name, an
+// ErrGraphAlreadyRegistered is returned.
+func (r *Registry[NodeType]) Register(name string, d DirectedGraph[NodeType]) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.graphs[name]; ok {
+		return &ErrGraphAlreadyRegistered{name}
+	}
+	r.graphs[name] = d
+	return nil
+}
+
+// Unregister removes the graph registered under the specified name. If there is none, an ErrGraphNotRegistered
+// is returned.
+func (r *Registry[NodeType]) Unregister(name string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.graphs[name]; !ok {
+		return &ErrGraphNotRegistered{name}
+	}
+	delete(r.graphs, name)
+	return nil
+}
+
+// Get returns the graph registered under the specified name. If there is none, an ErrGraphNotRegistered is
+// returned.
+func (r *Registry[NodeType]) Get(name string) (DirectedGraph[NodeType], error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	d, ok := r.graphs[name]
+	if !ok {
+		return nil, &ErrGraphNotRegistered{name}
+	}
+	return d, nil
+}
+
+// Names returns the names of all registered graphs in ascending order.
+func (r *Registry[NodeType]) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return sortedKeys(r.graphs)
+}
+
+// Health returns the Health of each registered graph, keyed by name, which can be exported as metrics.
+func (r *Registry[NodeType]) Health() map[string]Health {
+	result := map[string]Health{}
+	for name, d := range r.snapshot() {
+		result[name] = d.Health()
+	}
+	return result
+}
+
+// TotalReadyNodes returns the number of ready nodes that have not been popped yet across all registered graphs.
+func (r *Registry[NodeType]) TotalReadyNodes() int {
+	total := 0
+	for _, d := range r.snapshot() {
+		total += d.Health().QueueDepth
+	}
+	return total
+}
+
+// Stalled returns the names of the registered graphs for which DirectedGraph.Ping fails with the specified idle
+// time, that is, the graphs that have unfinished nodes, but made no progress for longer than maxIdle. The names
+// are sorted in ascending order.
+func (r *Registry[NodeType]) Stalled(maxIdle time.Duration) []string {
+	var result []string
+	for name, d := range r.snapshot() {
+		if d.Ping(maxIdle) != nil {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// snapshot returns a copy of the registered graphs, so that the graphs can be queried without holding the lock of
+// the registry.
+func (r *Registry[NodeType]) snapshot() map[string]DirectedGraph[NodeType] {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return maps.Clone(r.graphs)
+}
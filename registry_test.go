@@ -0,0 +1,41 @@
+package dgraph_test
+
+import (
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestRegistry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	registry := dgraph.NewRegistry[string]()
+	idle := dgraph.New[string](dgraph.WithClock(clock.Now))
+	assert.NoErrorR[dgraph.Node[string]](t)(idle.AddNode("a", "a"))
+	assert.NoErrorR[dgraph.Node[string]](t)(idle.AddNode("b", "b"))
+	assert.NoError(t, idle.PushStartingNodes())
+	busy := dgraph.New[string](dgraph.WithClock(clock.Now))
+	assert.NoErrorR[dgraph.Node[string]](t)(busy.AddNode("c", "c"))
+
+	assert.NoError(t, registry.Register("idle", idle))
+	assert.NoError(t, registry.Register("busy", busy))
+	assert.InstanceOf[*dgraph.ErrGraphAlreadyRegistered](t, registry.Register("idle", busy))
+	assert.Equals(t, registry.Names(), []string{"busy", "idle"})
+	assert.Equals(t, assert.NoErrorR[dgraph.DirectedGraph[string]](t)(registry.Get("idle")) == idle, true)
+
+	assert.Equals(t, registry.TotalReadyNodes(), 2)
+	assert.NoError(t, busy.PushStartingNodes())
+	assert.Equals(t, registry.TotalReadyNodes(), 3)
+	assert.Equals(t, registry.Health()["busy"].QueueDepth, 1)
+
+	clock.Advance(time.Minute)
+	busy.PopReadyNodes()
+	assert.Equals(t, registry.Stalled(30*time.Second), []string{"idle"})
+
+	assert.NoError(t, registry.Unregister("idle"))
+	assert.InstanceOf[*dgraph.ErrGraphNotRegistered](t, registry.Unregister("idle"))
+	_, err := registry.Get("idle")
+	assert.InstanceOf[*dgraph.ErrGraphNotRegistered](t, err)
+	assert.Equals(t, registry.Names(), []string{"busy"})
+}
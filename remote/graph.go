@@ -0,0 +1,921 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.arcalot.io/dgraph"
+)
+
+// ErrReadOnly is returned by the functions of a Graph and its nodes, edges, and groups that would change the
+// graph.
+type ErrReadOnly struct {
+	Operation string
+}
+
+func (e ErrReadOnly) Error() string {
+	return e.Operation + " is not supported by a remote graph, which is read-only"
+}
+
+// Graph is a read-only DirectedGraph showing the state of a served graph at the time of the last Fetch or
+// Refresh. Functions that would change the graph or take part in its execution return an ErrReadOnly. Those that
+// don't return an error, such as Reset, SetGate, the Pop functions, Subscribe, and the listener functions, panic
+// with an *ErrReadOnly instead. Nodes, edges, and groups returned by the Graph always show the state of the last
+// refresh. Functions that return a new graph, such as Clone and Partition,
+// return an independent graph that can be changed.
+type Graph[NodeType any] struct {
+	state *graphState[NodeType]
+	// prefix is the prefix of the namespace shown by the Graph, or empty for the whole graph.
+	prefix string
+}
+
+// graphState is the state shared by a Graph and its namespaces.
+type graphState[NodeType any] struct {
+	client *Client[NodeType]
+	path   string
+
+	lock     sync.RWMutex
+	snapshot dgraph.DirectedGraph[NodeType]
+	etag     string
+	// refreshed is closed and replaced whenever Refresh changes the snapshot.
+	refreshed chan struct{}
+}
+
+// Refresh fetches the current state of the served graph. If the generation of the served graph has not changed
+// since the last refresh, the server answers with 304 Not Modified and the current snapshot is kept.
+func (g *Graph[NodeType]) Refresh(ctx context.Context) error {
+	s := g.state
+	s.lock.RLock()
+	etag := s.etag
+	s.lock.RUnlock()
+	snapshot, etag, err := s.client.get(ctx, s.path, etag)
+	if err != nil || snapshot == nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.snapshot = snapshot
+	s.etag = etag
+	close(s.refreshed)
+	s.refreshed = make(chan struct{})
+	return nil
+}
+
+// current returns the snapshot shown by the Graph and the channel closed by the next refresh.
+func (g *Graph[NodeType]) current() (dgraph.DirectedGraph[NodeType], <-chan struct{}) {
+	g.state.lock.RLock()
+	defer g.state.lock.RUnlock()
+	if g.prefix == "" {
+		return g.state.snapshot, g.state.refreshed
+	}
+	return g.state.snapshot.Namespace(g.prefix), g.state.refreshed
+}
+
+// snapshot returns the snapshot shown by the Graph.
+func (g *Graph[NodeType]) snapshot() dgraph.DirectedGraph[NodeType] {
+	snapshot, _ := g.current()
+	return snapshot
+}
+
+func (g *Graph[NodeType]) wrapNode(n dgraph.Node[NodeType]) dgraph.Node[NodeType] {
+	return &graphNode[NodeType]{g, n.ID(), n}
+}
+
+func (g *Graph[NodeType]) wrapNodes(nodes map[string]dgraph.Node[NodeType]) map[string]dgraph.Node[NodeType] {
+	if nodes == nil {
+		return nil
+	}
+	result := make(map[string]dgraph.Node[NodeType], len(nodes))
+	for nodeID, n := range nodes {
+		result[nodeID] = g.wrapNode(n)
+	}
+	return result
+}
+
+func (g *Graph[NodeType]) wrapNodeList(nodes []dgraph.Node[NodeType]) []dgraph.Node[NodeType] {
+	if nodes == nil {
+		return nil
+	}
+	result := make([]dgraph.Node[NodeType], len(nodes))
+	for i, n := range nodes {
+		result[i] = g.wrapNode(n)
+	}
+	return result
+}
+
+func (g *Graph[NodeType]) wrapEdge(e dgraph.Edge) dgraph.Edge {
+	return &graphEdge[NodeType]{g, e.SourceNodeID(), e.DestinationNodeID(), e}
+}
+
+func (g *Graph[NodeType]) wrapVisit(
+	visit func(node dgraph.Node[NodeType]) error,
+) func(node dgraph.Node[NodeType]) error {
+	return func(n dgraph.Node[NodeType]) error {
+		return visit(g.wrapNode(n))
+	}
+}
+
+func (g *Graph[NodeType]) AddNode(string, NodeType) (dgraph.Node[NodeType], error) {
+	return nil, &ErrReadOnly{"AddNode"}
+}
+
+func (g *Graph[NodeType]) AddNodes(map[string]NodeType) error {
+	return &ErrReadOnly{"AddNodes"}
+}
+
+func (g *Graph[NodeType]) ConnectDependencies(string, map[string]dgraph.DependencyType) error {
+	return &ErrReadOnly{"ConnectDependencies"}
+}
+
+func (g *Graph[NodeType]) AddFailureHandlers(
+	map[string]dgraph.FailureHandler[NodeType],
+) (map[string]dgraph.Node[NodeType], error) {
+	return nil, &ErrReadOnly{"AddFailureHandlers"}
+}
+
+func (g *Graph[NodeType]) GetNodeByID(id string) (dgraph.Node[NodeType], error) {
+	n, err := g.snapshot().GetNodeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return g.wrapNode(n), nil
+}
+
+func (g *Graph[NodeType]) AddGroup(string) (dgraph.Group, error) {
+	return nil, &ErrReadOnly{"AddGroup"}
+}
+
+func (g *Graph[NodeType]) GetGroup(name string) (dgraph.Group, error) {
+	group, err := g.snapshot().GetGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return &graphGroup[NodeType]{g, name, group}, nil
+}
+
+func (g *Graph[NodeType]) ListNodes() map[string]dgraph.Node[NodeType] {
+	return g.wrapNodes(g.snapshot().ListNodes())
+}
+
+func (g *Graph[NodeType]) Nodes() func(yield func(string, dgraph.Node[NodeType]) bool) {
+	nodes := g.ListNodes()
+	return func(yield func(string, dgraph.Node[NodeType]) bool) {
+		for nodeID, n := range nodes {
+			if !yield(nodeID, n) {
+				return
+			}
+		}
+	}
+}
+
+func (g *Graph[NodeType]) ListNodesWithoutInboundConnections() map[string]dgraph.Node[NodeType] {
+	return g.wrapNodes(g.snapshot().ListNodesWithoutInboundConnections())
+}
+
+func (g *Graph[NodeType]) Ancestors(nodeID string) (map[string]dgraph.Node[NodeType], error) {
+	nodes, err := g.snapshot().Ancestors(nodeID)
+	return g.wrapNodes(nodes), err
+}
+
+func (g *Graph[NodeType]) Descendants(nodeID string) (map[string]dgraph.Node[NodeType], error) {
+	nodes, err := g.snapshot().Descendants(nodeID)
+	return g.wrapNodes(nodes), err
+}
+
+func (g *Graph[NodeType]) WalkBFS(start string, visit func(node dgraph.Node[NodeType]) error) error {
+	return g.snapshot().WalkBFS(start, g.wrapVisit(visit))
+}
+
+func (g *Graph[NodeType]) WalkDFS(start string, visit func(node dgraph.Node[NodeType]) error) error {
+	return g.snapshot().WalkDFS(start, g.wrapVisit(visit))
+}
+
+func (g *Graph[NodeType]) StatusCounts(prefix string) dgraph.StatusCounts {
+	return g.snapshot().StatusCounts(prefix)
+}
+
+func (g *Graph[NodeType]) NodeStates() map[string]dgraph.NodeState {
+	return g.snapshot().NodeStates()
+}
+
+func (g *Graph[NodeType]) IsComplete(prefix string) bool {
+	return g.snapshot().IsComplete(prefix)
+}
+
+func (g *Graph[NodeType]) GetEdge(fromNodeID, toNodeID string) (dgraph.Edge, error) {
+	e, err := g.snapshot().GetEdge(fromNodeID, toNodeID)
+	if err != nil {
+		return nil, err
+	}
+	return g.wrapEdge(e), nil
+}
+
+func (g *Graph[NodeType]) ListConnections() []dgraph.Connection {
+	return g.snapshot().ListConnections()
+}
+
+func (g *Graph[NodeType]) Connections() func(yield func(dgraph.Connection) bool) {
+	return g.snapshot().Connections()
+}
+
+func (g *Graph[NodeType]) ListEdges() []dgraph.Edge {
+	edges := g.snapshot().ListEdges()
+	result := make([]dgraph.Edge, len(edges))
+	for i, e := range edges {
+		result[i] = g.wrapEdge(e)
+	}
+	return result
+}
+
+func (g *Graph[NodeType]) Edges() func(yield func(dgraph.Edge) bool) {
+	edges := g.ListEdges()
+	return func(yield func(dgraph.Edge) bool) {
+		for _, e := range edges {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+func (g *Graph[NodeType]) AdjacencyMatrix() ([][]bool, []string) {
+	return g.snapshot().AdjacencyMatrix()
+}
+
+func (g *Graph[NodeType]) EdgeSet() *dgraph.EdgeSet {
+	return g.snapshot().EdgeSet()
+}
+
+func (g *Graph[NodeType]) UndirectedView() dgraph.UndirectedView[NodeType] {
+	return &graphUndirectedView[NodeType]{g}
+}
+
+func (g *Graph[NodeType]) Generation() uint64 {
+	return g.snapshot().Generation()
+}
+
+// Namespace returns a read-only view of the namespace of the snapshot. It shares the snapshot with the Graph, so
+// refreshing either of them refreshes both.
+func (g *Graph[NodeType]) Namespace(prefix string) dgraph.DirectedGraph[NodeType] {
+	return &Graph[NodeType]{g.state, g.prefix + prefix}
+}
+
+func (g *Graph[NodeType]) NotifyDataReady(string, string) error {
+	return &ErrReadOnly{"NotifyDataReady"}
+}
+
+func (g *Graph[NodeType]) Diff(other dgraph.DirectedGraph[NodeType]) dgraph.GraphDiff {
+	return g.snapshot().Diff(other)
+}
+
+func (g *Graph[NodeType]) Merge(dgraph.DirectedGraph[NodeType], ...dgraph.MergeOption) error {
+	return &ErrReadOnly{"Merge"}
+}
+
+func (g *Graph[NodeType]) Degrees() map[string]dgraph.Degree {
+	return g.snapshot().Degrees()
+}
+
+func (g *Graph[NodeType]) ValidateBoundary(prefix string, contract dgraph.BoundaryContract) error {
+	return g.snapshot().ValidateBoundary(prefix, contract)
+}
+
+func (g *Graph[NodeType]) Reset() {
+	panic(&ErrReadOnly{"Reset"})
+}
+
+func (g *Graph[NodeType]) ResetExecution() {
+	panic(&ErrReadOnly{"ResetExecution"})
+}
+
+func (g *Graph[NodeType]) SaveState() ([]byte, error) {
+	return g.snapshot().SaveState()
+}
+
+func (g *Graph[NodeType]) RestoreState([]byte) error {
+	return &ErrReadOnly{"RestoreState"}
+}
+
+func (g *Graph[NodeType]) Clone() dgraph.DirectedGraph[NodeType] {
+	return g.snapshot().Clone()
+}
+
+func (g *Graph[NodeType]) CloneChecked() (dgraph.DirectedGraph[NodeType], error) {
+	return g.snapshot().CloneChecked()
+}
+
+func (g *Graph[NodeType]) ExportJSON() ([]byte, error) {
+	return g.snapshot().ExportJSON()
+}
+
+func (g *Graph[NodeType]) ExportJSONFiltered(filter dgraph.ExportFilter) ([]byte, error) {
+	return g.snapshot().ExportJSONFiltered(filter)
+}
+
+func (g *Graph[NodeType]) Compile() (*dgraph.ExecutionPlan[NodeType], error) {
+	return g.snapshot().Compile()
+}
+
+func (g *Graph[NodeType]) TopologicalSort() ([]dgraph.Node[NodeType], error) {
+	nodes, err := g.snapshot().TopologicalSort()
+	return g.wrapNodeList(nodes), err
+}
+
+func (g *Graph[NodeType]) HasCycles() bool {
+	return g.snapshot().HasCycles()
+}
+
+func (g *Graph[NodeType]) FindCycles() [][]string {
+	return g.snapshot().FindCycles()
+}
+
+func (g *Graph[NodeType]) Condense() (dgraph.DirectedGraph[[]string], map[string]string) {
+	return g.snapshot().Condense()
+}
+
+func (g *Graph[NodeType]) SuggestCycleBreaks() []dgraph.CycleBreak {
+	return g.snapshot().SuggestCycleBreaks()
+}
+
+func (g *Graph[NodeType]) ShortestPath(fromNodeID, toNodeID string) ([]dgraph.Node[NodeType], float64, error) {
+	nodes, weight, err := g.snapshot().ShortestPath(fromNodeID, toNodeID)
+	return g.wrapNodeList(nodes), weight, err
+}
+
+func (g *Graph[NodeType]) LongestPath(fromNodeID, toNodeID string) ([]dgraph.Node[NodeType], float64, error) {
+	nodes, weight, err := g.snapshot().LongestPath(fromNodeID, toNodeID)
+	return g.wrapNodeList(nodes), weight, err
+}
+
+func (g *Graph[NodeType]) CriticalPath() []dgraph.Node[NodeType] {
+	return g.wrapNodeList(g.snapshot().CriticalPath())
+}
+
+func (g *Graph[NodeType]) EstimateMakespan() (time.Duration, error) {
+	return g.snapshot().EstimateMakespan()
+}
+
+func (g *Graph[NodeType]) PopReadyNodes() map[string]dgraph.ResolutionStatus {
+	panic(&ErrReadOnly{"PopReadyNodes"})
+}
+
+func (g *Graph[NodeType]) PopReadyNodesWithReasons() map[string]dgraph.ReadyNodeInfo {
+	panic(&ErrReadOnly{"PopReadyNodesWithReasons"})
+}
+
+func (g *Graph[NodeType]) PopReadyNodesOrdered() []string {
+	panic(&ErrReadOnly{"PopReadyNodesOrdered"})
+}
+
+func (g *Graph[NodeType]) PopReadyNodeObjects() []dgraph.ReadyNode[NodeType] {
+	panic(&ErrReadOnly{"PopReadyNodeObjects"})
+}
+
+func (g *Graph[NodeType]) PopNReadyNodes(int) map[string]dgraph.ResolutionStatus {
+	panic(&ErrReadOnly{"PopNReadyNodes"})
+}
+
+func (g *Graph[NodeType]) PopReadyNodesWhere(func(dgraph.Node[NodeType]) bool) map[string]dgraph.ResolutionStatus {
+	panic(&ErrReadOnly{"PopReadyNodesWhere"})
+}
+
+func (g *Graph[NodeType]) PopReadyNodesInGroup(string) (map[string]dgraph.ResolutionStatus, error) {
+	return nil, &ErrReadOnly{"PopReadyNodesInGroup"}
+}
+
+func (g *Graph[NodeType]) ResourcesInUse() map[string]int {
+	return g.snapshot().ResourcesInUse()
+}
+
+func (g *Graph[NodeType]) HasReadyNodes() bool {
+	return g.snapshot().HasReadyNodes()
+}
+
+func (g *Graph[NodeType]) SetGate(bool) {
+	panic(&ErrReadOnly{"SetGate"})
+}
+
+func (g *Graph[NodeType]) IsGateOpen() bool {
+	return g.snapshot().IsGateOpen()
+}
+
+func (g *Graph[NodeType]) Drain(context.Context) error {
+	return &ErrReadOnly{"Drain"}
+}
+
+func (g *Graph[NodeType]) Cancel(string) error {
+	return &ErrReadOnly{"Cancel"}
+}
+
+func (g *Graph[NodeType]) CancelAll() {
+	panic(&ErrReadOnly{"CancelAll"})
+}
+
+func (g *Graph[NodeType]) Subscribe(context.Context) <-chan dgraph.Node[NodeType] {
+	panic(&ErrReadOnly{"Subscribe"})
+}
+
+func (g *Graph[NodeType]) OnNodeAdded(func(node dgraph.Node[NodeType])) {
+	panic(&ErrReadOnly{"OnNodeAdded"})
+}
+
+func (g *Graph[NodeType]) OnNodeReady(func(node dgraph.Node[NodeType])) {
+	panic(&ErrReadOnly{"OnNodeReady"})
+}
+
+func (g *Graph[NodeType]) OnNodeResolved(func(node dgraph.Node[NodeType], status dgraph.ResolutionStatus)) {
+	panic(&ErrReadOnly{"OnNodeResolved"})
+}
+
+func (g *Graph[NodeType]) OnConnect(
+	func(from dgraph.Node[NodeType], to dgraph.Node[NodeType], dependencyType dgraph.DependencyType),
+) {
+	panic(&ErrReadOnly{"OnConnect"})
+}
+
+func (g *Graph[NodeType]) OnResolved(func(node dgraph.Node[NodeType])) {
+	panic(&ErrReadOnly{"OnResolved"})
+}
+
+func (g *Graph[NodeType]) OnUnresolvable(func(node dgraph.Node[NodeType], cause error)) {
+	panic(&ErrReadOnly{"OnUnresolvable"})
+}
+
+func (g *Graph[NodeType]) OnSkipped(func(node dgraph.Node[NodeType], cause error)) {
+	panic(&ErrReadOnly{"OnSkipped"})
+}
+
+func (g *Graph[NodeType]) MarkOutput(string) error {
+	return &ErrReadOnly{"MarkOutput"}
+}
+
+func (g *Graph[NodeType]) Outputs() map[string]dgraph.OutputResult {
+	return g.snapshot().Outputs()
+}
+
+// WaitOutputs waits until all output nodes are resolved in the snapshot. Since the snapshot only changes when it
+// is refreshed, another goroutine has to call Refresh for WaitOutputs to return.
+func (g *Graph[NodeType]) WaitOutputs(ctx context.Context) (map[string]dgraph.OutputResult, error) {
+	for {
+		snapshot, refreshed := g.current()
+		outputs := snapshot.Outputs()
+		done := true
+		for _, output := range outputs {
+			if output.Status == dgraph.Waiting {
+				done = false
+			}
+		}
+		if done {
+			return outputs, nil
+		}
+		select {
+		case <-refreshed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (g *Graph[NodeType]) SetDeadline(time.Time) {
+	panic(&ErrReadOnly{"SetDeadline"})
+}
+
+func (g *Graph[NodeType]) Done() <-chan struct{} {
+	return g.snapshot().Done()
+}
+
+func (g *Graph[NodeType]) PushStartingNodes() error {
+	return &ErrReadOnly{"PushStartingNodes"}
+}
+
+func (g *Graph[NodeType]) ListStale(threshold time.Duration) []dgraph.StaleNode {
+	return g.snapshot().ListStale(threshold)
+}
+
+func (g *Graph[NodeType]) DurationStatistics(
+	tag func(node dgraph.Node[NodeType]) string,
+) map[string]dgraph.DurationStatistics {
+	return g.snapshot().DurationStatistics(func(n dgraph.Node[NodeType]) string {
+		return tag(g.wrapNode(n))
+	})
+}
+
+func (g *Graph[NodeType]) Stats() dgraph.Stats {
+	return g.snapshot().Stats()
+}
+
+func (g *Graph[NodeType]) Health() dgraph.Health {
+	return g.snapshot().Health()
+}
+
+func (g *Graph[NodeType]) Ping(maxIdle time.Duration) error {
+	return g.snapshot().Ping(maxIdle)
+}
+
+func (g *Graph[NodeType]) ResolutionOrder() []string {
+	return g.snapshot().ResolutionOrder()
+}
+
+func (g *Graph[NodeType]) Partition(nodeIDs []string) (dgraph.DirectedGraph[NodeType], error) {
+	return g.snapshot().Partition(nodeIDs)
+}
+
+func (g *Graph[NodeType]) ResolveExternal(string, dgraph.ResolutionStatus) error {
+	return &ErrReadOnly{"ResolveExternal"}
+}
+
+func (g *Graph[NodeType]) ApplyResolutions([]dgraph.ResolutionRecord) error {
+	return &ErrReadOnly{"ApplyResolutions"}
+}
+
+func (g *Graph[NodeType]) Reconcile() error {
+	return &ErrReadOnly{"Reconcile"}
+}
+
+func (g *Graph[NodeType]) Mermaid() string {
+	return g.snapshot().Mermaid()
+}
+
+func (g *Graph[NodeType]) MermaidFiltered(filter dgraph.ExportFilter) string {
+	return g.snapshot().MermaidFiltered(filter)
+}
+
+func (g *Graph[NodeType]) MermaidWithOptions(options dgraph.MermaidOptions[NodeType]) string {
+	return g.snapshot().MermaidWithOptions(options)
+}
+
+func (g *Graph[NodeType]) DOT() string {
+	return g.snapshot().DOT()
+}
+
+func (g *Graph[NodeType]) DOTFiltered(filter dgraph.ExportFilter) string {
+	return g.snapshot().DOTFiltered(filter)
+}
+
+// graphNode is a node of a Graph. It shows the node with the same ID in the current snapshot, or the node it was
+// created from if the node no longer exists.
+type graphNode[NodeType any] struct {
+	g    *Graph[NodeType]
+	id   string
+	last dgraph.Node[NodeType]
+}
+
+func (n *graphNode[NodeType]) current() dgraph.Node[NodeType] {
+	if current, err := n.g.snapshot().GetNodeByID(n.id); err == nil {
+		return current
+	}
+	return n.last
+}
+
+func (n *graphNode[NodeType]) ID() string {
+	return n.id
+}
+
+func (n *graphNode[NodeType]) Item() NodeType {
+	return n.current().Item()
+}
+
+func (n *graphNode[NodeType]) ResolutionStatus() dgraph.ResolutionStatus {
+	return n.current().ResolutionStatus()
+}
+
+func (n *graphNode[NodeType]) UnresolvableCause() error {
+	return n.current().UnresolvableCause()
+}
+
+func (n *graphNode[NodeType]) ReadyReason() dgraph.ReadyReason {
+	return n.current().ReadyReason()
+}
+
+func (n *graphNode[NodeType]) IsReady() bool {
+	return n.current().IsReady()
+}
+
+func (n *graphNode[NodeType]) Timings() dgraph.NodeTimings {
+	return n.current().Timings()
+}
+
+func (n *graphNode[NodeType]) SetExpectedDuration(time.Duration) error {
+	return &ErrReadOnly{"SetExpectedDuration"}
+}
+
+func (n *graphNode[NodeType]) ExpectedDuration() time.Duration {
+	return n.current().ExpectedDuration()
+}
+
+func (n *graphNode[NodeType]) Connect(string) error {
+	return &ErrReadOnly{"Connect"}
+}
+
+func (n *graphNode[NodeType]) ConnectDependency(string, dgraph.DependencyType) error {
+	return &ErrReadOnly{"ConnectDependency"}
+}
+
+func (n *graphNode[NodeType]) ConnectWithMetadata(string, map[string]string) error {
+	return &ErrReadOnly{"ConnectWithMetadata"}
+}
+
+func (n *graphNode[NodeType]) ConnectDependencyEdge(string, dgraph.DependencyType) (dgraph.Edge, error) {
+	return nil, &ErrReadOnly{"ConnectDependencyEdge"}
+}
+
+func (n *graphNode[NodeType]) ConnectDependencyIf(string, dgraph.DependencyCondition[NodeType]) error {
+	return &ErrReadOnly{"ConnectDependencyIf"}
+}
+
+func (n *graphNode[NodeType]) ConnectExpression(dgraph.Expression) error {
+	return &ErrReadOnly{"ConnectExpression"}
+}
+
+func (n *graphNode[NodeType]) DependencyExpression() (dgraph.Expression, bool) {
+	return n.current().DependencyExpression()
+}
+
+func (n *graphNode[NodeType]) ConnectGroupDependency(string, dgraph.GroupDependencyMode) error {
+	return &ErrReadOnly{"ConnectGroupDependency"}
+}
+
+func (n *graphNode[NodeType]) DisconnectInbound(string) error {
+	return &ErrReadOnly{"DisconnectInbound"}
+}
+
+func (n *graphNode[NodeType]) ObviateDependency(string) error {
+	return &ErrReadOnly{"ObviateDependency"}
+}
+
+func (n *graphNode[NodeType]) DisconnectOutbound(string) error {
+	return &ErrReadOnly{"DisconnectOutbound"}
+}
+
+func (n *graphNode[NodeType]) Remove() error {
+	return &ErrReadOnly{"Remove"}
+}
+
+func (n *graphNode[NodeType]) RequireResources(...string) error {
+	return &ErrReadOnly{"RequireResources"}
+}
+
+func (n *graphNode[NodeType]) RequiredResources() map[string]int {
+	return n.current().RequiredResources()
+}
+
+func (n *graphNode[NodeType]) IsExternal() bool {
+	return n.current().IsExternal()
+}
+
+func (n *graphNode[NodeType]) HasSelfLoop() bool {
+	return n.current().HasSelfLoop()
+}
+
+func (n *graphNode[NodeType]) CompleteIteration() error {
+	return &ErrReadOnly{"CompleteIteration"}
+}
+
+func (n *graphNode[NodeType]) Iterations() int {
+	return n.current().Iterations()
+}
+
+func (n *graphNode[NodeType]) Annotate(...string) error {
+	return &ErrReadOnly{"Annotate"}
+}
+
+func (n *graphNode[NodeType]) Annotations() []string {
+	return n.current().Annotations()
+}
+
+func (n *graphNode[NodeType]) SetDescription(string) error {
+	return &ErrReadOnly{"SetDescription"}
+}
+
+func (n *graphNode[NodeType]) Description() string {
+	return n.current().Description()
+}
+
+func (n *graphNode[NodeType]) SetAttr(string, string) error {
+	return &ErrReadOnly{"SetAttr"}
+}
+
+func (n *graphNode[NodeType]) Attr(key string) (string, bool) {
+	return n.current().Attr(key)
+}
+
+func (n *graphNode[NodeType]) Attrs() map[string]string {
+	return n.current().Attrs()
+}
+
+func (n *graphNode[NodeType]) InDegree() (int, error) {
+	return n.current().InDegree()
+}
+
+func (n *graphNode[NodeType]) OutDegree() (int, error) {
+	return n.current().OutDegree()
+}
+
+func (n *graphNode[NodeType]) ListInboundConnections() (map[string]dgraph.Node[NodeType], error) {
+	nodes, err := n.current().ListInboundConnections()
+	return n.g.wrapNodes(nodes), err
+}
+
+func (n *graphNode[NodeType]) ListOutboundConnections() (map[string]dgraph.Node[NodeType], error) {
+	nodes, err := n.current().ListOutboundConnections()
+	return n.g.wrapNodes(nodes), err
+}
+
+func (n *graphNode[NodeType]) ListInboundConnectionsOrdered() ([]dgraph.Node[NodeType], error) {
+	nodes, err := n.current().ListInboundConnectionsOrdered()
+	return n.g.wrapNodeList(nodes), err
+}
+
+func (n *graphNode[NodeType]) ListOutboundConnectionsOrdered() ([]dgraph.Node[NodeType], error) {
+	nodes, err := n.current().ListOutboundConnectionsOrdered()
+	return n.g.wrapNodeList(nodes), err
+}
+
+func (n *graphNode[NodeType]) Rename(string) error {
+	return &ErrReadOnly{"Rename"}
+}
+
+func (n *graphNode[NodeType]) ResolveNode(dgraph.ResolutionStatus) error {
+	return &ErrReadOnly{"ResolveNode"}
+}
+
+func (n *graphNode[NodeType]) ResolveNodeWithResult(dgraph.ResolutionStatus, any) error {
+	return &ErrReadOnly{"ResolveNodeWithResult"}
+}
+
+func (n *graphNode[NodeType]) ResetResolution() error {
+	return &ErrReadOnly{"ResetResolution"}
+}
+
+func (n *graphNode[NodeType]) ResolveNodeWithReason(dgraph.ResolutionStatus, error) error {
+	return &ErrReadOnly{"ResolveNodeWithReason"}
+}
+
+func (n *graphNode[NodeType]) UnresolvableReason() error {
+	return n.current().UnresolvableReason()
+}
+
+func (n *graphNode[NodeType]) Result() any {
+	return n.current().Result()
+}
+
+func (n *graphNode[NodeType]) OutstandingDependencies() map[string]dgraph.DependencyType {
+	return n.current().OutstandingDependencies()
+}
+
+func (n *graphNode[NodeType]) ResolvedDependencies() map[string]dgraph.DependencyType {
+	return n.current().ResolvedDependencies()
+}
+
+func (n *graphNode[NodeType]) ResolvedDependencyHistory() []dgraph.ResolvedDependency {
+	return n.current().ResolvedDependencyHistory()
+}
+
+func (n *graphNode[NodeType]) SatisfyingOrDependency() (string, bool) {
+	return n.current().SatisfyingOrDependency()
+}
+
+func (n *graphNode[NodeType]) ReadinessCondition() dgraph.ReadinessCondition {
+	return n.current().ReadinessCondition()
+}
+
+func (n *graphNode[NodeType]) SatisfactionTrace() []dgraph.DependencyEvent {
+	return n.current().SatisfactionTrace()
+}
+
+func (n *graphNode[NodeType]) DependencyReport() dgraph.DependencyReport {
+	return n.current().DependencyReport()
+}
+
+// graphEdge is an edge of a Graph. Like graphNode, it shows the connection in the current snapshot.
+type graphEdge[NodeType any] struct {
+	g          *Graph[NodeType]
+	fromNodeID string
+	toNodeID   string
+	last       dgraph.Edge
+}
+
+func (e *graphEdge[NodeType]) current() dgraph.Edge {
+	if current, err := e.g.snapshot().GetEdge(e.fromNodeID, e.toNodeID); err == nil {
+		return current
+	}
+	return e.last
+}
+
+func (e *graphEdge[NodeType]) SourceNodeID() string {
+	return e.fromNodeID
+}
+
+func (e *graphEdge[NodeType]) DestinationNodeID() string {
+	return e.toNodeID
+}
+
+func (e *graphEdge[NodeType]) DependencyType() (dgraph.DependencyType, error) {
+	return e.current().DependencyType()
+}
+
+func (e *graphEdge[NodeType]) ChangeType(dgraph.DependencyType) error {
+	return &ErrReadOnly{"ChangeType"}
+}
+
+func (e *graphEdge[NodeType]) Obviate() error {
+	return &ErrReadOnly{"Obviate"}
+}
+
+func (e *graphEdge[NodeType]) Disconnect() error {
+	return &ErrReadOnly{"Disconnect"}
+}
+
+func (e *graphEdge[NodeType]) Metadata() (map[string]string, error) {
+	return e.current().Metadata()
+}
+
+func (e *graphEdge[NodeType]) SetMetadata(string, string) error {
+	return &ErrReadOnly{"SetMetadata"}
+}
+
+func (e *graphEdge[NodeType]) Weight() (float64, error) {
+	return e.current().Weight()
+}
+
+func (e *graphEdge[NodeType]) SetWeight(float64) error {
+	return &ErrReadOnly{"SetWeight"}
+}
+
+// graphGroup is a group of a Graph. Like graphNode, it shows the group in the current snapshot.
+type graphGroup[NodeType any] struct {
+	g    *Graph[NodeType]
+	name string
+	last dgraph.Group
+}
+
+func (g *graphGroup[NodeType]) current() dgraph.Group {
+	if current, err := g.g.snapshot().GetGroup(g.name); err == nil {
+		return current
+	}
+	return g.last
+}
+
+func (g *graphGroup[NodeType]) Name() string {
+	return g.name
+}
+
+func (g *graphGroup[NodeType]) AddMember(string) error {
+	return &ErrReadOnly{"AddMember"}
+}
+
+func (g *graphGroup[NodeType]) RemoveMember(string) error {
+	return &ErrReadOnly{"RemoveMember"}
+}
+
+func (g *graphGroup[NodeType]) ListMembers() []string {
+	return g.current().ListMembers()
+}
+
+func (g *graphGroup[NodeType]) StatusCounts() dgraph.StatusCounts {
+	return g.current().StatusCounts()
+}
+
+func (g *graphGroup[NodeType]) IsComplete() bool {
+	return g.current().IsComplete()
+}
+
+// graphUndirectedView is the UndirectedView of a Graph, which shows the current snapshot.
+type graphUndirectedView[NodeType any] struct {
+	g *Graph[NodeType]
+}
+
+func (u *graphUndirectedView[NodeType]) Neighbors(nodeID string) (map[string]dgraph.Node[NodeType], error) {
+	nodes, err := u.g.snapshot().UndirectedView().Neighbors(nodeID)
+	return u.g.wrapNodes(nodes), err
+}
+
+func (u *graphUndirectedView[NodeType]) Degree(nodeID string) (int, error) {
+	return u.g.snapshot().UndirectedView().Degree(nodeID)
+}
+
+func (u *graphUndirectedView[NodeType]) Adjacent(nodeID1, nodeID2 string) bool {
+	return u.g.snapshot().UndirectedView().Adjacent(nodeID1, nodeID2)
+}
+
+func (u *graphUndirectedView[NodeType]) ConnectedComponents() [][]string {
+	return u.g.snapshot().UndirectedView().ConnectedComponents()
+}
+
+// newGraph fetches the graph served at the path and returns it as a Graph.
+func newGraph[NodeType any](ctx context.Context, client *Client[NodeType], path string) (*Graph[NodeType], error) {
+	snapshot, etag, err := client.get(ctx, path, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Graph[NodeType]{
+		state: &graphState[NodeType]{
+			client:    client,
+			path:      path,
+			snapshot:  snapshot,
+			etag:      etag,
+			refreshed: make(chan struct{}),
+		},
+	}, nil
+}
@@ -0,0 +1,235 @@
+// Package remote serves the topology and live status of a directed graph as a versioned JSON API, and provides a
+// client that reconstructs read-only snapshots of the served graph. This allows inspecting a graph running inside
+// another process without linking into it. Snapshots are refreshed with conditional requests, so refreshing an
+// unchanged graph is cheap.
+//
+// The API has a single endpoint, GET /v1/graph, which returns the graph in the format of
+// DirectedGraph.ExportJSON, wrapped in an envelope carrying the API version. A dgraph.Registry of many graphs is
+// served with NewRegistryHandler instead.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.arcalot.io/dgraph"
+)
+
+// APIVersion is the version of the API served by NewHandler and understood by Client.
+const APIVersion = 1
+
+// GraphPath is the path of the graph endpoint relative to the base URL.
+const GraphPath = "/v1/graph"
+
+// GraphsPath is the path of the index endpoint of NewRegistryHandler relative to the base URL. The graphs are
+// served below it by name.
+const GraphsPath = "/v1/graphs"
+
+// envelope is the response body of the graph endpoint.
+type envelope struct {
+	APIVersion int             `json:"api_version"`
+	Graph      json.RawMessage `json:"graph"`
+}
+
+// ErrUnsupportedAPIVersion is returned by Client if the server responds with a different API version.
+type ErrUnsupportedAPIVersion struct {
+	APIVersion int
+}
+
+func (e ErrUnsupportedAPIVersion) Error() string {
+	return fmt.Sprintf("unsupported API version %d, expected %d", e.APIVersion, APIVersion)
+}
+
+// ErrUnexpectedStatus is returned by Client if the server responds with a status other than 200 OK, or 304 Not
+// Modified when refreshing a Graph.
+type ErrUnexpectedStatus struct {
+	StatusCode int
+	Body       string
+}
+
+func (e ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d: %s", e.StatusCode, e.Body)
+}
+
+// NewHandler returns an http.Handler serving the graph endpoint for the specified graph. Every request exports
+// the current state of the graph, so the served status is always live. The node items are marshalled with
+// encoding/json. The response carries the generation of the graph as its ETag, and a request with an If-None-Match
+// header holding the current generation is answered with 304 Not Modified without exporting the graph.
+func NewHandler[NodeType any](d dgraph.DirectedGraph[NodeType]) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+GraphPath, func(w http.ResponseWriter, r *http.Request) {
+		serveGraph(w, r, d)
+	})
+	return mux
+}
+
+// GraphSummary is the state of a registered graph listed by the index endpoint of NewRegistryHandler.
+type GraphSummary struct {
+	Name         string    `json:"name"`
+	Generation   uint64    `json:"generation"`
+	InFlight     int       `json:"in_flight"`
+	QueueDepth   int       `json:"queue_depth"`
+	HeldBack     int       `json:"held_back"`
+	Waiting      int       `json:"waiting"`
+	Done         bool      `json:"done"`
+	LastProgress time.Time `json:"last_progress"`
+}
+
+// index is the response body of the index endpoint.
+type index struct {
+	APIVersion int            `json:"api_version"`
+	Graphs     []GraphSummary `json:"graphs"`
+}
+
+// NewRegistryHandler returns an http.Handler serving all graphs of the registry. GET /v1/graphs returns an index
+// listing a GraphSummary of each graph sorted by name, and GET /v1/graphs/{name} serves the graph registered under
+// the name like the graph endpoint of NewHandler. Graphs registered or unregistered later are picked up by the
+// next request.
+func NewRegistryHandler[NodeType any](registry *dgraph.Registry[NodeType]) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+GraphsPath, func(w http.ResponseWriter, r *http.Request) {
+		result := index{APIVersion, []GraphSummary{}}
+		health := registry.Health()
+		for _, name := range registry.Names() {
+			h, ok := health[name]
+			if !ok {
+				// Registered after the health was collected.
+				continue
+			}
+			result.Graphs = append(result.Graphs, GraphSummary{
+				Name:         name,
+				Generation:   h.Generation,
+				InFlight:     len(h.InFlight),
+				QueueDepth:   h.QueueDepth,
+				HeldBack:     h.HeldBack,
+				Waiting:      h.Waiting,
+				Done:         h.Done(),
+				LastProgress: h.LastProgress,
+			})
+		}
+		body, err := json.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("GET "+GraphsPath+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		d, err := registry.Get(r.PathValue("name"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		serveGraph(w, r, d)
+	})
+	return mux
+}
+
+// serveGraph writes the envelope of the graph, or 304 Not Modified if the client already has its current
+// generation.
+func serveGraph[NodeType any](w http.ResponseWriter, r *http.Request, d dgraph.DirectedGraph[NodeType]) {
+	// The generation is read before the export, so the ETag is at worst older than the body, which only causes an
+	// unnecessary refetch.
+	etag := `"` + strconv.FormatUint(d.Generation(), 10) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	graph, err := d.ExportJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body, err := json.Marshal(envelope{APIVersion, graph})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// Client fetches graphs from a server created with NewHandler or NewRegistryHandler.
+type Client[NodeType any] struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the server at the specified base URL. If httpClient is nil,
+// http.DefaultClient is used.
+func NewClient[NodeType any](baseURL string, httpClient *http.Client) *Client[NodeType] {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client[NodeType]{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// Fetch retrieves the current state of the graph served by NewHandler and returns it as a read-only Graph. The
+// Graph has the same nodes, connections, resolution statuses, and unpopped ready nodes as the served graph, but
+// changes are rejected with an ErrReadOnly. Call Refresh on the Graph to observe later changes.
+func (c *Client[NodeType]) Fetch(ctx context.Context) (*Graph[NodeType], error) {
+	return newGraph(ctx, c, GraphPath)
+}
+
+// FetchGraph works like Fetch for the graph registered under the name on a server created with
+// NewRegistryHandler.
+func (c *Client[NodeType]) FetchGraph(ctx context.Context, name string) (*Graph[NodeType], error) {
+	return newGraph(ctx, c, GraphsPath+"/"+url.PathEscape(name))
+}
+
+// get fetches the graph served at the path. If etag is not empty and the graph has not changed since, nil is
+// returned without an error. Otherwise, the graph is returned with its ETag.
+func (c *Client[NodeType]) get(
+	ctx context.Context,
+	path string,
+	etag string,
+) (dgraph.DirectedGraph[NodeType], string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch graph (%w)", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if etag != "" && response.StatusCode == http.StatusNotModified {
+		return nil, "", nil
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read graph response (%w)", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, "", &ErrUnexpectedStatus{response.StatusCode, strings.TrimSpace(string(body))}
+	}
+	var e envelope
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, "", fmt.Errorf("failed to decode graph response (%w)", err)
+	}
+	if e.APIVersion != APIVersion {
+		return nil, "", &ErrUnsupportedAPIVersion{e.APIVersion}
+	}
+	snapshot, err := dgraph.ImportJSON[NodeType](e.Graph)
+	if err != nil {
+		return nil, "", err
+	}
+	return snapshot, response.Header.Get("ETag"), nil
+}
@@ -0,0 +1,228 @@
+package remote_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+	"go.arcalot.io/dgraph/remote"
+)
+
+func TestRemote(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "item a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "item b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+
+	server := httptest.NewServer(remote.NewHandler(d))
+	defer server.Close()
+	client := remote.NewClient[string](server.URL+"/", server.Client())
+
+	g := assert.NoErrorR[*remote.Graph[string]](t)(client.Fetch(context.Background()))
+	var snapshot dgraph.DirectedGraph[string] = g
+	remoteA := assert.NoErrorR[dgraph.Node[string]](t)(snapshot.GetNodeByID("a"))
+	remoteB := assert.NoErrorR[dgraph.Node[string]](t)(snapshot.GetNodeByID("b"))
+	assert.Equals(t, remoteB.Item(), "item b")
+	assert.Equals(t, remoteB.OutstandingDependencies(), map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+	assert.Equals(t, snapshot.HasReadyNodes(), true)
+
+	// Changes are rejected, and ready nodes are never popped.
+	var readOnly *remote.ErrReadOnly
+	assert.Equals(t, errors.As(remoteA.ResolveNode(dgraph.Unresolvable), &readOnly), true)
+	assert.Equals(t, readOnly.Operation, "ResolveNode")
+	_, err := snapshot.AddNode("c", "item c")
+	assert.InstanceOf[*remote.ErrReadOnly](t, err)
+	assert.InstanceOf[*remote.ErrReadOnly](t, snapshot.PushStartingNodes())
+	assertReadOnlyPanic(t, "PopReadyNodes", func() { snapshot.PopReadyNodes() })
+	assert.Equals(t, snapshot.HasReadyNodes(), true)
+
+	// Changes to the served graph are visible after a refresh, including through the nodes fetched before.
+	d.PopReadyNodes()
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, remoteA.ResolutionStatus(), dgraph.Waiting)
+	assert.NoError(t, g.Refresh(context.Background()))
+	assert.Equals(t, remoteA.ResolutionStatus(), dgraph.Resolved)
+	assert.Equals(t, remoteB.IsReady(), true)
+	assert.Equals(t, snapshot.Namespace("b").HasReadyNodes(), true)
+}
+
+// assertReadOnlyPanic asserts that the function panics with an ErrReadOnly for the operation.
+func assertReadOnlyPanic(t *testing.T, operation string, f func()) {
+	t.Helper()
+	assert.PanicsWithValidation(t, f, func(t *testing.T, value any) {
+		readOnly, ok := value.(*remote.ErrReadOnly)
+		assert.Equals(t, ok, true)
+		assert.Equals(t, readOnly.Operation, operation)
+	})
+}
+
+func TestRemote_ReadOnlyPanics(t *testing.T) {
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "item a"))
+	server := httptest.NewServer(remote.NewHandler(d))
+	defer server.Close()
+	client := remote.NewClient[string](server.URL+"/", server.Client())
+	g := assert.NoErrorR[*remote.Graph[string]](t)(client.Fetch(context.Background()))
+
+	// Changes that can't return an error are rejected loudly instead of being ignored.
+	for operation, f := range map[string]func(){
+		"Reset":                    g.Reset,
+		"ResetExecution":           g.ResetExecution,
+		"SetGate":                  func() { g.SetGate(false) },
+		"CancelAll":                g.CancelAll,
+		"SetDeadline":              func() { g.SetDeadline(time.Now()) },
+		"PopReadyNodesWithReasons": func() { g.PopReadyNodesWithReasons() },
+		"PopReadyNodesOrdered":     func() { g.PopReadyNodesOrdered() },
+		"PopReadyNodeObjects":      func() { g.PopReadyNodeObjects() },
+		"PopNReadyNodes":           func() { g.PopNReadyNodes(1) },
+		"PopReadyNodesWhere": func() {
+			g.PopReadyNodesWhere(func(dgraph.Node[string]) bool { return true })
+		},
+		"Subscribe":      func() { g.Subscribe(context.Background()) },
+		"OnNodeAdded":    func() { g.OnNodeAdded(func(dgraph.Node[string]) {}) },
+		"OnNodeResolved": func() { g.OnNodeResolved(func(dgraph.Node[string], dgraph.ResolutionStatus) {}) },
+		"OnUnresolvable": func() { g.OnUnresolvable(func(dgraph.Node[string], error) {}) },
+	} {
+		t.Run(operation, func(t *testing.T) {
+			assertReadOnlyPanic(t, operation, f)
+		})
+	}
+	_, err := g.PopReadyNodesInGroup("group")
+	assert.InstanceOf[*remote.ErrReadOnly](t, err)
+	assert.Equals(t, len(g.ListNodes()), 1)
+}
+
+func TestRemote_Refresh(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "item a"))
+	assert.NoError(t, d.MarkOutput("a"))
+	var statusCodes []int
+	handler := remote.NewHandler(d)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		statusCodes = append(statusCodes, recorder.Code)
+		for key, values := range recorder.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.Code)
+		_, _ = w.Write(recorder.Body.Bytes())
+	}))
+	defer server.Close()
+
+	g := assert.NoErrorR[*remote.Graph[string]](t)(
+		remote.NewClient[string](server.URL, server.Client()).Fetch(context.Background()),
+	)
+	// An unchanged graph is not sent again.
+	assert.NoError(t, g.Refresh(context.Background()))
+	assert.Equals(t, statusCodes, []int{http.StatusOK, http.StatusNotModified})
+
+	// WaitOutputs returns once a refresh shows the outputs resolved.
+	outputs := make(chan map[string]dgraph.OutputResult)
+	go func() {
+		result, err := g.WaitOutputs(context.Background())
+		assert.NoError(t, err)
+		outputs <- result
+	}()
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, g.Refresh(context.Background()))
+	assert.Equals(t, (<-outputs)["a"].Status, dgraph.Resolved)
+	assert.Equals(t, statusCodes, []int{http.StatusOK, http.StatusNotModified, http.StatusOK})
+}
+
+func TestRemote_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == remote.GraphPath {
+			_, _ = w.Write([]byte(`{"api_version":2,"graph":{}}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, err := remote.NewClient[string](server.URL, server.Client()).Fetch(context.Background())
+	var unsupported *remote.ErrUnsupportedAPIVersion
+	assert.Equals(t, errors.As(err, &unsupported), true)
+	assert.Equals(t, unsupported.APIVersion, 2)
+
+	_, err = remote.NewClient[string](server.URL+"/prefix", server.Client()).Fetch(context.Background())
+	var unexpectedStatus *remote.ErrUnexpectedStatus
+	assert.Equals(t, errors.As(err, &unexpectedStatus), true)
+	assert.Equals(t, unexpectedStatus.StatusCode, http.StatusNotFound)
+}
+
+func TestRemote_NotModified(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "item a"))
+	server := httptest.NewServer(remote.NewHandler(d))
+	defer server.Close()
+
+	get := func(etag string) *http.Response {
+		request := assert.NoErrorR[*http.Request](t)(http.NewRequest(http.MethodGet, server.URL+remote.GraphPath, nil))
+		if etag != "" {
+			request.Header.Set("If-None-Match", etag)
+		}
+		response := assert.NoErrorR[*http.Response](t)(server.Client().Do(request))
+		assert.NoError(t, response.Body.Close())
+		return response
+	}
+	response := get("")
+	assert.Equals(t, response.StatusCode, http.StatusOK)
+	etag := response.Header.Get("ETag")
+	assert.Equals(t, get(etag).StatusCode, http.StatusNotModified)
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	response = get(etag)
+	assert.Equals(t, response.StatusCode, http.StatusOK)
+	assert.Equals(t, response.Header.Get("ETag") != etag, true)
+}
+
+func TestRemote_Registry(t *testing.T) {
+	registry := dgraph.NewRegistry[string]()
+	d := dgraph.New[string]()
+	assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "item a"))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, registry.Register("workflow", d))
+	server := httptest.NewServer(remote.NewRegistryHandler(registry))
+	defer server.Close()
+
+	get := func(path string) (*http.Response, []byte) {
+		response := assert.NoErrorR[*http.Response](t)(server.Client().Get(server.URL + path))
+		body := assert.NoErrorR[[]byte](t)(io.ReadAll(response.Body))
+		assert.NoError(t, response.Body.Close())
+		return response, body
+	}
+	response, body := get(remote.GraphsPath)
+	assert.Equals(t, response.StatusCode, http.StatusOK)
+	var index struct {
+		Graphs []remote.GraphSummary `json:"graphs"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &index))
+	assert.Equals(t, len(index.Graphs), 1)
+	assert.Equals(t, index.Graphs[0].Name, "workflow")
+	assert.Equals(t, index.Graphs[0].QueueDepth, 1)
+	assert.Equals(t, index.Graphs[0].Done, false)
+
+	response, body = get(remote.GraphsPath + "/workflow")
+	assert.Equals(t, response.StatusCode, http.StatusOK)
+	assert.Contains(t, string(body), `"item a"`)
+	response, _ = get(remote.GraphsPath + "/missing")
+	assert.Equals(t, response.StatusCode, http.StatusNotFound)
+
+	client := remote.NewClient[string](server.URL, server.Client())
+	g := assert.NoErrorR[*remote.Graph[string]](t)(client.FetchGraph(context.Background(), "workflow"))
+	remoteA := assert.NoErrorR[dgraph.Node[string]](t)(g.GetNodeByID("a"))
+	assert.Equals(t, remoteA.Item(), "item a")
+	_, err := client.FetchGraph(context.Background(), "missing")
+	var unexpectedStatus *remote.ErrUnexpectedStatus
+	assert.Equals(t, errors.As(err, &unexpectedStatus), true)
+	assert.Equals(t, unexpectedStatus.StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,102 @@
+package dgraph
+
+func (n *node[NodeType]) Rename(newID string) error {
+	newID = n.dg.config.normalizeID(newID)
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	if newID == n.id {
+		return nil
+	}
+	if n.dg.config.idPattern != nil && !n.dg.config.idPattern.MatchString(newID) {
+		return &ErrInvalidNodeID{newID, n.dg.config.idPattern.String()}
+	}
+	if _, ok := n.dg.nodes[newID]; ok {
+		return ErrNodeAlreadyExists{newID}
+	}
+	n.dg.renameNode(n, newID)
+	return nil
+}
+
+// renameNode moves all state of the graph that refers to the node to the new ID.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) renameNode(n *node[NodeType], newID string) {
+	oldID := n.id
+	renameKey(d.nodes, oldID, newID)
+	renameKey(d.connectionsFromNode, oldID, newID)
+	renameKey(d.connectionsToNode, oldID, newID)
+	renameKey(d.changedNodes, oldID, newID)
+	for toNodeID := range d.connectionsFromNode[newID] {
+		renameKey(d.connectionsToNode[toNodeID], oldID, newID)
+		d.renameConnection([2]string{oldID, toNodeID}, [2]string{newID, toNodeID})
+		d.nodes[toNodeID].renameDependency(oldID, newID)
+	}
+	for fromNodeID := range d.connectionsToNode[newID] {
+		renameKey(d.connectionsFromNode[fromNodeID], oldID, newID)
+		d.renameConnection([2]string{fromNodeID, oldID}, [2]string{fromNodeID, newID})
+	}
+	for _, g := range d.groups {
+		renameKey(g.members, oldID, newID)
+		renameKey(g.dependents, oldID, newID)
+	}
+	renameKey(d.heldReadyIDs, oldID, newID)
+	if d.readyForProcessing.Contains(oldID) {
+		d.readyForProcessing.Remove(oldID)
+		d.readyForProcessing.Add(newID)
+	}
+	for i, nodeID := range d.resolutionOrder {
+		if nodeID == oldID {
+			d.resolutionOrder[i] = newID
+		}
+	}
+	n.id = newID
+	d.downstreamCosts = nil
+	d.advanceGeneration()
+}
+
+// renameConnection moves the position, metadata, weight, condition, and pending data of a connection to its new
+// key.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) renameConnection(oldKey, newKey [2]string) {
+	renameKey(d.connectionSequence, oldKey, newKey)
+	renameKey(d.connectionMetadata, oldKey, newKey)
+	renameKey(d.connectionWeights, oldKey, newKey)
+	renameKey(d.connectionConditions, oldKey, newKey)
+	renameKey(d.pendingData, oldKey, newKey)
+}
+
+// renameDependency updates the dependency records of the node after the dependency was renamed.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) renameDependency(oldID, newID string) {
+	renameKey(n.outstandingDependencies, oldID, newID)
+	renameKey(n.resolvedDependencies, oldID, newID)
+	renameKey(n.failedDependencies, oldID, newID)
+	renameKey(n.obviatedTypes, oldID, newID)
+	if n.satisfyingOrDependency == oldID {
+		n.satisfyingOrDependency = newID
+	}
+	if n.expression != nil {
+		expression := n.expression.renamed(oldID, newID)
+		n.expression = &expression
+	}
+	for i := range n.resolutionHistory {
+		if n.resolutionHistory[i].NodeID == oldID {
+			n.resolutionHistory[i].NodeID = newID
+		}
+	}
+	for i := range n.satisfactionTrace {
+		if n.satisfactionTrace[i].DependencyID == oldID {
+			n.satisfactionTrace[i].DependencyID = newID
+		}
+	}
+}
+
+// renameKey moves the value of the old key to the new key, if the map has one.
+func renameKey[K comparable, V any](m map[K]V, oldKey, newKey K) {
+	if value, ok := m[oldKey]; ok {
+		delete(m, oldKey)
+		m[newKey] = value
+	}
+}
@@ -0,0 +1,37 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestNode_Rename(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("b", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+
+	assert.NoError(t, a.Rename("x"))
+	assert.NoError(t, b.Rename("y"))
+	assert.Equals(t, a.ID(), "x")
+	assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{"x": dgraph.AndDependency})
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{"y": dgraph.AndDependency})
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"x"})
+	_, err := d.GetNodeByID("a")
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, b.Rename("z"))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"z"})
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, c.ResolvedDependencies(), map[string]dgraph.DependencyType{"z": dgraph.AndDependency})
+	assert.Equals(t, d.ResolutionOrder(), []string{"x", "z"})
+	assert.Equals(t, d.HasCycles(), false)
+
+	assert.InstanceOf[dgraph.ErrNodeAlreadyExists](t, c.Rename("x"))
+}
@@ -0,0 +1,69 @@
+package dgraph
+
+import "time"
+
+func (d *directedGraph[NodeType]) ResetExecution() {
+	d.lock.Lock()
+	defer d.unlock()
+	for _, n := range d.nodes {
+		n.resetExecution()
+	}
+	d.readyForProcessing.Clear()
+	d.clearHeldReady()
+	clear(d.changedNodes)
+	d.started = false
+	d.startedAt = time.Time{}
+	d.resolutionOrder = d.resolutionOrder[:0]
+	clear(d.resourcesInUse)
+	clear(d.pendingData)
+	clear(d.dependencyStats)
+	if d.deadlineTimer != nil {
+		d.deadlineTimer.Stop()
+		d.deadlineTimer = nil
+	}
+	d.deadline = time.Time{}
+	if d.deadlineExceeded {
+		d.deadlineExceeded = false
+		d.done = make(chan struct{})
+	}
+	d.randomSource = d.config.newRandomSource()
+	d.advanceGeneration()
+	d.notifyInFlightChanged()
+}
+
+// resetExecution resets the node to Waiting, with all of its inbound connections as outstanding dependencies.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) resetExecution() {
+	outstandingDependencies := make(map[string]DependencyType, len(n.dg.connectionsToNode[n.id]))
+	for fromNodeID := range n.dg.connectionsToNode[n.id] {
+		outstandingDependencies[fromNodeID] = n.declaredDependencyType(fromNodeID)
+	}
+	n.outstandingDependencies = outstandingDependencies
+	n.resolvedDependencies = map[string]DependencyType{}
+	n.failedDependencies = map[string]DependencyType{}
+	n.obviatedTypes = nil
+	n.resolutionHistory = nil
+	n.status = Waiting
+	n.ready = false
+	n.readyReason = ""
+	n.readyAt = time.Time{}
+	n.poppedAt = time.Time{}
+	n.resolvedAt = time.Time{}
+	n.satisfyingOrDependency = ""
+	n.satisfactionTrace = nil
+	n.unresolvableCause = nil
+	n.reason = nil
+	n.result = nil
+	n.holdsResources = false
+	n.iterations = 0
+}
+
+// declaredDependencyType returns the type the dependency had before it was obviated, or its current type if it was
+// not obviated.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) declaredDependencyType(dependencyNodeID string) DependencyType {
+	if dependencyType, ok := n.obviatedTypes[dependencyNodeID]; ok {
+		return dependencyType
+	}
+	return n.dependencyType(dependencyNodeID)
+}
@@ -0,0 +1,41 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_ResetExecution(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.OrDependency))
+
+	run := func() {
+		assert.NoError(t, d.PushStartingNodes())
+		assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+		assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+		// Resolving a obviates the OR dependency on b.
+		assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{
+			"b": dgraph.ObviatedDependency,
+		})
+		assert.Equals(t, d.PopReadyNodesOrdered(), []string{"c"})
+		assert.NoError(t, b.ResolveNode(dgraph.Unresolvable))
+		assert.NoError(t, c.ResolveNode(dgraph.Resolved))
+	}
+	run()
+	d.ResetExecution()
+	assert.Equals(t, c.ResolutionStatus(), dgraph.Waiting)
+	assert.Equals(t, c.IsReady(), false)
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.OrDependency,
+		"b": dgraph.OrDependency,
+	})
+	assert.Equals(t, d.HasReadyNodes(), false)
+	assert.Equals(t, d.ResolutionOrder(), []string{})
+	run()
+}
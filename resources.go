@@ -0,0 +1,111 @@
+package dgraph
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// WithResources enables resource-aware readiness. The capacities map each resource name, for example "gpu" or
+// "lock:db", to the number of tokens available. Nodes declare the tokens they need with Node.RequireResources.
+// A ready node is only exposed in the ready set once all of its tokens are available; the tokens are then held
+// until the node is resolved or removed from the ready set, and other ready nodes are held back meanwhile.
+// Held back nodes whose tokens become available are released in the order in which they became ready, but a
+// node that needs more tokens doesn't block nodes behind it that need fewer.
+func WithResources(capacities map[string]int) Option {
+	return func(c *config) {
+		c.resourceCapacities = maps.Clone(capacities)
+	}
+}
+
+func (n *node[NodeType]) RequireResources(tokens ...string) error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	if n.holdsResources {
+		// Replacing the requirements now would release a different number of tokens than were acquired.
+		return &ErrResourcesHeld{n.id}
+	}
+	resources := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		resource, count, err := parseResourceToken(token)
+		if err != nil {
+			return err
+		}
+		resources[resource] += count
+	}
+	for resource, count := range resources {
+		if capacity := n.dg.config.resourceCapacities[resource]; count > capacity {
+			return &ErrResourceUnavailable{n.id, resource, count, capacity}
+		}
+	}
+	n.resources = resources
+	n.dg.advanceGeneration()
+	return nil
+}
+
+func (n *node[NodeType]) RequiredResources() map[string]int {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return maps.Clone(n.resources)
+}
+
+func (d *directedGraph[NodeType]) ResourcesInUse() map[string]int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return maps.Clone(d.resourcesInUse)
+}
+
+// parseResourceToken parses a token in the "name" or "name:count" format. Since resource names may contain colons
+// themselves, only a numeric suffix is treated as the count.
+func parseResourceToken(token string) (string, int, error) {
+	if i := strings.LastIndex(token, ":"); i >= 0 {
+		if count, err := strconv.Atoi(token[i+1:]); err == nil {
+			if count <= 0 || i == 0 {
+				return "", 0, &ErrInvalidResourceToken{token}
+			}
+			return token[:i], count, nil
+		}
+	}
+	if token == "" {
+		return "", 0, &ErrInvalidResourceToken{token}
+	}
+	return token, 1, nil
+}
+
+// acquireResources acquires the tokens required by the node and returns true, or returns false if any of them is
+// not available. Nodes that have already been resolved don't need any tokens.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) acquireResources(n *node[NodeType]) bool {
+	if n.holdsResources || len(n.resources) == 0 || n.status != Waiting {
+		return true
+	}
+	for resource, count := range n.resources {
+		if d.resourcesInUse[resource]+count > d.config.resourceCapacities[resource] {
+			return false
+		}
+	}
+	for resource, count := range n.resources {
+		d.resourcesInUse[resource] += count
+	}
+	n.holdsResources = true
+	return true
+}
+
+// releaseResources releases the tokens held by the node and returns true if it held any.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) releaseResources(n *node[NodeType]) bool {
+	if !n.holdsResources {
+		return false
+	}
+	for resource, count := range n.resources {
+		d.resourcesInUse[resource] -= count
+		if d.resourcesInUse[resource] == 0 {
+			delete(d.resourcesInUse, resource)
+		}
+	}
+	n.holdsResources = false
+	return true
+}
@@ -0,0 +1,162 @@
+package dgraph
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+func (n *node[NodeType]) ResetResolution() error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	switch {
+	case n.status != Unresolvable:
+		return &ErrNodeNotRetryable{n.id, fmt.Sprintf("its status is %s", n.status)}
+	case n.unresolvableCause != nil:
+		return &ErrNodeNotRetryable{n.id, n.unresolvableCause.Error()}
+	}
+	// Validate all dependents first, so that the graph is left unchanged if any of them has already run.
+	if err := n.checkDependentsRetryable(); err != nil {
+		return err
+	}
+	n.resetDependents()
+	n.resetStatus()
+	if n.ready {
+		n.dg.pushReady(n)
+	}
+	return nil
+}
+
+// checkDependentsRetryable returns an ErrNodeNotRetryable if a dependent has already run because the node is
+// unresolvable.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) checkDependentsRetryable() error {
+	for _, toNodeID := range sortedKeys(n.dg.connectionsFromNode[n.id]) {
+		toNode := n.dg.nodes[toNodeID]
+		dependencyType, failed := toNode.failedDependencies[n.id]
+		switch {
+		case !failed:
+			continue
+		case toNode.isSkippedBecauseOf(n.id):
+			if err := toNode.checkDependentsRetryable(); err != nil {
+				return err
+			}
+		case isSatisfiedByFailure(dependencyType) && (toNode.status != Waiting ||
+			toNode.ready && !n.dg.isPendingReady(toNodeID)):
+			return &ErrNodeNotRetryable{
+				n.id,
+				fmt.Sprintf("its dependent %q has already run because of its failure", toNodeID),
+			}
+		}
+	}
+	return nil
+}
+
+// resetDependents makes the node an outstanding dependency of its dependents again. Dependents that were made
+// unresolvable by the node are reset to Waiting, and dependents that became ready because of its failure are
+// removed from the ready set.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) resetDependents() {
+	for _, toNodeID := range sortedKeys(n.dg.connectionsFromNode[n.id]) {
+		toNode := n.dg.nodes[toNodeID]
+		dependencyType, failed := toNode.failedDependencies[n.id]
+		if !failed {
+			continue
+		}
+		switch {
+		case toNode.isSkippedBecauseOf(n.id):
+			_, obviated := toNode.unresolvableCause.(*ErrNodeObviated)
+			toNode.resetStatus()
+			if !obviated {
+				toNode.resetReadiness()
+			} else if toNode.ready {
+				// Obviated nodes were taken out of the ready set without running.
+				n.dg.pushReady(toNode)
+			}
+			toNode.resetDependents()
+		case isSatisfiedByFailure(dependencyType) && toNode.ready:
+			toNode.resetReadiness()
+		}
+		toNode.restoreDependency(n.id, dependencyType)
+	}
+}
+
+// resetStatus returns an unresolvable node to Waiting as if it had not been popped, and removes it from the
+// resolution order.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) resetStatus() {
+	n.status = Waiting
+	n.unresolvableCause = nil
+	n.reason = nil
+	n.result = nil
+	n.poppedAt = time.Time{}
+	n.resolvedAt = time.Time{}
+	if i := slices.Index(n.dg.resolutionOrder, n.id); i >= 0 {
+		n.dg.resolutionOrder = slices.Delete(n.dg.resolutionOrder, i, i+1)
+	}
+	n.dg.advanceGeneration()
+}
+
+// resetReadiness removes a node that has not been popped from the ready set, and restores the optional and
+// expression dependencies that were obviated when it became ready.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) resetReadiness() {
+	n.dg.removeReady(n.id)
+	n.ready = false
+	n.readyReason = ""
+	n.readyAt = time.Time{}
+	for dependencyNodeID, dependencyType := range n.outstandingDependencies {
+		obviatedType := n.obviatedTypes[dependencyNodeID]
+		if dependencyType == ObviatedDependency &&
+			(obviatedType == OptionalDependency || obviatedType == ExpressionDependency) {
+			n.outstandingDependencies[dependencyNodeID] = obviatedType
+			delete(n.obviatedTypes, dependencyNodeID)
+		}
+	}
+}
+
+// restoreDependency moves a failed dependency back to the outstanding dependencies. Dependencies that can no longer
+// affect the node are restored as obviated.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) restoreDependency(dependencyNodeID string, dependencyType DependencyType) {
+	delete(n.failedDependencies, dependencyNodeID)
+	if dependencyType != ObviatedDependency &&
+		(n.status != Waiting || dependencyType == OrDependency && n.satisfyingOrDependency != "") {
+		if n.obviatedTypes == nil {
+			n.obviatedTypes = map[string]DependencyType{}
+		}
+		n.obviatedTypes[dependencyNodeID] = dependencyType
+		dependencyType = ObviatedDependency
+	}
+	n.outstandingDependencies[dependencyNodeID] = dependencyType
+}
+
+// isSkippedBecauseOf returns true if the node was made unresolvable by the failure of the specified dependency.
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) isSkippedBecauseOf(dependencyNodeID string) bool {
+	if n.status != Unresolvable {
+		return false
+	}
+	switch cause := n.unresolvableCause.(type) {
+	case *ErrDependencyUnresolvable:
+		return cause.DependencyID == dependencyNodeID
+	case *ErrDependencyConditionNotMet:
+		return cause.DependencyID == dependencyNodeID
+	case *ErrExpressionNotSatisfied:
+		return n.failedDependencies[dependencyNodeID] == ExpressionDependency
+	case *ErrNodeObviated:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSatisfiedByFailure returns true if an unresolvable dependency of the specified type satisfies the dependency.
+// Dependencies of an expression may be, depending on the expression.
+func isSatisfiedByFailure(dependencyType DependencyType) bool {
+	return dependencyType == CompletionAndDependency || dependencyType == OnUnresolvableDependency ||
+		dependencyType == ExpressionDependency
+}
@@ -0,0 +1,63 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestNode_ResetResolution(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, nodeID := range []string{"a", "b", "c", "d", "h"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(nodeID, nodeID))
+	}
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	h := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("h"))
+	assert.NoError(t, b.ConnectDependency("a", dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency("a", dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency("d", dgraph.OrDependency))
+	assert.NoError(t, h.ConnectDependency("a", dgraph.OnUnresolvableDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "d"})
+
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, b.ResolutionStatus(), dgraph.Unresolvable)
+	assert.Equals(t, h.IsReady(), true)
+
+	assert.NoError(t, a.ResetResolution())
+	assert.Equals(t, a.ResolutionStatus(), dgraph.Waiting)
+	assert.Equals(t, b.ResolutionStatus(), dgraph.Waiting)
+	assert.Equals(t, b.IsReady(), false)
+	assert.Nil(t, b.UnresolvableCause())
+	assert.Equals(t, b.OutstandingDependencies(), map[string]dgraph.DependencyType{"a": dgraph.AndDependency})
+	assert.Equals(t, c.OutstandingDependencies(), map[string]dgraph.DependencyType{
+		"a": dgraph.OrDependency,
+		"d": dgraph.OrDependency,
+	})
+	assert.Equals(t, h.IsReady(), false)
+	assert.Equals(t, d.ResolutionOrder(), []string{})
+	// The retried node is ready again, while its skipped dependent is not.
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"b", "c", "h"})
+	assert.Equals(t, h.ResolutionStatus(), dgraph.Unresolvable)
+	assert.InstanceOf[*dgraph.ErrNodeNotRetryable](t, a.ResetResolution())
+}
+
+func TestNode_ResetResolution_DependentAlreadyRan(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	h := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("h", "h"))
+	assert.NoError(t, h.ConnectDependency("a", dgraph.OnUnresolvableDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a"})
+	assert.NoError(t, a.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"h"})
+
+	assert.InstanceOf[*dgraph.ErrNodeNotRetryable](t, a.ResetResolution())
+	assert.Equals(t, a.ResolutionStatus(), dgraph.Unresolvable)
+}
@@ -0,0 +1,53 @@
+package dgraph
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// WithSeed makes the graph process nodes in a reproducible order wherever the order is not otherwise specified,
+// such as the order in which the dependents of a resolved node are notified, and the order in which Reconcile
+// visits nodes and dependencies. These orders determine, among others, the order of the held back ready nodes and
+// of the listener calls, and which OR dependency satisfies a node during reconciliation. Instead of the random
+// order of Go maps, the IDs are sorted and then shuffled with a random source seeded with the seed, so that
+// two graphs built and resolved the same way with the same seed behave exactly the same. Clones continue with a
+// copy of the random state of the original.
+func WithSeed(seed uint64) Option {
+	return func(c *config) {
+		c.seeded = true
+		c.seed = seed
+	}
+}
+
+// newRandomSource returns the random source for a new graph, or nil if WithSeed is not used.
+func (c config) newRandomSource() *rand.PCG {
+	if !c.seeded {
+		return nil
+	}
+	return rand.NewPCG(c.seed, c.seed)
+}
+
+// seededKeys returns the keys of the map in map order, or in a shuffled order derived from the random source if it
+// is not nil.
+func seededKeys[V any](source *rand.PCG, m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	if source != nil {
+		slices.Sort(keys)
+		rand.New(source).Shuffle(len(keys), func(i, j int) {
+			keys[i], keys[j] = keys[j], keys[i]
+		})
+	}
+	return keys
+}
+
+// cloneRandomSource returns an independent copy of the random source with the same state.
+func cloneRandomSource(source *rand.PCG) *rand.PCG {
+	if source == nil {
+		return nil
+	}
+	clone := *source
+	return &clone
+}
@@ -0,0 +1,35 @@
+package dgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// seededRun resolves a root node with many dependents and returns the order in which the dependents became ready.
+func seededRun(t *testing.T, seed uint64) []string {
+	d := dgraph.New[string](dgraph.WithSeed(seed))
+	root := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("root", "root"))
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("dependent-%02d", i)
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+		assert.NoError(t, n.ConnectDependency(root.ID(), dgraph.AndDependency))
+	}
+	var readyOrder []string
+	d.OnNodeReady(func(node dgraph.Node[string]) {
+		readyOrder = append(readyOrder, node.ID())
+	})
+	assert.NoError(t, d.PushStartingNodes())
+	assert.NoError(t, root.ResolveNode(dgraph.Resolved))
+	return readyOrder
+}
+
+func TestWithSeed(t *testing.T) {
+	first := seededRun(t, 42)
+	assert.Equals(t, len(first), 21)
+	for i := 0; i < 5; i++ {
+		assert.Equals(t, seededRun(t, 42), first)
+	}
+}
@@ -0,0 +1,49 @@
+package dgraph
+
+import "time"
+
+// WithSelfLoops permits connecting a node to itself with a CompletionAndDependency, which marks the node as
+// iterative: the node must complete an iteration before it is ready to run again. Self-loops are not part of the
+// topology of the graph, so they don't count as cycles and are not listed as connections. Without this option, and
+// for other dependency types, connecting a node to itself returns an ErrCannotConnectToSelf.
+func WithSelfLoops() Option {
+	return func(c *config) {
+		c.selfLoops = true
+	}
+}
+
+func (n *node[NodeType]) HasSelfLoop() bool {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.selfLoop
+}
+
+func (n *node[NodeType]) CompleteIteration() error {
+	n.dg.lock.Lock()
+	defer n.dg.unlock()
+	if n.deleted {
+		return &ErrNodeDeleted{n.id}
+	}
+	if !n.selfLoop {
+		return &ErrNoSelfLoop{n.id}
+	}
+	if n.status != Waiting {
+		return &ErrNodeResolutionAlreadySet{n.id, n.status, Waiting}
+	}
+	if !n.ready || n.poppedAt.IsZero() || n.dg.isPendingReady(n.id) {
+		return &ErrIterationNotRunning{n.id}
+	}
+	n.iterations++
+	n.dg.advanceGeneration()
+	n.poppedAt = time.Time{}
+	n.readyReason = ReadyIterationCompleted
+	n.dg.pushReady(n)
+	n.dg.notifyInFlightChanged()
+	return nil
+}
+
+func (n *node[NodeType]) Iterations() int {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.iterations
+}
@@ -0,0 +1,42 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_SelfLoops(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	assert.InstanceOf[*dgraph.ErrCannotConnectToSelf](t, a.ConnectDependency("a", dgraph.CompletionAndDependency))
+
+	d = dgraph.New[string](dgraph.WithSelfLoops())
+	a = assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.InstanceOf[*dgraph.ErrCannotConnectToSelf](t, a.ConnectDependency("a", dgraph.AndDependency))
+	assert.InstanceOf[*dgraph.ErrNoSelfLoop](t, b.CompleteIteration())
+	assert.NoError(t, a.ConnectDependency("a", dgraph.CompletionAndDependency))
+	assert.Equals(t, a.HasSelfLoop(), true)
+	assert.Equals(t, len(d.ListConnections()), 1)
+	assert.Nil(t, d.FindCycles())
+
+	assert.NoError(t, d.PushStartingNodes())
+	assert.InstanceOf[*dgraph.ErrIterationNotRunning](t, a.CompleteIteration())
+	for i := 1; i <= 2; i++ {
+		assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting})
+		assert.NoError(t, a.CompleteIteration())
+		assert.Equals(t, a.Iterations(), i)
+		assert.Equals(t, a.ReadyReason(), dgraph.ReadyIterationCompleted)
+	}
+	// The dependents only become ready once the node is resolved after the last iteration.
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"a": dgraph.Waiting})
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, d.PopReadyNodes(), map[string]dgraph.ResolutionStatus{"b": dgraph.Waiting})
+	assert.InstanceOf[*dgraph.ErrNodeResolutionAlreadySet](t, a.CompleteIteration())
+
+	assert.NoError(t, a.DisconnectInbound("a"))
+	assert.Equals(t, a.HasSelfLoop(), false)
+}
@@ -0,0 +1,61 @@
+package dgraph
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// StaleState describes why a node returned by ListStale is stuck.
+type StaleState string
+
+const (
+	// StaleReady means the node is ready, but has not been popped.
+	StaleReady StaleState = "ready"
+	// StaleBlocked means the node is waiting for its dependencies to resolve.
+	StaleBlocked StaleState = "blocked"
+)
+
+// StaleNode is a node that has been in the same state for longer than the threshold passed to ListStale.
+type StaleNode struct {
+	NodeID string
+	State  StaleState
+	// Since is the time at which the node entered the state.
+	Since time.Time
+	// Duration is the time the node has spent in the state.
+	Duration time.Duration
+}
+
+func (d *directedGraph[NodeType]) ListStale(threshold time.Duration) []StaleNode {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	now := d.config.clock()
+	var result []StaleNode
+	for nodeID, n := range d.nodes {
+		var state StaleState
+		var since time.Time
+		switch {
+		case d.isPendingReady(nodeID):
+			state = StaleReady
+			since = n.readyAt
+		case d.started && !n.ready && n.status == Waiting:
+			state = StaleBlocked
+			since = n.addedAt
+			if since.Before(d.startedAt) {
+				since = d.startedAt
+			}
+		default:
+			continue
+		}
+		if duration := now.Sub(since); duration > threshold {
+			result = append(result, StaleNode{nodeID, state, since, duration})
+		}
+	}
+	slices.SortFunc(result, func(a, b StaleNode) int {
+		if c := cmp.Compare(b.Duration, a.Duration); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+	return result
+}
@@ -0,0 +1,57 @@
+package dgraph_test
+
+import (
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+// fakeClock is a manually advanced clock for testing timestamps.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(duration time.Duration) {
+	c.now = c.now.Add(duration)
+}
+
+func TestDirectedGraph_ListStale(t *testing.T) {
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := dgraph.New[string](dgraph.WithClock(clock.Now))
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.AndDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.AndDependency))
+	clock.Advance(time.Hour)
+	// Nothing is stale before the graph is started.
+	assert.Equals(t, len(d.ListStale(time.Minute)), 0)
+
+	assert.NoError(t, d.PushStartingNodes())
+	clock.Advance(5 * time.Minute)
+	assert.Equals(t, d.PopReadyNodesOrdered(), []string{"a", "b"})
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, b.ResolveNode(dgraph.Resolved))
+	assert.Equals(t, len(d.ListStale(10*time.Minute)), 0)
+	clock.Advance(10 * time.Minute)
+	assert.Equals(t, d.ListStale(9*time.Minute), []dgraph.StaleNode{
+		{"c", dgraph.StaleReady, clock.now.Add(-10 * time.Minute), 10 * time.Minute},
+	})
+
+	_, err := d.AddNode("d", "d")
+	assert.NoError(t, err)
+	dependent := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("e", "e"))
+	assert.NoError(t, dependent.ConnectDependency("d", dgraph.AndDependency))
+	clock.Advance(30 * time.Minute)
+	assert.Equals(t, d.ListStale(time.Minute), []dgraph.StaleNode{
+		{"c", dgraph.StaleReady, clock.now.Add(-40 * time.Minute), 40 * time.Minute},
+		{"d", dgraph.StaleBlocked, clock.now.Add(-30 * time.Minute), 30 * time.Minute},
+		{"e", dgraph.StaleBlocked, clock.now.Add(-30 * time.Minute), 30 * time.Minute},
+	})
+}
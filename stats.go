@@ -0,0 +1,46 @@
+package dgraph
+
+import "maps"
+
+// DependencyTypeStats counts the dependencies of one dependency type by their effect on the dependent nodes.
+type DependencyTypeStats struct {
+	// Satisfied is the number of dependencies whose resolution counted towards the readiness of the dependent node.
+	Satisfied int
+	// Obviated is the number of dependencies that were obviated before they were resolved, for example the other OR
+	// dependencies of a node once one of them is resolved.
+	Obviated int
+	// Failed is the number of unresolvable dependencies that made the dependent node unresolvable.
+	Failed int
+	// Ignored is the number of dependencies whose resolution had no effect on the dependent node.
+	Ignored int
+}
+
+// Stats are the audit counters of a graph, returned by DirectedGraph.Stats.
+type Stats struct {
+	// DependencyTypes holds the counters of each dependency type, keyed by the type the dependency had when it was
+	// counted. Dependency types without any counted dependency are omitted.
+	DependencyTypes map[DependencyType]DependencyTypeStats
+}
+
+func (d *directedGraph[NodeType]) Stats() Stats {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return Stats{maps.Clone(d.dependencyStats)}
+}
+
+// countDependency records the effect of a dependency in the statistics of its dependency type.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) countDependency(dependencyType DependencyType, effect DependencyEffect) {
+	stats := d.dependencyStats[dependencyType]
+	switch effect {
+	case DependencySatisfied:
+		stats.Satisfied++
+	case DependencyObviated:
+		stats.Obviated++
+	case DependencyFailed:
+		stats.Failed++
+	case DependencyIgnored:
+		stats.Ignored++
+	}
+	d.dependencyStats[dependencyType] = stats
+}
@@ -0,0 +1,31 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Stats(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("c", "c"))
+	e := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("e", "e"))
+	assert.NoError(t, c.ConnectDependency(a.ID(), dgraph.OrDependency))
+	assert.NoError(t, c.ConnectDependency(b.ID(), dgraph.OrDependency))
+	assert.NoError(t, e.ConnectDependency(b.ID(), dgraph.AndDependency))
+	assert.NoError(t, e.ConnectDependency(a.ID(), dgraph.OptionalDependency))
+	assert.NoError(t, d.PushStartingNodes())
+
+	assert.NoError(t, a.ResolveNode(dgraph.Resolved))
+	assert.NoError(t, b.ResolveNode(dgraph.Unresolvable))
+	assert.Equals(t, d.Stats(), dgraph.Stats{
+		DependencyTypes: map[dgraph.DependencyType]dgraph.DependencyTypeStats{
+			dgraph.OrDependency:       {Satisfied: 1, Obviated: 1},
+			dgraph.AndDependency:      {Failed: 1},
+			dgraph.OptionalDependency: {Ignored: 1},
+		},
+	})
+}
@@ -0,0 +1,100 @@
+package dgraph
+
+import (
+	"context"
+	"strings"
+)
+
+func (d *directedGraph[NodeType]) Subscribe(ctx context.Context) <-chan Node[NodeType] {
+	return d.subscribe(ctx, "", func(n *node[NodeType]) Node[NodeType] {
+		return n
+	})
+}
+
+// subscribe works like Subscribe, but only delivers the ready nodes whose IDs start with the prefix, converted with
+// the wrap function.
+func (d *directedGraph[NodeType]) subscribe(
+	ctx context.Context,
+	prefix string,
+	wrap func(n *node[NodeType]) Node[NodeType],
+) <-chan Node[NodeType] {
+	result := make(chan Node[NodeType])
+	go func() {
+		defer close(result)
+		for {
+			d.lock.Lock()
+			d.checkDeadline()
+			n := d.popNextReady(prefix)
+			var status ResolutionStatus
+			if n != nil {
+				status = n.status
+			}
+			if n == nil {
+				if d.readyChanged == nil {
+					d.readyChanged = make(chan struct{})
+				}
+				readyChanged := d.readyChanged
+				done := d.done
+				d.unlock()
+				select {
+				case <-readyChanged:
+					continue
+				case <-done:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+			d.unlock()
+			select {
+			case result <- wrap(n):
+			case <-ctx.Done():
+				// Nobody received the node, so return it to the ready set for other subscribers or pollers, unless it
+				// was resolved, cancelled, or removed in the meantime. It goes through pushReady, so that the limit
+				// of ready nodes and the resources are respected.
+				d.lock.Lock()
+				if !n.deleted && n.ready && n.status == status {
+					d.pushReady(n)
+				}
+				d.unlock()
+				return
+			}
+		}
+	}()
+	return result
+}
+
+// popNextReady removes and returns the ready node with the highest priority among the nodes whose IDs start with
+// the prefix, or nil if no such node is ready or the readiness gate is closed.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) popNextReady(prefix string) *node[NodeType] {
+	if d.gateClosed {
+		return nil
+	}
+	var next *node[NodeType]
+	for _, nodeID := range d.readyForProcessing.List() {
+		if !strings.HasPrefix(nodeID, prefix) {
+			continue
+		}
+		if next == nil || d.compareReadyPriority(nodeID, next.id) < 0 {
+			next = d.nodes[nodeID]
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	next.poppedAt = d.config.clock()
+	d.readyForProcessing.Remove(next.id)
+	d.advanceGeneration()
+	d.releaseHeldReady()
+	return next
+}
+
+// notifyReadyChanged wakes up the subscribers waiting for ready nodes.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) notifyReadyChanged() {
+	if d.readyChanged != nil {
+		close(d.readyChanged)
+		d.readyChanged = nil
+	}
+}
@@ -0,0 +1,113 @@
+package dgraph_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Subscribe(t *testing.T) {
+	d := dgraph.New[string]()
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("a", "a"))
+	b := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("b", "b"))
+	assert.NoError(t, b.ConnectDependency(a.ID(), dgraph.AndDependency))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	subscription := d.Subscribe(ctx)
+	assert.NoError(t, d.PushStartingNodes())
+	n := <-subscription
+	assert.Equals(t, n.ID(), "a")
+	assert.NoError(t, n.ResolveNode(dgraph.Resolved))
+	n = <-subscription
+	assert.Equals(t, n.ID(), "b")
+	assert.Equals(t, d.HasReadyNodes(), false)
+
+	cancel()
+	_, ok := <-subscription
+	assert.Equals(t, ok, false)
+}
+
+func TestDirectedGraph_Subscribe_Concurrent(t *testing.T) {
+	const nodeCount = 100
+	d := dgraph.New[int]()
+	end := assert.NoErrorR[dgraph.Node[int]](t)(d.AddNode("end", -1))
+	for i := 0; i < nodeCount; i++ {
+		n := assert.NoErrorR[dgraph.Node[int]](t)(d.AddNode(fmt.Sprintf("node-%d", i), i))
+		assert.NoError(t, end.ConnectDependency(n.ID(), dgraph.AndDependency))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lock := sync.Mutex{}
+	delivered := map[string]int{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range d.Subscribe(ctx) {
+				lock.Lock()
+				delivered[n.ID()]++
+				lock.Unlock()
+				assert.NoError(t, n.ResolveNode(dgraph.Resolved))
+				if n.ID() == "end" {
+					cancel()
+				}
+			}
+		}()
+	}
+	assert.NoError(t, d.PushStartingNodes())
+	wg.Wait()
+	assert.Equals(t, len(delivered), nodeCount+1)
+	for nodeID, count := range delivered {
+		if count != 1 {
+			t.Fatalf("node %s was delivered %d times", nodeID, count)
+		}
+	}
+}
+
+func TestDirectedGraph_Subscribe_CancelRequeue(t *testing.T) {
+	d := dgraph.New[string](dgraph.WithMaxReadyNodes(1))
+	for _, id := range []string{"a", "b", "c"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, d.PushStartingNodes())
+	assert.Equals(t, d.Health().QueueDepth, 1)
+	assert.Equals(t, d.Health().HeldBack, 2)
+
+	// subscribe starts a subscriber without receiving from it, and returns once it popped a node, which releases
+	// one of the held back nodes into the ready set.
+	subscribe := func() (context.CancelFunc, <-chan dgraph.Node[string]) {
+		ctx, cancel := context.WithCancel(context.Background())
+		subscription := d.Subscribe(ctx)
+		for d.Health().HeldBack == 2 {
+			time.Sleep(time.Millisecond)
+		}
+		return cancel, subscription
+	}
+
+	// The undelivered node is held back, since the node released in its place fills the ready set.
+	cancel, subscription := subscribe()
+	cancel()
+	_, ok := <-subscription
+	assert.Equals(t, ok, false)
+	assert.Equals(t, d.Health().QueueDepth, 1)
+	assert.Equals(t, d.Health().HeldBack, 2)
+
+	// A node resolved while the subscriber held it is not returned to the ready set.
+	cancel, subscription = subscribe()
+	inFlight := d.Health().InFlight
+	assert.Equals(t, len(inFlight), 1)
+	n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(inFlight[0]))
+	assert.NoError(t, n.ResolveNode(dgraph.Resolved))
+	cancel()
+	<-subscription
+	assert.Equals(t, d.Health().QueueDepth, 1)
+	assert.Equals(t, d.Health().HeldBack, 1)
+}
@@ -0,0 +1,327 @@
+// Package stress provides a concurrency stress-test harness for directed graphs. It drives a graph with concurrent
+// resolvers, mutators, and readers, checks invariants throughout, and reports the violations it finds. Run it with
+// the Go race detector enabled to also catch data races.
+package stress
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.arcalot.io/dgraph"
+)
+
+// Config describes the workload of a stress test run.
+type Config struct {
+	// NewGraph creates the graph under test. If nil, dgraph.New is used.
+	NewGraph func() dgraph.DirectedGraph[int]
+	// Nodes is the number of nodes in the initial graph.
+	Nodes int
+	// MaxDependencies is the maximum number of dependencies of each node.
+	MaxDependencies int
+	// Resolvers is the number of goroutines resolving ready nodes.
+	Resolvers int
+	// Mutators is the number of goroutines adding nodes while the graph is being resolved.
+	Mutators int
+	// Mutations is the number of nodes each mutator adds.
+	Mutations int
+	// Readers is the number of goroutines calling read-only functions while the graph is being resolved.
+	Readers int
+	// UnresolvableRatio is the ratio of ready nodes that are resolved as Unresolvable.
+	UnresolvableRatio float64
+	// StallTimeout is the time without progress after which the run is considered stalled. Defaults to 5 seconds.
+	StallTimeout time.Duration
+	// Seed is the seed for the random workload generation.
+	Seed int64
+}
+
+// Violation is a broken invariant found during a run.
+type Violation struct {
+	NodeID  string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("node %q: %s", v.NodeID, v.Message)
+}
+
+// Report summarizes a stress test run.
+type Report struct {
+	Nodes       int
+	Resolutions int
+	Mutations   int
+	Reads       int
+	Violations  []Violation
+}
+
+var dependencyTypes = []dgraph.DependencyType{
+	dgraph.AndDependency,
+	dgraph.AndDependency,
+	dgraph.OrDependency,
+	dgraph.OrDependency,
+	dgraph.CompletionAndDependency,
+	dgraph.OnUnresolvableDependency,
+	dgraph.OptionalDependency,
+}
+
+type harness struct {
+	config Config
+	graph  dgraph.DirectedGraph[int]
+	work   chan string
+
+	// Serializes the mutators, so that no other mutator reconciles a node before all its dependencies are connected.
+	mutationLock sync.Mutex
+
+	lock        sync.Mutex
+	random      *rand.Rand
+	nodeIDs     []string
+	nodeCount   int
+	inFlight    int
+	popped      map[string]dgraph.ResolutionStatus
+	lastChange  time.Time
+	report      Report
+	mutatorsRun sync.WaitGroup
+}
+
+// Run executes a stress test with the specified configuration until all nodes have been processed, the run stalls,
+// or the context is cancelled.
+func Run(ctx context.Context, config Config) Report {
+	if config.NewGraph == nil {
+		config.NewGraph = func() dgraph.DirectedGraph[int] {
+			return dgraph.New[int]()
+		}
+	}
+	if config.StallTimeout == 0 {
+		config.StallTimeout = 5 * time.Second
+	}
+	h := &harness{
+		config:     config,
+		graph:      config.NewGraph(),
+		work:       make(chan string),
+		random:     rand.New(rand.NewSource(config.Seed)), //nolint:gosec // Reproducibility, not security.
+		popped:     map[string]dgraph.ResolutionStatus{},
+		lastChange: time.Now(),
+	}
+	for i := 0; i < config.Nodes; i++ {
+		h.addNode()
+	}
+	if err := h.graph.PushStartingNodes(); err != nil {
+		h.violation("", "PushStartingNodes failed: %v", err)
+		return h.report
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var workers sync.WaitGroup
+	for i := 0; i < config.Resolvers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			h.resolve()
+		}()
+	}
+	for i := 0; i < config.Readers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			h.read(ctx)
+		}()
+	}
+	for i := 0; i < config.Mutators; i++ {
+		h.mutatorsRun.Add(1)
+		go func() {
+			defer h.mutatorsRun.Done()
+			h.mutate(ctx)
+		}()
+	}
+	h.dispatch(ctx)
+	close(h.work)
+	cancel()
+	h.mutatorsRun.Wait()
+	workers.Wait()
+	h.checkFinalState()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.report.Nodes = h.nodeCount
+	return h.report
+}
+
+// addNode adds a node with random dependencies on existing nodes. Dependencies on nodes that are already resolved
+// are applied using Reconcile.
+func (h *harness) addNode() {
+	h.mutationLock.Lock()
+	defer h.mutationLock.Unlock()
+	h.lock.Lock()
+	nodeID := fmt.Sprintf("node-%d", len(h.nodeIDs))
+	dependencies := map[string]dgraph.DependencyType{}
+	if len(h.nodeIDs) > 0 && h.config.MaxDependencies > 0 {
+		for i := h.random.Intn(h.config.MaxDependencies + 1); i > 0; i-- {
+			dependencyID := h.nodeIDs[h.random.Intn(len(h.nodeIDs))]
+			dependencies[dependencyID] = dependencyTypes[h.random.Intn(len(dependencyTypes))]
+		}
+	}
+	h.nodeIDs = append(h.nodeIDs, nodeID)
+	h.nodeCount++
+	h.lock.Unlock()
+
+	n, err := h.graph.AddNode(nodeID, 0)
+	if err != nil {
+		h.violation(nodeID, "AddNode failed: %v", err)
+		return
+	}
+	for dependencyID, dependencyType := range dependencies {
+		if err := n.ConnectDependency(dependencyID, dependencyType); err != nil {
+			h.violation(nodeID, "ConnectDependency(%q) failed: %v", dependencyID, err)
+		}
+	}
+	if err := h.graph.Reconcile(); err != nil {
+		h.violation(nodeID, "Reconcile failed: %v", err)
+	}
+}
+
+// dispatch pops ready nodes and hands the waiting ones to the resolvers until all nodes are processed.
+func (h *harness) dispatch(ctx context.Context) {
+	mutatorsDone := make(chan struct{})
+	go func() {
+		h.mutatorsRun.Wait()
+		close(mutatorsDone)
+	}()
+	for {
+		// The state is checked before popping, so that the nodes pushed by the last resolutions are still popped.
+		h.lock.Lock()
+		idle := len(h.popped) == h.nodeCount && h.inFlight == 0
+		stalled := time.Since(h.lastChange) > h.config.StallTimeout
+		h.lock.Unlock()
+		mutated := false
+		select {
+		case <-mutatorsDone:
+			mutated = true
+		default:
+		}
+		readyNodes := h.graph.PopReadyNodes()
+		for nodeID, status := range readyNodes {
+			h.checkPopped(nodeID, status)
+			if status == dgraph.Waiting {
+				h.lock.Lock()
+				h.inFlight++
+				h.lock.Unlock()
+				select {
+				case h.work <- nodeID:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		if idle && mutated && len(readyNodes) == 0 {
+			return
+		}
+		if stalled {
+			h.violation("", "stalled; %d of %d nodes were never ready", h.nodeCount-len(h.popped), h.nodeCount)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (h *harness) checkPopped(nodeID string, status dgraph.ResolutionStatus) {
+	h.lock.Lock()
+	previousStatus, alreadyPopped := h.popped[nodeID]
+	h.popped[nodeID] = status
+	h.lastChange = time.Now()
+	h.lock.Unlock()
+	if alreadyPopped && !(previousStatus == dgraph.Unresolvable && status == dgraph.Unresolvable) {
+		h.violation(nodeID, "popped twice; first as %s, then as %s", previousStatus, status)
+	}
+	if status != dgraph.Waiting {
+		return
+	}
+	n, err := h.graph.GetNodeByID(nodeID)
+	if err != nil {
+		h.violation(nodeID, "GetNodeByID of popped node failed: %v", err)
+		return
+	}
+	for dependencyID, dependencyType := range n.OutstandingDependencies() {
+		switch dependencyType {
+		case dgraph.AndDependency, dgraph.CompletionAndDependency, dgraph.OnUnresolvableDependency, dgraph.OrDependency:
+			h.violation(nodeID, "ready with outstanding %s dependency %q", dependencyType, dependencyID)
+		}
+	}
+}
+
+func (h *harness) resolve() {
+	for nodeID := range h.work {
+		h.lock.Lock()
+		status := dgraph.Resolved
+		if h.random.Float64() < h.config.UnresolvableRatio {
+			status = dgraph.Unresolvable
+		}
+		h.lock.Unlock()
+		n, err := h.graph.GetNodeByID(nodeID)
+		if err == nil {
+			err = n.ResolveNode(status)
+		}
+		h.lock.Lock()
+		h.report.Resolutions++
+		h.inFlight--
+		h.lastChange = time.Now()
+		h.lock.Unlock()
+		if err != nil {
+			h.violation(nodeID, "resolving as %s failed: %v", status, err)
+		}
+	}
+}
+
+func (h *harness) mutate(ctx context.Context) {
+	for i := 0; i < h.config.Mutations; i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		h.addNode()
+		h.lock.Lock()
+		h.report.Mutations++
+		h.lock.Unlock()
+	}
+}
+
+func (h *harness) read(ctx context.Context) {
+	for ctx.Err() == nil {
+		for _, n := range h.graph.ListNodes() {
+			n.OutstandingDependencies()
+			n.ResolvedDependencies()
+			if _, err := n.ListInboundConnections(); err != nil {
+				h.violation(n.ID(), "ListInboundConnections failed: %v", err)
+			}
+		}
+		h.graph.HasReadyNodes()
+		h.graph.Mermaid()
+		h.lock.Lock()
+		h.report.Reads++
+		h.lock.Unlock()
+	}
+}
+
+func (h *harness) checkFinalState() {
+	if h.graph.HasReadyNodes() {
+		h.violation("", "ready nodes remain after the run")
+	}
+	for nodeID, n := range h.graph.ListNodes() {
+		for dependencyID, dependencyType := range n.OutstandingDependencies() {
+			if dependencyType != dgraph.ObviatedDependency && dependencyType != dgraph.OptionalDependency {
+				h.violation(nodeID, "outstanding %s dependency %q after the run", dependencyType, dependencyID)
+			}
+		}
+	}
+}
+
+func (h *harness) violation(nodeID string, format string, args ...any) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.report.Violations = append(h.report.Violations, Violation{nodeID, fmt.Sprintf(format, args...)})
+}
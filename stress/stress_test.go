@@ -0,0 +1,30 @@
+package stress_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph/stress"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	report := stress.Run(ctx, stress.Config{
+		Nodes:             100,
+		MaxDependencies:   3,
+		Resolvers:         8,
+		Mutators:          2,
+		Mutations:         25,
+		Readers:           2,
+		UnresolvableRatio: 0.1,
+		Seed:              42,
+	})
+	assert.Equals(t, report.Nodes, 150)
+	assert.Equals(t, report.Mutations, 50)
+	for _, violation := range report.Violations {
+		t.Errorf("%s", violation)
+	}
+}
@@ -0,0 +1,73 @@
+package dgraph
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of similar IDs suggested in an ErrNodeNotFound.
+const maxSuggestions = 3
+
+// nodeNotFound creates an ErrNodeNotFound that suggests existing node IDs similar to the specified one.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) nodeNotFound(nodeID string) *ErrNodeNotFound {
+	candidates := make([]string, 0, len(d.nodes))
+	for existingID := range d.nodes {
+		candidates = append(candidates, existingID)
+	}
+	return &ErrNodeNotFound{nodeID, candidates}
+}
+
+// suggestIDs returns up to maxSuggestions candidates that are similar to the ID, closest first. A candidate is
+// similar if its edit distance to the ID is small relative to the length of the ID, or if one is a dotted prefix
+// of the other.
+func suggestIDs(id string, candidates []string) []string {
+	type suggestion struct {
+		id       string
+		distance int
+	}
+	maxDistance := max(2, len(id)/4)
+	var suggestions []suggestion
+	for _, candidate := range candidates {
+		distance := editDistance(id, candidate)
+		if distance <= maxDistance ||
+			strings.HasPrefix(candidate, id+".") ||
+			strings.HasPrefix(id, candidate+".") {
+			suggestions = append(suggestions, suggestion{candidate, distance})
+		}
+	}
+	slices.SortFunc(suggestions, func(a, b suggestion) int {
+		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.id, b.id)
+	})
+	var result []string
+	for i := 0; i < len(suggestions) && i < maxSuggestions; i++ {
+		result = append(result, suggestions[i].id)
+	}
+	return result
+}
+
+// editDistance returns the Levenshtein distance between the two strings.
+func editDistance(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			substitutionCost := 1
+			if runesA[i-1] == runesB[j-1] {
+				substitutionCost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
@@ -0,0 +1,122 @@
+package dgraph
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// NodeTimings holds the timestamps recorded for a node. Timestamps of events that haven't happened are zero.
+type NodeTimings struct {
+	// AddedAt is the time the node was added to the graph.
+	AddedAt time.Time
+	// ReadyAt is the time the node was marked ready.
+	ReadyAt time.Time
+	// PoppedAt is the time the node was last returned from PopReadyNodes.
+	PoppedAt time.Time
+	// ResolvedAt is the time the resolution status of the node was set.
+	ResolvedAt time.Time
+}
+
+// QueueTime returns the time between the node becoming ready and being resolved. The second return value is false
+// if the node has not been processed by the caller, which is the case if it was not popped before its resolution,
+// for example because it became unresolvable due to a failed dependency.
+func (t NodeTimings) QueueTime() (time.Duration, bool) {
+	if t.ReadyAt.IsZero() || t.ResolvedAt.IsZero() || t.PoppedAt.IsZero() || t.PoppedAt.After(t.ResolvedAt) {
+		return 0, false
+	}
+	return t.ResolvedAt.Sub(t.ReadyAt), true
+}
+
+// DurationStatistics summarizes the queue times of a set of nodes.
+type DurationStatistics struct {
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+}
+
+func (n *node[NodeType]) Timings() NodeTimings {
+	n.dg.lock.RLock()
+	defer n.dg.lock.RUnlock()
+	return n.timings()
+}
+
+// Caller should have appropriate mutex locked before calling.
+func (n *node[NodeType]) timings() NodeTimings {
+	return NodeTimings{
+		AddedAt:    n.addedAt,
+		ReadyAt:    n.readyAt,
+		PoppedAt:   n.poppedAt,
+		ResolvedAt: n.resolvedAt,
+	}
+}
+
+func (d *directedGraph[NodeType]) DurationStatistics(tag func(node Node[NodeType]) string) map[string]DurationStatistics {
+	return d.durationStatistics("", func(n *node[NodeType]) Node[NodeType] {
+		return n
+	}, tag)
+}
+
+// durationStatistics works like DurationStatistics, but only includes the nodes whose IDs start with the prefix,
+// which are converted with the wrap function before they are passed to the tag function.
+func (d *directedGraph[NodeType]) durationStatistics(
+	prefix string,
+	wrap func(n *node[NodeType]) Node[NodeType],
+	tag func(node Node[NodeType]) string,
+) map[string]DurationStatistics {
+	type queuedNode struct {
+		node      *node[NodeType]
+		queueTime time.Duration
+	}
+	d.lock.RLock()
+	queuedNodes := make([]queuedNode, 0, len(d.nodes))
+	for nodeID, n := range d.nodes {
+		if !strings.HasPrefix(nodeID, prefix) {
+			continue
+		}
+		if queueTime, ok := n.timings().QueueTime(); ok {
+			queuedNodes = append(queuedNodes, queuedNode{n, queueTime})
+		}
+	}
+	d.lock.RUnlock()
+
+	// The tag function is called outside the lock, so it can use the node functions.
+	byTag := map[string][]time.Duration{}
+	for _, queued := range queuedNodes {
+		nodeTag := ""
+		if tag != nil {
+			nodeTag = tag(wrap(queued.node))
+		}
+		byTag[nodeTag] = append(byTag[nodeTag], queued.queueTime)
+	}
+	result := make(map[string]DurationStatistics, len(byTag))
+	for nodeTag, durations := range byTag {
+		result[nodeTag] = summarizeDurations(durations)
+	}
+	return result
+}
+
+// summarizeDurations computes the statistics of a non-empty list of durations. Percentiles use the nearest-rank
+// method.
+func summarizeDurations(durations []time.Duration) DurationStatistics {
+	slices.Sort(durations)
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+	percentile := func(p int) time.Duration {
+		rank := (p*len(durations) + 99) / 100
+		return durations[max(rank, 1)-1]
+	}
+	return DurationStatistics{
+		Count: len(durations),
+		Min:   durations[0],
+		Max:   durations[len(durations)-1],
+		Mean:  total / time.Duration(len(durations)),
+		P50:   percentile(50),
+		P95:   percentile(95),
+	}
+}
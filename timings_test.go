@@ -0,0 +1,66 @@
+package dgraph_test
+
+import (
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_DurationStatistics(t *testing.T) {
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := dgraph.New[string](dgraph.WithClock(clock.Now))
+	for _, id := range []string{"fast.1", "fast.2", "slow.1", "slow.2"} {
+		_, err := d.AddNode(id, id[:4])
+		assert.NoError(t, err)
+	}
+	failing := assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode("failing", "fail"))
+	assert.NoError(t, failing.ConnectDependency("slow.2", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+	d.PopReadyNodes()
+	durations := map[string]time.Duration{
+		"fast.1": time.Second,
+		"fast.2": 3 * time.Second,
+		"slow.1": time.Minute,
+		"slow.2": 2 * time.Minute,
+	}
+	for _, id := range []string{"fast.1", "fast.2", "slow.1", "slow.2"} {
+		clock.now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(durations[id])
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(id))
+		status := dgraph.Resolved
+		if id == "slow.2" {
+			status = dgraph.Unresolvable
+		}
+		assert.NoError(t, n.ResolveNode(status))
+	}
+	fast1 := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("fast.1"))
+	queueTime, ok := fast1.Timings().QueueTime()
+	assert.Equals(t, ok, true)
+	assert.Equals(t, queueTime, time.Second)
+	// The failing node was never processed by the caller.
+	_, ok = failing.Timings().QueueTime()
+	assert.Equals(t, ok, false)
+
+	assert.Equals(t, d.DurationStatistics(func(node dgraph.Node[string]) string {
+		return node.Item()
+	}), map[string]dgraph.DurationStatistics{
+		"fast": {
+			Count: 2,
+			Min:   time.Second,
+			Max:   3 * time.Second,
+			Mean:  2 * time.Second,
+			P50:   time.Second,
+			P95:   3 * time.Second,
+		},
+		"slow": {
+			Count: 2,
+			Min:   time.Minute,
+			Max:   2 * time.Minute,
+			Mean:  90 * time.Second,
+			P50:   time.Minute,
+			P95:   2 * time.Minute,
+		},
+	})
+	assert.Equals(t, d.DurationStatistics(nil)[""].Count, 4)
+}
@@ -0,0 +1,102 @@
+package dgraph
+
+import (
+	"container/heap"
+	"slices"
+)
+
+func (d *directedGraph[NodeType]) TopologicalSort() ([]Node[NodeType], error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	order, cycle := d.topologicalOrder()
+	if cycle != nil {
+		return nil, &ErrGraphHasCycles{Cycle: cycle}
+	}
+	result := make([]Node[NodeType], len(order))
+	for i, nodeID := range order {
+		result[i] = d.nodes[nodeID]
+	}
+	return result, nil
+}
+
+// topologicalOrder sorts the node IDs using Kahn's algorithm, always picking the lowest available ID next. If the
+// graph has cycles, the order is nil and one of the cycles is returned instead.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) topologicalOrder() (order []string, cycle []string) {
+	inDegree := make(map[string]int, len(d.nodes))
+	available := &idHeap{}
+	for nodeID := range d.nodes {
+		inDegree[nodeID] = len(d.connectionsToNode[nodeID])
+		if inDegree[nodeID] == 0 {
+			*available = append(*available, nodeID)
+		}
+	}
+	heap.Init(available)
+	order = make([]string, 0, len(d.nodes))
+	for available.Len() > 0 {
+		nodeID := heap.Pop(available).(string)
+		order = append(order, nodeID)
+		for toNodeID := range d.connectionsFromNode[nodeID] {
+			inDegree[toNodeID]--
+			if inDegree[toNodeID] == 0 {
+				heap.Push(available, toNodeID)
+			}
+		}
+	}
+	if len(order) == len(d.nodes) {
+		return order, nil
+	}
+	return nil, d.findCycle(inDegree)
+}
+
+// findCycle finds a cycle among the nodes with a remaining in-degree after Kahn's algorithm. Every such node has an
+// inbound connection from another such node, so walking inbound connections must eventually revisit a node.
+// Caller should have appropriate mutex locked before calling.
+func (d *directedGraph[NodeType]) findCycle(inDegree map[string]int) []string {
+	var remaining []string
+	for nodeID, degree := range inDegree {
+		if degree > 0 {
+			remaining = append(remaining, nodeID)
+		}
+	}
+	slices.Sort(remaining)
+	visitedAt := map[string]int{}
+	var path []string
+	current := remaining[0]
+	for {
+		if i, ok := visitedAt[current]; ok {
+			cycle := path[i:]
+			slices.Reverse(cycle)
+			// Start the cycle at its lowest ID, so the result does not depend on where the walk entered it.
+			start := slices.Index(cycle, slices.Min(cycle))
+			return slices.Concat(cycle[start:], cycle[:start])
+		}
+		visitedAt[current] = len(path)
+		path = append(path, current)
+		var sources []string
+		for fromNodeID := range d.connectionsToNode[current] {
+			if inDegree[fromNodeID] > 0 {
+				sources = append(sources, fromNodeID)
+			}
+		}
+		current = slices.Min(sources)
+	}
+}
+
+// idHeap is a min-heap of node IDs for use with container/heap.
+type idHeap []string
+
+func (h idHeap) Len() int           { return len(h) }
+func (h idHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h idHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *idHeap) Push(x any) {
+	*h = append(*h, x.(string))
+}
+
+func (h *idHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,54 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_TopologicalSort(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"d", "c", "b", "a", "e"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	connect := func(from, to string) {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(to))
+		assert.NoError(t, n.ConnectDependency(from, dgraph.AndDependency))
+	}
+	connect("d", "b")
+	connect("c", "b")
+	connect("b", "a")
+	connect("d", "a")
+
+	nodes := assert.NoErrorR[[]dgraph.Node[string]](t)(d.TopologicalSort())
+	var ids []string
+	for _, n := range nodes {
+		ids = append(ids, n.ID())
+	}
+	assert.Equals(t, ids, []string{"c", "d", "b", "a", "e"})
+}
+
+func TestDirectedGraph_TopologicalSort_Cycle(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	connect := func(from, to string) {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(to))
+		assert.NoError(t, n.ConnectDependency(from, dgraph.AndDependency))
+	}
+	connect("a", "b")
+	connect("b", "c")
+	connect("c", "d")
+	connect("d", "b")
+
+	_, err := d.TopologicalSort()
+	var cycleErr *dgraph.ErrGraphHasCycles
+	assert.Equals(t, errors.As(err, &cycleErr), true)
+	assert.Equals(t, cycleErr.Cycle, []string{"b", "c", "d"})
+	assert.Equals(t, err.Error(), "the graph has cycles: b -> c -> d -> b")
+}
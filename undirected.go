@@ -0,0 +1,105 @@
+package dgraph
+
+import (
+	"slices"
+	"strings"
+)
+
+// UndirectedView is a read-only view of a DirectedGraph that ignores the direction of the connections. The view is
+// live; it reflects changes made to the graph after it was created.
+type UndirectedView[NodeType any] interface {
+	// Neighbors returns all nodes connected to the specified node in either direction. If the node does not
+	// exist, an ErrNodeNotFound is returned.
+	Neighbors(nodeID string) (map[string]Node[NodeType], error)
+	// Degree returns the number of distinct neighbors of the specified node. If the node does not exist, an
+	// ErrNodeNotFound is returned.
+	Degree(nodeID string) (int, error)
+	// Adjacent returns true if the two nodes are connected in either direction.
+	Adjacent(nodeID1, nodeID2 string) bool
+	// ConnectedComponents returns the IDs of the nodes in each connected component. The IDs in each component are
+	// sorted, and the components are sorted by their first ID.
+	ConnectedComponents() [][]string
+}
+
+func (d *directedGraph[NodeType]) UndirectedView() UndirectedView[NodeType] {
+	return &undirectedView[NodeType]{d}
+}
+
+type undirectedView[NodeType any] struct {
+	dg *directedGraph[NodeType]
+}
+
+func (u *undirectedView[NodeType]) Neighbors(nodeID string) (map[string]Node[NodeType], error) {
+	nodeID = u.dg.config.normalizeID(nodeID)
+	u.dg.lock.RLock()
+	defer u.dg.lock.RUnlock()
+	if _, ok := u.dg.nodes[nodeID]; !ok {
+		return nil, u.dg.nodeNotFound(nodeID)
+	}
+	result := map[string]Node[NodeType]{}
+	for neighborID := range u.neighbors(nodeID) {
+		result[neighborID] = u.dg.nodes[neighborID]
+	}
+	return result, nil
+}
+
+func (u *undirectedView[NodeType]) Degree(nodeID string) (int, error) {
+	nodeID = u.dg.config.normalizeID(nodeID)
+	u.dg.lock.RLock()
+	defer u.dg.lock.RUnlock()
+	if _, ok := u.dg.nodes[nodeID]; !ok {
+		return 0, u.dg.nodeNotFound(nodeID)
+	}
+	return len(u.neighbors(nodeID)), nil
+}
+
+func (u *undirectedView[NodeType]) Adjacent(nodeID1, nodeID2 string) bool {
+	nodeID1 = u.dg.config.normalizeID(nodeID1)
+	nodeID2 = u.dg.config.normalizeID(nodeID2)
+	u.dg.lock.RLock()
+	defer u.dg.lock.RUnlock()
+	_, forward := u.dg.connectionsFromNode[nodeID1][nodeID2]
+	_, backward := u.dg.connectionsFromNode[nodeID2][nodeID1]
+	return forward || backward
+}
+
+func (u *undirectedView[NodeType]) ConnectedComponents() [][]string {
+	u.dg.lock.RLock()
+	defer u.dg.lock.RUnlock()
+	visited := make(map[string]struct{}, len(u.dg.nodes))
+	var result [][]string
+	for nodeID := range u.dg.nodes {
+		if _, ok := visited[nodeID]; ok {
+			continue
+		}
+		visited[nodeID] = struct{}{}
+		component := []string{nodeID}
+		for i := 0; i < len(component); i++ {
+			for neighborID := range u.neighbors(component[i]) {
+				if _, ok := visited[neighborID]; !ok {
+					visited[neighborID] = struct{}{}
+					component = append(component, neighborID)
+				}
+			}
+		}
+		slices.Sort(component)
+		result = append(result, component)
+	}
+	slices.SortFunc(result, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return result
+}
+
+// neighbors returns the set of nodes connected to the specified node in either direction.
+// Caller should have appropriate mutex locked before calling.
+func (u *undirectedView[NodeType]) neighbors(nodeID string) map[string]struct{} {
+	result := make(map[string]struct{}, len(u.dg.connectionsFromNode[nodeID])+len(u.dg.connectionsToNode[nodeID]))
+	for neighborID := range u.dg.connectionsFromNode[nodeID] {
+		result[neighborID] = struct{}{}
+	}
+	for neighborID := range u.dg.connectionsToNode[nodeID] {
+		result[neighborID] = struct{}{}
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package dgraph_test
+
+import (
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_UndirectedView(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "x", "y", "lonely"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	a := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("a"))
+	c := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("c"))
+	y := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("y"))
+	assert.NoError(t, a.Connect("b"))
+	assert.NoError(t, c.Connect("b"))
+	assert.NoError(t, y.Connect("x"))
+
+	u := d.UndirectedView()
+	neighbors, err := u.Neighbors("b")
+	assert.NoError(t, err)
+	assert.Equals(t, len(neighbors), 2)
+	assert.MapContainsKey(t, "a", neighbors)
+	assert.MapContainsKey(t, "c", neighbors)
+	degree, err := u.Degree("a")
+	assert.NoError(t, err)
+	assert.Equals(t, degree, 1)
+	assert.Equals(t, u.Adjacent("x", "y"), true)
+	assert.Equals(t, u.Adjacent("a", "c"), false)
+	assert.Equals(t, u.ConnectedComponents(), [][]string{{"a", "b", "c"}, {"lonely"}, {"x", "y"}})
+	_, err = u.Neighbors("missing")
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, err)
+
+	// The view is live.
+	assert.NoError(t, c.Connect("x"))
+	assert.Equals(t, u.ConnectedComponents(), [][]string{{"a", "b", "c", "x", "y"}, {"lonely"}})
+}
@@ -0,0 +1,69 @@
+package dgraph
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrStopWalk can be returned by the visit function of WalkBFS and WalkDFS to end the walk early without an error.
+var ErrStopWalk = errors.New("stop walk")
+
+func (d *directedGraph[NodeType]) WalkBFS(start string, visit func(node Node[NodeType]) error) error {
+	return d.walk(start, visit, false)
+}
+
+func (d *directedGraph[NodeType]) WalkDFS(start string, visit func(node Node[NodeType]) error) error {
+	return d.walk(start, visit, true)
+}
+
+// walk visits the nodes reachable from the start node over outbound connections, depth-first or breadth-first.
+// The lock is only held while looking up the dependents of a node, so the visit function can use the graph.
+func (d *directedGraph[NodeType]) walk(start string, visit func(node Node[NodeType]) error, depthFirst bool) error {
+	start = d.config.normalizeID(start)
+	d.lock.RLock()
+	_, ok := d.nodes[start]
+	d.lock.RUnlock()
+	if !ok {
+		return d.nodeNotFound(start)
+	}
+	visited := map[string]struct{}{}
+	pending := []string{start}
+	for len(pending) > 0 {
+		var nodeID string
+		if depthFirst {
+			nodeID, pending = pending[len(pending)-1], pending[:len(pending)-1]
+		} else {
+			nodeID, pending = pending[0], pending[1:]
+		}
+		if _, ok := visited[nodeID]; ok {
+			continue
+		}
+		visited[nodeID] = struct{}{}
+		d.lock.RLock()
+		n, ok := d.nodes[nodeID]
+		var dependents []string
+		for dependentID := range d.connectionsFromNode[nodeID] {
+			if _, ok := visited[dependentID]; !ok {
+				dependents = append(dependents, dependentID)
+			}
+		}
+		d.lock.RUnlock()
+		if !ok {
+			// The node was removed during the walk.
+			continue
+		}
+		if err := visit(n); err != nil {
+			if errors.Is(err, ErrStopWalk) {
+				return nil
+			}
+			return err
+		}
+		// Dependents are visited in the order of their IDs, so they are pushed in reverse for depth-first walks.
+		slices.Sort(dependents)
+		if depthFirst {
+			slices.Reverse(dependents)
+		}
+		pending = append(pending, dependents...)
+	}
+	return nil
+}
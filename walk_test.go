@@ -0,0 +1,43 @@
+package dgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+)
+
+func TestDirectedGraph_Walk(t *testing.T) {
+	// a -> b -> d, a -> c -> d, d -> e
+	d := dgraph.New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	for _, connection := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}} {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(connection[0]))
+		assert.NoError(t, n.Connect(connection[1]))
+	}
+	walk := func(walker func(string, func(dgraph.Node[string]) error) error, stopAt string) []string {
+		var visited []string
+		assert.NoError(t, walker("a", func(node dgraph.Node[string]) error {
+			visited = append(visited, node.ID())
+			// The lock is not held while visiting.
+			_, err := node.ListOutboundConnections()
+			assert.NoError(t, err)
+			if node.ID() == stopAt {
+				return dgraph.ErrStopWalk
+			}
+			return nil
+		}))
+		return visited
+	}
+	assert.Equals(t, walk(d.WalkBFS, ""), []string{"a", "b", "c", "d", "e"})
+	assert.Equals(t, walk(d.WalkDFS, ""), []string{"a", "b", "d", "e", "c"})
+	assert.Equals(t, walk(d.WalkDFS, "d"), []string{"a", "b", "d"})
+
+	failure := errors.New("failure")
+	assert.Equals(t, d.WalkBFS("a", func(dgraph.Node[string]) error { return failure }), failure)
+	assert.InstanceOf[*dgraph.ErrNodeNotFound](t, d.WalkDFS("f", nil))
+}
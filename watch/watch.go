@@ -0,0 +1,184 @@
+// Package watch renders a compact, periodically refreshed terminal view of the execution progress of a directed
+// graph: a progress bar per stage, the number of nodes in each state, and the most recent unresolvable nodes.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.arcalot.io/dgraph"
+)
+
+// Options configures a Watcher. The zero value uses the defaults.
+type Options struct {
+	// Interval is the time between two renders. Defaults to 500ms.
+	Interval time.Duration
+	// Stage maps a node ID to the stage the node is counted in. Defaults to DefaultStage.
+	Stage func(nodeID string) string
+	// BarWidth is the width of the progress bars in characters. Defaults to 20.
+	BarWidth int
+	// RecentUnresolvable is the number of recently unresolvable nodes shown. Defaults to 5.
+	RecentUnresolvable int
+	// ClearScreen clears the terminal before each render, so the view is updated in place.
+	ClearScreen bool
+}
+
+// DefaultStage groups nodes by the first two dot-separated segments of their ID, so that "steps.example.running"
+// and "steps.example.outputs" are both counted in the "steps.example" stage.
+func DefaultStage(nodeID string) string {
+	parts := strings.SplitN(nodeID, ".", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
+
+// Watcher tracks the progress of a directed graph between renders. It is safe for concurrent use.
+type Watcher[NodeType any] struct {
+	dg      dgraph.DirectedGraph[NodeType]
+	options Options
+	// lock guards seen and recent.
+	lock   sync.Mutex
+	seen   map[string]struct{}
+	recent []string
+}
+
+// New creates a Watcher for the specified graph.
+func New[NodeType any](d dgraph.DirectedGraph[NodeType], options Options) *Watcher[NodeType] {
+	if options.Interval <= 0 {
+		options.Interval = 500 * time.Millisecond
+	}
+	if options.Stage == nil {
+		options.Stage = DefaultStage
+	}
+	if options.BarWidth <= 0 {
+		options.BarWidth = 20
+	}
+	if options.RecentUnresolvable <= 0 {
+		options.RecentUnresolvable = 5
+	}
+	return &Watcher[NodeType]{
+		dg:      d,
+		options: options,
+		seen:    map[string]struct{}{},
+	}
+}
+
+type stageProgress struct {
+	done  int
+	total int
+}
+
+// Render returns the current view of the graph, and whether all nodes have reached a final status. The view is
+// built from a single snapshot of the node states, so the counts are consistent with each other.
+func (w *Watcher[NodeType]) Render() (string, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	stages := map[string]*stageProgress{}
+	var ready, waiting, resolved, unresolvable, cancelled int
+	states := w.dg.NodeStates()
+	nodeIDs := make([]string, 0, len(states))
+	for nodeID := range states {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	slices.Sort(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		state := states[nodeID]
+		stage := w.options.Stage(nodeID)
+		progress, ok := stages[stage]
+		if !ok {
+			progress = &stageProgress{}
+			stages[stage] = progress
+		}
+		progress.total++
+		switch state.Status {
+		case dgraph.Waiting:
+			if state.Ready {
+				ready++
+			} else {
+				waiting++
+			}
+			continue
+		case dgraph.Resolved:
+			resolved++
+		case dgraph.Cancelled:
+			cancelled++
+		case dgraph.Unresolvable:
+			unresolvable++
+			if _, ok := w.seen[nodeID]; !ok {
+				w.seen[nodeID] = struct{}{}
+				w.recent = append(w.recent, nodeID)
+			}
+		}
+		progress.done++
+	}
+	if len(w.recent) > w.options.RecentUnresolvable {
+		w.recent = w.recent[len(w.recent)-w.options.RecentUnresolvable:]
+	}
+
+	stageNames := make([]string, 0, len(stages))
+	nameWidth := 0
+	for stage := range stages {
+		stageNames = append(stageNames, stage)
+		nameWidth = max(nameWidth, len(stage))
+	}
+	slices.Sort(stageNames)
+	var result strings.Builder
+	for _, stage := range stageNames {
+		progress := stages[stage]
+		filled := progress.done * w.options.BarWidth / progress.total
+		fmt.Fprintf(
+			&result,
+			"%-*s [%s%s] %d/%d\n",
+			nameWidth,
+			stage,
+			strings.Repeat("#", filled),
+			strings.Repeat("-", w.options.BarWidth-filled),
+			progress.done,
+			progress.total,
+		)
+	}
+	fmt.Fprintf(
+		&result,
+		"ready: %d  waiting: %d  resolved: %d  unresolvable: %d",
+		ready, waiting, resolved, unresolvable,
+	)
+	// Cancellations are rare, so they are only shown once there are any.
+	if cancelled > 0 {
+		fmt.Fprintf(&result, "  cancelled: %d", cancelled)
+	}
+	result.WriteString("\n")
+	if len(w.recent) > 0 {
+		fmt.Fprintf(&result, "recent unresolvable: %s\n", strings.Join(w.recent, ", "))
+	}
+	return result.String(), ready+waiting == 0
+}
+
+// Run renders the view to the writer at the configured interval until all nodes have reached a final status or
+// the context is cancelled. The final view is always rendered.
+func (w *Watcher[NodeType]) Run(ctx context.Context, out io.Writer) error {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+	for {
+		view, done := w.Render()
+		if w.options.ClearScreen {
+			view = "\033[H\033[2J" + view
+		}
+		if _, err := io.WriteString(out, view); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
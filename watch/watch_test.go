@@ -0,0 +1,89 @@
+package watch_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.arcalot.io/assert"
+	"go.arcalot.io/dgraph"
+	"go.arcalot.io/dgraph/watch"
+)
+
+func TestWatcher(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"input", "steps.a.run", "steps.a.done", "steps.b.run", "steps.b.done"} {
+		_, err := d.AddNode(id, id)
+		assert.NoError(t, err)
+	}
+	aDone := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("steps.a.done"))
+	bDone := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("steps.b.done"))
+	assert.NoError(t, aDone.ConnectDependency("steps.a.run", dgraph.AndDependency))
+	assert.NoError(t, bDone.ConnectDependency("steps.b.run", dgraph.AndDependency))
+	assert.NoError(t, d.PushStartingNodes())
+
+	w := watch.New(d, watch.Options{BarWidth: 4})
+	for _, id := range []string{"input", "steps.a.run"} {
+		n := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID(id))
+		assert.NoError(t, n.ResolveNode(dgraph.Resolved))
+	}
+	bRun := assert.NoErrorR[dgraph.Node[string]](t)(d.GetNodeByID("steps.b.run"))
+	assert.NoError(t, bRun.ResolveNode(dgraph.Unresolvable))
+	view, done := w.Render()
+	assert.Equals(t, done, false)
+	assert.Equals(t, view, `input   [####] 1/1
+steps.a [##--] 1/2
+steps.b [####] 2/2
+ready: 1  waiting: 0  resolved: 2  unresolvable: 2
+recent unresolvable: steps.b.done, steps.b.run
+`)
+
+	assert.NoError(t, aDone.ResolveNode(dgraph.Resolved))
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, w.Run(ctx, &out))
+	assert.Contains(t, out.String(), "steps.a [####] 2/2")
+}
+
+func TestWatcher_Cancelled(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"steps.a.run", "steps.a.done"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, d.PushStartingNodes())
+	d.CancelAll()
+	view, done := watch.New(d, watch.Options{BarWidth: 4}).Render()
+	assert.Equals(t, done, true)
+	assert.Equals(t, view, `steps.a [####] 2/2
+ready: 0  waiting: 0  resolved: 0  unresolvable: 0  cancelled: 2
+`)
+}
+
+func TestWatcher_Concurrent(t *testing.T) {
+	d := dgraph.New[string]()
+	for _, id := range []string{"steps.a.run", "steps.b.run", "steps.c.run", "steps.d.run"} {
+		assert.NoErrorR[dgraph.Node[string]](t)(d.AddNode(id, id))
+	}
+	assert.NoError(t, d.PushStartingNodes())
+	w := watch.New(d, watch.Options{})
+	var wg sync.WaitGroup
+	for _, n := range d.ListNodes() {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, n.ResolveNode(dgraph.Unresolvable))
+		}()
+		go func() {
+			defer wg.Done()
+			w.Render()
+		}()
+	}
+	wg.Wait()
+	view, done := w.Render()
+	assert.Equals(t, done, true)
+	assert.Contains(t, view, "ready: 0  waiting: 0  resolved: 0  unresolvable: 4\n")
+	assert.Contains(t, view, "recent unresolvable: ")
+}